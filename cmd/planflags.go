@@ -0,0 +1,122 @@
+package cmd
+
+import "steadyq/internal/runner"
+
+// planFlags applies each run flag set on the command line over a loaded
+// plan's config. A new run flag registers here, or --plan rejects it.
+var planFlags = map[string]func(cfg *runner.Config, f runner.Config){
+	// Target and request
+	"url":           func(cfg *runner.Config, f runner.Config) { cfg.URL = f.URL },
+	"method":        func(cfg *runner.Config, f runner.Config) { cfg.Method = f.Method },
+	"body":          func(cfg *runner.Config, f runner.Config) { cfg.Body = f.Body },
+	"body-dir":      func(cfg *runner.Config, f runner.Config) { cfg.BodyDir = f.BodyDir },
+	"header-preset": func(cfg *runner.Config, f runner.Config) { cfg.HeaderPresets = f.HeaderPresets },
+	"protocol":      func(cfg *runner.Config, f runner.Config) { cfg.Protocol = f.Protocol },
+	// Flags add to (and override) the plan's headers, weights, tags and metadata
+	"header":      func(cfg *runner.Config, f runner.Config) { cfg.Headers = mergeKeyValues(cfg.Headers, f.Headers) },
+	"tag":         func(cfg *runner.Config, f runner.Config) { cfg.Tags = mergeKeyValues(cfg.Tags, f.Tags) },
+	"meta":        func(cfg *runner.Config, f runner.Config) { cfg.Metadata = mergeKeyValues(cfg.Metadata, f.Metadata) },
+	"body-weight": mergeBodyWeights,
+
+	// Load shape
+	"rate": func(cfg *runner.Config, f runner.Config) {
+		cfg.Mode = "rps"
+		cfg.TargetRPS = f.TargetRPS
+	},
+	"users": func(cfg *runner.Config, f runner.Config) {
+		cfg.Mode = f.Mode
+		cfg.NumUsers = f.NumUsers
+	},
+	"duration":          func(cfg *runner.Config, f runner.Config) { cfg.SteadyDur = f.SteadyDur },
+	"ramp-up":           func(cfg *runner.Config, f runner.Config) { cfg.RampUp = f.RampUp },
+	"ramp-down":         func(cfg *runner.Config, f runner.Config) { cfg.RampDown = f.RampDown },
+	"max-requests":      func(cfg *runner.Config, f runner.Config) { cfg.MaxRequests = f.MaxRequests },
+	"max-results":       func(cfg *runner.Config, f runner.Config) { cfg.MaxResults = f.MaxResults },
+	"fan-out":           func(cfg *runner.Config, f runner.Config) { cfg.FanOut = f.FanOut },
+	"graceful-stop":     func(cfg *runner.Config, f runner.Config) { cfg.GracefulStop = f.GracefulStop },
+	"iteration-timeout": func(cfg *runner.Config, f runner.Config) { cfg.IterationTimeout = f.IterationTimeout },
+	"stuck-after":       func(cfg *runner.Config, f runner.Config) { cfg.StuckAfter = f.StuckAfter },
+	"pacing":            func(cfg *runner.Config, f runner.Config) { cfg.Pacing = f.Pacing },
+	"pacing-burst":      func(cfg *runner.Config, f runner.Config) { cfg.PacingBurst = f.PacingBurst },
+	"pacing-tick":       func(cfg *runner.Config, f runner.Config) { cfg.PacingTick = f.PacingTick },
+	"seed":              func(cfg *runner.Config, f runner.Config) { cfg.Seed = f.Seed },
+
+	// Timeouts and connections
+	"timeout":         func(cfg *runner.Config, f runner.Config) { cfg.RequestTimeout = f.RequestTimeout },
+	"connect-timeout": func(cfg *runner.Config, f runner.Config) { cfg.ConnectTimeout = f.ConnectTimeout },
+	"tls-timeout":     func(cfg *runner.Config, f runner.Config) { cfg.TLSHandshakeTimeout = f.TLSHandshakeTimeout },
+	"header-timeout":  func(cfg *runner.Config, f runner.Config) { cfg.ResponseHeaderTimeout = f.ResponseHeaderTimeout },
+	"max-conns":       func(cfg *runner.Config, f runner.Config) { cfg.MaxConns = f.MaxConns },
+	"http-engine":     func(cfg *runner.Config, f runner.Config) { cfg.HTTPEngine = f.HTTPEngine },
+	"connect-to":      func(cfg *runner.Config, f runner.Config) { cfg.ConnectTo = f.ConnectTo },
+	"sni":             func(cfg *runner.Config, f runner.Config) { cfg.ServerName = f.ServerName },
+	"pin-dns":         func(cfg *runner.Config, f runner.Config) { cfg.PinDNS = f.PinDNS },
+	"dns-ttl":         func(cfg *runner.Config, f runner.Config) { cfg.DNSTTL = f.DNSTTL },
+	"ca-file":         func(cfg *runner.Config, f runner.Config) { cfg.CAFile = f.CAFile },
+	"insecure":        func(cfg *runner.Config, f runner.Config) { cfg.Insecure = f.Insecure },
+	"no-tls-resume":   func(cfg *runner.Config, f runner.Config) { cfg.NoTLSResume = f.NoTLSResume },
+	"bandwidth":       func(cfg *runner.Config, f runner.Config) { cfg.Bandwidth = f.Bandwidth },
+	"network":         func(cfg *runner.Config, f runner.Config) { cfg.NetworkProfile = f.NetworkProfile },
+	"mirror":          func(cfg *runner.Config, f runner.Config) { cfg.Mirror = f.Mirror },
+
+	// Request behaviour
+	"request-id-header": func(cfg *runner.Config, f runner.Config) { cfg.RequestIDHeader = f.RequestIDHeader },
+	"conditional":       func(cfg *runner.Config, f runner.Config) { cfg.Conditional = f.Conditional },
+	"fingerprints":      func(cfg *runner.Config, f runner.Config) { cfg.Fingerprints = f.Fingerprints },
+	"honor-retry-after": func(cfg *runner.Config, f runner.Config) { cfg.HonorRetryAfter = f.HonorRetryAfter },
+	"success-codes":     func(cfg *runner.Config, f runner.Config) { cfg.SuccessCodes = f.SuccessCodes },
+	"preflight":         func(cfg *runner.Config, f runner.Config) { cfg.Preflight = f.Preflight },
+	"preflight-url":     func(cfg *runner.Config, f runner.Config) { cfg.PreflightURL = f.PreflightURL },
+	"label":             func(cfg *runner.Config, f runner.Config) { cfg.Label = f.Label },
+
+	// Circuit breaker and early stop
+	"breaker-error-rate":   func(cfg *runner.Config, f runner.Config) { cfg.BreakerErrorRate = f.BreakerErrorRate },
+	"breaker-min-requests": func(cfg *runner.Config, f runner.Config) { cfg.BreakerMinRequests = f.BreakerMinRequests },
+	"breaker-window":       func(cfg *runner.Config, f runner.Config) { cfg.BreakerWindow = f.BreakerWindow },
+	"breaker-cooldown":     func(cfg *runner.Config, f runner.Config) { cfg.BreakerCooldown = f.BreakerCooldown },
+	"breaker-probes":       func(cfg *runner.Config, f runner.Config) { cfg.BreakerProbes = f.BreakerProbes },
+	"abort-error-rate":     func(cfg *runner.Config, f runner.Config) { cfg.AbortErrorRate = f.AbortErrorRate },
+	"abort-p99":            func(cfg *runner.Config, f runner.Config) { cfg.AbortP99 = f.AbortP99 },
+	"abort-after":          func(cfg *runner.Config, f runner.Config) { cfg.AbortAfter = f.AbortAfter },
+	"max-duration":         func(cfg *runner.Config, f runner.Config) { cfg.MaxDuration = f.MaxDuration },
+	"threshold":            func(cfg *runner.Config, f runner.Config) { cfg.Thresholds = f.Thresholds },
+	"percentiles":          func(cfg *runner.Config, f runner.Config) { cfg.Percentiles = f.Percentiles },
+
+	// Protocols
+	"read-bytes":    func(cfg *runner.Config, f runner.Config) { cfg.ReadBytes = f.ReadBytes },
+	"read-delim":    func(cfg *runner.Config, f runner.Config) { cfg.ReadDelim = f.ReadDelim },
+	"redis-cmd":     func(cfg *runner.Config, f runner.Config) { cfg.RedisCommands = f.RedisCommands },
+	"kafka-acks":    func(cfg *runner.Config, f runner.Config) { cfg.KafkaAcks = f.KafkaAcks },
+	"kafka-key":     func(cfg *runner.Config, f runner.Config) { cfg.KafkaKey = f.KafkaKey },
+	"sql-arg":       func(cfg *runner.Config, f runner.Config) { cfg.SQLArgs = f.SQLArgs },
+	"sql-max-conns": func(cfg *runner.Config, f runner.Config) { cfg.SQLMaxConns = f.SQLMaxConns },
+
+	// Monitoring and reporting
+	"monitor":           func(cfg *runner.Config, f runner.Config) { cfg.Monitor = f.Monitor },
+	"monitor-interval":  func(cfg *runner.Config, f runner.Config) { cfg.MonitorInterval = f.MonitorInterval },
+	"snapshot-interval": func(cfg *runner.Config, f runner.Config) { cfg.SnapshotInterval = f.SnapshotInterval },
+	"name":              func(cfg *runner.Config, f runner.Config) { cfg.Name = f.Name },
+	"out":               func(cfg *runner.Config, f runner.Config) { cfg.OutPrefix = f.OutPrefix },
+	"out-dir":           func(cfg *runner.Config, f runner.Config) { cfg.OutDir = f.OutDir },
+	"runs-dir":          func(cfg *runner.Config, f runner.Config) { cfg.RunsDir = f.RunsDir },
+	"bundle":            func(cfg *runner.Config, f runner.Config) { cfg.Bundle = f.Bundle },
+	"upload":            func(cfg *runner.Config, f runner.Config) { cfg.UploadTo = f.UploadTo },
+	"history":           func(cfg *runner.Config, f runner.Config) { cfg.History = f.History },
+	"history-remote":    func(cfg *runner.Config, f runner.Config) { cfg.HistoryRemote = f.HistoryRemote },
+}
+
+// planIgnoredFlags are set flags that are not part of a run's config and
+// so combine with --plan without changing it
+var planIgnoredFlags = map[string]bool{
+	"plan": true, "watch": true, "dry-run": true, "welcome": true, "global-bandwidth": true,
+	"config": true, "theme": true, "log-file": true, "log-level": true,
+}
+
+func mergeBodyWeights(cfg *runner.Config, f runner.Config) {
+	if cfg.BodyWeights == nil {
+		cfg.BodyWeights = make(map[string]int)
+	}
+	for name, w := range f.BodyWeights {
+		cfg.BodyWeights[name] = w
+	}
+}
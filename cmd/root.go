@@ -2,23 +2,37 @@ package cmd
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
 	"steadyq/internal/banner"
 	"steadyq/internal/cli"
 	"steadyq/internal/dummy"
+	"steadyq/internal/logging"
+	"steadyq/internal/monitor"
+	"steadyq/internal/plan"
 	"steadyq/internal/runner"
 	"steadyq/internal/tui/app"
+	"steadyq/internal/tui/styles"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 var (
-	cfgFile string
+	cfgFile  string
+	planFile string
+	theme    string
+	logFile  string
+	logLevel string
+	welcome  bool
 
 	// CLI Flags
 	url       string
@@ -32,6 +46,107 @@ var (
 	timeout   int
 	headers   []string
 	outPrefix string
+	outDir    string
+	runsDir   string
+	history   string
+	histShare string
+	runName   string
+	bundle    bool
+	uploadTo  string
+
+	snapshotInterval time.Duration
+
+	// Timeout & Connection Flags
+	connectTimeout time.Duration
+	gracefulStop   time.Duration
+	iterTimeout    time.Duration
+	stuckAfter     time.Duration
+	fanOut         int
+	fingerprints   string
+	conditional    bool
+	requestIDHdr   string
+	maxRequests    int
+	maxResults     int
+	abortErrorRate float64
+	abortP99       time.Duration
+	abortAfter     time.Duration
+	maxDuration    time.Duration
+	thresholds     []string
+	percentiles    []float64
+	headerPresets  []string
+	watch          bool
+	dryRun         int
+	tlsTimeout     time.Duration
+	headerTimeout  time.Duration
+	maxConns       int
+	httpEngine     string
+
+	// Bandwidth Flags
+	bandwidth       string
+	globalBandwidth string
+	networkProfile  string
+	connectTo       string
+	pinDNS          bool
+	mirror          string
+	dnsTTL          time.Duration
+	serverName      string
+	caFile          string
+	insecure        bool
+	noTLSResume     bool
+
+	// Preflight Flags
+	preflight    bool
+	preflightURL string
+
+	// Pacing Flags
+	pacing      string
+	pacingBurst int
+	pacingTick  time.Duration
+
+	// Target Monitoring Flags
+	monitorSource   string
+	monitorInterval time.Duration
+
+	// Success Criteria Flags
+	successCodes string
+
+	// Body Directory Flags
+	bodyDir     string
+	bodyWeights []string
+
+	// Label Flags
+	label string
+	tags  []string
+	meta  []string
+
+	// Reproducibility
+	seed int64
+
+	// Rate Limiting Flags
+	honorRetryAfter bool
+
+	// Circuit Breaker Flags
+	breakerErrorRate   float64
+	breakerMinRequests int
+	breakerWindow      time.Duration
+	breakerCooldown    time.Duration
+	breakerProbes      int
+
+	// Raw Socket Flags
+	protocol  string
+	readBytes int
+	readDelim string
+
+	// Redis Flags
+	redisCmds []string
+
+	// Kafka Flags
+	kafkaAcks string
+	kafkaKey  string
+
+	// SQL Flags
+	sqlArgs     []string
+	sqlMaxConns int
 )
 
 var rootCmd = &cobra.Command{
@@ -44,9 +159,16 @@ It supports two main modes:
 1. TUI Mode (Default): Interactive Terminal UI
 2. CLI Mode (Headless): Run with flags for CI/CD usage`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// If CLI flags are provided, run headless
-		if cmd.Flags().Changed("url") {
-			runHeadless()
+		bps, err := runner.ParseBandwidth(globalBandwidth)
+		if err != nil {
+			fmt.Printf("Error: --global-bandwidth: %v\n", err)
+			os.Exit(1)
+		}
+		runner.SetGlobalBandwidth(bps)
+
+		// If CLI flags or a plan are provided, run headless
+		if cmd.Flags().Changed("url") || planFile != "" {
+			runHeadless(cmd)
 			return
 		}
 
@@ -62,6 +184,10 @@ func Execute() {
 		cmd.Usage()
 	})
 
+	// probe and compare-targets take the same request flags as a headless run
+	probeCmd.Flags().AddFlagSet(rootCmd.Flags())
+	compareTargetsCmd.Flags().AddFlagSet(rootCmd.Flags())
+
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -73,20 +199,109 @@ func init() {
 
 	// dummy command?
 	rootCmd.AddCommand(dummyCmd)
+	rootCmd.AddCommand(replayCmd)
+	rootCmd.AddCommand(mergeCmd)
+	rootCmd.AddCommand(compareCmd)
+	rootCmd.AddCommand(compareTargetsCmd)
+	rootCmd.AddCommand(scheduleCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(probeCmd)
+	rootCmd.AddCommand(selftestCmd)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.steadyq.yaml)")
+	rootCmd.PersistentFlags().StringVar(&theme, "theme", "", "TUI theme: auto, dark, light, mono (default: $STEADYQ_THEME or auto, mono if NO_COLOR is set)")
+	viper.BindPFlag("theme", rootCmd.PersistentFlags().Lookup("theme"))
+	rootCmd.Flags().BoolVar(&welcome, "welcome", false, "Show the first-run demo offer and dashboard tour again (TUI)")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "Append internal diagnostic logs (runner, exports, plans) to this file (default: off)")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "Log level for --log-file: "+strings.Join(logging.Levels, ", "))
 
+	rootCmd.Flags().StringVarP(&planFile, "plan", "P", "", "Run a saved plan (name or path to .json/.yaml, enables CLI mode)")
 	rootCmd.Flags().StringVarP(&url, "url", "u", "", "Target URL (enables CLI mode)")
 	rootCmd.Flags().StringVarP(&method, "method", "X", "GET", "HTTP Method")
 	rootCmd.Flags().StringVarP(&body, "body", "b", "", "Request Body")
+	rootCmd.Flags().StringVar(&bodyDir, "body-dir", "", "Send a random file of this directory as each request body (templated like --body)")
+	rootCmd.Flags().StringArrayVar(&bodyWeights, "body-weight", []string{}, "Weight of a --body-dir file, repeatable (e.g. large.json=5; default 1, 0 excludes)")
 	rootCmd.Flags().IntVarP(&rate, "rate", "r", 10, "Target RPS (Open Loop)")
 	rootCmd.Flags().IntVarP(&users, "users", "U", 0, "Target Users (Closed Loop, overrides rate)")
 	rootCmd.Flags().IntVarP(&duration, "duration", "d", 10, "Duration in seconds")
 	rootCmd.Flags().IntVar(&rampUp, "ramp-up", 0, "Ramp Up duration in seconds")
 	rootCmd.Flags().IntVar(&rampDown, "ramp-down", 0, "Ramp Down duration in seconds")
-	rootCmd.Flags().IntVar(&timeout, "timeout", 10, "Request timeout in seconds")
+	rootCmd.Flags().IntVar(&timeout, "timeout", 10, "Overall request deadline in seconds")
+	rootCmd.Flags().IntVar(&maxRequests, "max-requests", 0, "Stop after sending this many requests (0 = run for the full duration)")
+	rootCmd.Flags().IntVar(&maxResults, "max-results", 0, fmt.Sprintf("Results kept in memory for the summary and exports, the oldest dropped beyond it (default %d)", runner.DefaultMaxResults))
+	rootCmd.Flags().BoolVar(&watch, "watch", false, "Re-run a short validation load (--max-requests, default 20) each time the --plan file changes")
+	rootCmd.Flags().IntVar(&dryRun, "dry-run", 0, "Print rendered requests (templates, data files, headers) without sending anything; --dry-run=N for N (default 3)")
+	rootCmd.Flags().Lookup("dry-run").NoOptDefVal = "3"
+	rootCmd.Flags().Float64Var(&abortErrorRate, "abort-error-rate", 0, "Stop the run when the error rate stays above this percent for --abort-after (0 = off)")
+	rootCmd.Flags().DurationVar(&abortP99, "abort-p99", 0, "Stop the run when P99 stays above this for --abort-after (e.g. 2s, 0 = off)")
+	rootCmd.Flags().DurationVar(&abortAfter, "abort-after", 0, "How long an abort condition must hold before stopping (default 10s)")
+	rootCmd.Flags().DurationVar(&maxDuration, "max-duration", 0, "Hard wall-time cap for the run, whatever the plan says (e.g. 5m, 0 = off)")
+	rootCmd.Flags().StringArrayVar(&thresholds, "threshold", []string{}, "Pass/fail target, repeatable (e.g. p99<500ms, error_rate<1%, rps>=100); exit code 3 when one fails")
+	rootCmd.Flags().Float64SliceVar(&percentiles, "percentiles", nil, "Service time percentiles to show and export (e.g. 50,99,99.9,99.99; default 50,90,95,99)")
+	rootCmd.Flags().Lookup("percentiles").DefValue = "" // Not "[]"; the default is in the usage
+	rootCmd.Flags().StringVar(&requestIDHdr, "request-id-header", "", "Send a unique ID per request in this header (X-Request-ID without a value) and export failed IDs for log correlation")
+	rootCmd.Flags().Lookup("request-id-header").NoOptDefVal = "X-Request-ID"
+	rootCmd.Flags().BoolVar(&conditional, "conditional", false, "Replay ETag/Last-Modified as If-None-Match/If-Modified-Since; 304s count as success and are reported apart")
+	rootCmd.Flags().StringVar(&fingerprints, "fingerprints", "", "Rotate User-Agent/Accept-Language per virtual user: \"builtin\" browser mix or a file (UA [| Accept-Language] per line)")
+	rootCmd.Flags().IntVar(&fanOut, "fan-out", 0, "Users mode: requests each iteration sends concurrently, joined before the think time (default 1)")
+	rootCmd.Flags().DurationVar(&gracefulStop, "graceful-stop", 0, "Users mode: time in-flight iterations get to finish after the end before being cancelled (default 30s)")
+	rootCmd.Flags().DurationVar(&iterTimeout, "iteration-timeout", 0, "Users mode: deadline for each iteration's requests, so a hung request frees its user sooner than --timeout (0 = off)")
+	rootCmd.Flags().DurationVar(&stuckAfter, "stuck-after", 0, "Users mode: count users waiting on a request longer than this as stuck (default 5s)")
+	rootCmd.Flags().DurationVar(&connectTimeout, "connect-timeout", 0, "TCP connect timeout (e.g. 2s, default: request deadline)")
+	rootCmd.Flags().DurationVar(&tlsTimeout, "tls-timeout", 0, "TLS handshake timeout (e.g. 5s, default 10s)")
+	rootCmd.Flags().DurationVar(&headerTimeout, "header-timeout", 0, "Response header timeout (e.g. 5s, default: none)")
+	rootCmd.Flags().StringVar(&mirror, "mirror", "", "Also send every HTTP request to this base URL and compare the two (e.g. https://canary.example.com)")
+	rootCmd.Flags().BoolVar(&pinDNS, "pin-dns", false, "Resolve hosts once at run start and dial the pinned IPs")
+	rootCmd.Flags().DurationVar(&dnsTTL, "dns-ttl", 0, "Re-resolve pinned hosts this often, reporting changed answers (implies --pin-dns)")
+	rootCmd.Flags().StringVar(&connectTo, "connect-to", "", "Dial this host[:port] instead of the URL host, keeping URL, Host header and SNI (e.g. 10.0.0.5 or green-lb:443)")
+	rootCmd.Flags().StringVar(&serverName, "sni", "", "TLS server name (default: Host header if set, else URL host); override Host with -H \"Host: ...\"")
+	rootCmd.Flags().StringVar(&bandwidth, "bandwidth", "", "Egress cap on request bodies, e.g. 50Mbit or 2MB/s (default: unlimited)")
+	rootCmd.Flags().StringVar(&globalBandwidth, "global-bandwidth", "", "Egress cap shared by all concurrent runs (TUI), e.g. 100Mbit")
+	rootCmd.Flags().StringVar(&networkProfile, "network", "", "Emulated client link: "+strings.Join(runner.NetworkProfileNames(), ", ")+", or latency=200ms,jitter=50ms,down=2Mbit,up=512kbit")
+	rootCmd.Flags().StringVar(&caFile, "ca-file", "", "PEM CA bundle to trust in addition to the system roots")
+	rootCmd.Flags().BoolVar(&insecure, "insecure", false, "Skip TLS certificate verification")
+	rootCmd.Flags().BoolVar(&noTLSResume, "no-tls-resume", false, "Full TLS handshake on every new connection (no session resumption)")
+	rootCmd.Flags().IntVar(&maxConns, "max-conns", 0, "Max connections per host (default 2000)")
+	rootCmd.Flags().StringVar(&httpEngine, "http-engine", "", "HTTP client library: "+strings.Join(runner.HTTPEngines, ", ")+" (fasthttp: faster above ~50k RPS, HTTP/1.1 only, fewer features)")
 	rootCmd.Flags().StringSliceVarP(&headers, "header", "H", []string{}, "HTTP Header (e.g. \"Key: Value\")")
-	rootCmd.Flags().StringVarP(&outPrefix, "out", "o", "", "Output filename prefix for auto-reporting")
+	rootCmd.Flags().StringSliceVar(&headerPresets, "header-preset", nil, "Apply a named header set from ~/.steadyq/headers.json, repeatable; -H values win")
+	rootCmd.Flags().StringVarP(&outPrefix, "out", "o", "", "Output filename prefix for auto-reporting (template: {{date}}, {{name}}, {{target}})")
+	rootCmd.Flags().StringVar(&outDir, "out-dir", "", "Directory for reports (enables auto-reporting; TUI exports go here too)")
+	rootCmd.Flags().StringVar(&runsDir, "runs-dir", "", "Keep each run in <dir>/<run ID>/ with its config, log and reports (e.g. runs)")
+	rootCmd.Flags().StringVar(&history, "history", "", "Append the run to this JSON Lines history file, asking for a note and verdict in a terminal (e.g. "+cli.DefaultHistoryFile+")")
+	rootCmd.Flags().StringVar(&histShare, "history-remote", "", "Also push the --history entry to a team history: https://host/path (token in $"+cli.HistoryTokenEnv+"), s3://bucket/prefix or gs://bucket/prefix")
+	rootCmd.Flags().StringVar(&runName, "name", "", "Run name for the {{name}} placeholder (default: plan name or \"steadyq\")")
+	rootCmd.Flags().StringVar(&uploadTo, "upload", "", "Upload reports after the run to s3://bucket/prefix or gs://bucket/prefix (needs --out; uses aws/gsutil CLI)")
+	rootCmd.Flags().DurationVar(&snapshotInterval, "snapshot-interval", time.Second, "Store an interval histogram snapshot this often (reports: _intervals.json)")
+	rootCmd.Flags().BoolVar(&bundle, "bundle", false, "Also write <out>.zip with CSV, JSON, summary, timeline, HDR histograms and plan")
+
+	rootCmd.Flags().BoolVar(&preflight, "preflight", false, "Send one request before the run and abort if it fails")
+	rootCmd.Flags().StringVar(&preflightURL, "preflight-url", "", "Probe this URL (GET) as the preflight instead of the configured request (implies --preflight)")
+	rootCmd.Flags().StringVar(&pacing, "pacing", "constant", "Open-loop pacing: constant, poisson, batched, token-bucket")
+	rootCmd.Flags().IntVar(&pacingBurst, "pacing-burst", 10, "token-bucket pacing: max catch-up burst")
+	rootCmd.Flags().DurationVar(&pacingTick, "pacing-tick", 100*time.Millisecond, "batched pacing: send interval")
+	rootCmd.Flags().StringVar(&monitorSource, "monitor", "", "Scrape target CPU/memory during the run: http://host:9100/metrics (node_exporter) or ssh://user@host")
+	rootCmd.Flags().DurationVar(&monitorInterval, "monitor-interval", 2*time.Second, "Target monitor sampling interval")
+	rootCmd.Flags().StringVar(&label, "label", "", "Request label in results and exports (default \"SteadyQ Request\")")
+	rootCmd.Flags().StringArrayVar(&tags, "tag", []string{}, "Result tag key=value, repeatable (e.g. env=staging)")
+	rootCmd.Flags().StringArrayVar(&meta, "meta", []string{}, "Run metadata key=value, repeatable (recorded with host and git SHA in summaries)")
+	rootCmd.Flags().Int64Var(&seed, "seed", 0, "Seed all randomness (templates, Redis mix, Poisson pacing, IDs) to reproduce a run (0 = new seed, printed in the summary)")
+	rootCmd.Flags().StringVar(&successCodes, "success-codes", "", "Status codes counted as success, e.g. 200,202,404 or 200-299,409 (default: 2xx)")
+	rootCmd.Flags().BoolVar(&honorRetryAfter, "honor-retry-after", false, "Back off on 429/503 Retry-After (pause user, or shed open-loop load)")
+	rootCmd.Flags().Float64Var(&breakerErrorRate, "breaker-error-rate", 0, "Client circuit breaker: error ratio (0-1) that opens the circuit (0 = disabled)")
+	rootCmd.Flags().IntVar(&breakerMinRequests, "breaker-min-requests", 20, "Client circuit breaker: minimum requests in window before tripping")
+	rootCmd.Flags().DurationVar(&breakerWindow, "breaker-window", 10*time.Second, "Client circuit breaker: rolling error-rate window")
+	rootCmd.Flags().DurationVar(&breakerCooldown, "breaker-cooldown", 5*time.Second, "Client circuit breaker: time open before half-open probes")
+	rootCmd.Flags().IntVar(&breakerProbes, "breaker-probes", 1, "Client circuit breaker: successful probes needed to close")
+
+	rootCmd.Flags().StringVar(&protocol, "protocol", "", "Protocol: http, tcp, udp, redis, kafka, sql (default: inferred from URL scheme)")
+	rootCmd.Flags().IntVar(&readBytes, "read-bytes", 0, "TCP/UDP: wait for N response bytes")
+	rootCmd.Flags().StringVar(&readDelim, "read-delim", "", "TCP/UDP: wait until delimiter is received (e.g. \"\\n\")")
+	rootCmd.Flags().StringArrayVar(&redisCmds, "redis-cmd", []string{}, "Redis: weighted command, repeatable (e.g. \"80:GET key:{{randomInt 1 100}}\")")
+	rootCmd.Flags().StringVar(&kafkaAcks, "kafka-acks", "all", "Kafka: required acks (all, 1, 0)")
+	rootCmd.Flags().StringVar(&kafkaKey, "kafka-key", "", "Kafka: message key template")
+	rootCmd.Flags().StringArrayVar(&sqlArgs, "sql-arg", []string{}, "SQL: query argument template, repeatable (bound to $1/? in order)")
+	rootCmd.Flags().IntVar(&sqlMaxConns, "sql-max-conns", 0, "SQL: max open connections (0 = unlimited)")
 }
 
 func initConfig() {
@@ -102,11 +317,27 @@ func initConfig() {
 	}
 	viper.AutomaticEnv()
 	viper.ReadInConfig()
+
+	if err := logging.Setup(logFile, logLevel); err != nil {
+		fmt.Printf("Error: --log-file: %v\n", err)
+		os.Exit(1)
+	}
+
+	// A container's CPU quota, not the host's CPU count, is what the
+	// generator can use: more Ps than that only adds throttling
+	if from, to := monitor.AdjustGOMAXPROCS(); from != to {
+		slog.Info("GOMAXPROCS set to the CPU quota", "from", from, "to", to)
+	}
 }
 
 // --- Runners ---
 
 func runTUI() {
+	// 1. Theme (flag > config file > env/NO_COLOR detection)
+	if t := viper.GetString("theme"); t != "" {
+		styles.Apply(t)
+	}
+
 	// 2. Setup Default Runner (Idle)
 	defaultCfg := runner.Config{
 		TargetRPS: 10,
@@ -119,7 +350,12 @@ func runTUI() {
 
 	// 3. Launch TUI Application
 	m := app.NewModel(run, updates)
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	m.OutDir = outDir
+	m.Welcome = welcome || app.FirstRun()
+	if outPrefix != "" {
+		m.ReportTemplate = outPrefix
+	}
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
 
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error running SteadyQ: %v\n", err)
@@ -127,19 +363,139 @@ func runTUI() {
 	}
 }
 
-func runHeadless() {
+func runHeadless(cmd *cobra.Command) {
+	cfg := flagConfig()
+
+	if watch {
+		if planFile == "" {
+			fmt.Printf("Error: --watch needs --plan\n")
+			os.Exit(1)
+		}
+		flagCfg := cfg
+		cli.Watch(plan.Resolve(planFile), func() (runner.Config, error) {
+			cfg, err := loadPlan(cmd, flagCfg)
+			if err != nil {
+				return cfg, err
+			}
+			if cfg.MaxRequests <= 0 {
+				cfg.MaxRequests = cli.DefaultWatchRequests
+			}
+			return cfg, checkConfig(cfg)
+		})
+		return
+	}
+
+	if planFile != "" {
+		cfg = applyPlan(cmd, cfg)
+	}
+
+	validateConfig(cfg)
+	if dryRun > 0 {
+		cli.DryRun(cfg, dryRun)
+		return
+	}
+	cli.Start(cfg)
+}
+
+// flagConfig builds the run config from the command-line flags
+func flagConfig() runner.Config {
 	// Construct config from flags
 	cfg := runner.Config{
-		URL:        url,
-		Method:     method,
-		Body:       body,
-		TargetRPS:  rate,
-		SteadyDur:  duration,
-		RampUp:     rampUp,
-		RampDown:   rampDown,
-		TimeoutSec: timeout,
-		Mode:       "rps",
-		OutPrefix:  outPrefix,
+		URL:       url,
+		Method:    method,
+		Body:      body,
+		TargetRPS: rate,
+		SteadyDur: duration,
+		RampUp:    rampUp,
+		RampDown:  rampDown,
+		Mode:      "rps",
+		OutPrefix: outPrefix,
+		OutDir:    outDir,
+		RunsDir:   runsDir,
+		History:   history,
+		Name:      runName,
+		Bundle:    bundle,
+		UploadTo:  uploadTo,
+
+		HistoryRemote:    histShare,
+		SnapshotInterval: snapshotInterval,
+
+		// Timeouts
+		RequestTimeout:        time.Duration(timeout) * time.Second,
+		ConnectTimeout:        connectTimeout,
+		GracefulStop:          gracefulStop,
+		IterationTimeout:      iterTimeout,
+		StuckAfter:            stuckAfter,
+		FanOut:                fanOut,
+		Fingerprints:          fingerprints,
+		Conditional:           conditional,
+		RequestIDHeader:       requestIDHdr,
+		MaxRequests:           maxRequests,
+		MaxResults:            maxResults,
+		AbortErrorRate:        abortErrorRate,
+		AbortP99:              abortP99,
+		AbortAfter:            abortAfter,
+		MaxDuration:           maxDuration,
+		Thresholds:            thresholds,
+		Percentiles:           percentiles,
+		HeaderPresets:         headerPresets,
+		TLSHandshakeTimeout:   tlsTimeout,
+		ResponseHeaderTimeout: headerTimeout,
+		MaxConns:              maxConns,
+		HTTPEngine:            httpEngine,
+		ConnectTo:             connectTo,
+		PinDNS:                pinDNS,
+		Mirror:                mirror,
+		DNSTTL:                dnsTTL,
+		ServerName:            serverName,
+		CAFile:                caFile,
+		Bandwidth:             bandwidth,
+		NetworkProfile:        networkProfile,
+		Insecure:              insecure,
+		NoTLSResume:           noTLSResume,
+
+		// Preflight
+		Preflight:    preflight,
+		PreflightURL: preflightURL,
+
+		// Pacing
+		Pacing:      pacing,
+		PacingBurst: pacingBurst,
+		PacingTick:  pacingTick,
+
+		// Target Monitoring
+		Monitor:         monitorSource,
+		MonitorInterval: monitorInterval,
+
+		// Success Criteria
+		SuccessCodes: successCodes,
+
+		// Body Directory
+		BodyDir: bodyDir,
+
+		// Labels
+		Label: label,
+		Seed:  seed,
+
+		// Rate Limiting
+		HonorRetryAfter: honorRetryAfter,
+
+		// Circuit Breaker
+		BreakerErrorRate:   breakerErrorRate,
+		BreakerMinRequests: breakerMinRequests,
+		BreakerWindow:      breakerWindow,
+		BreakerCooldown:    breakerCooldown,
+		BreakerProbes:      breakerProbes,
+
+		// Protocols
+		Protocol:      protocol,
+		ReadBytes:     readBytes,
+		ReadDelim:     readDelim,
+		RedisCommands: redisCmds,
+		KafkaAcks:     kafkaAcks,
+		KafkaKey:      kafkaKey,
+		SQLArgs:       sqlArgs,
+		SQLMaxConns:   sqlMaxConns,
 	}
 	if users > 0 {
 		cfg.Mode = "users"
@@ -155,7 +511,557 @@ func runHeadless() {
 		}
 	}
 
-	cli.Start(cfg)
+	cfg.Tags = parseKeyValues("tag", tags)
+	cfg.Metadata = parseKeyValues("meta", meta)
+	for name, w := range parseKeyValues("body-weight", bodyWeights) {
+		weight, err := strconv.Atoi(w)
+		if err != nil || weight < 0 {
+			fmt.Printf("Error: invalid --body-weight %s=%s (expected a non-negative integer)\n", name, w)
+			os.Exit(1)
+		}
+		if cfg.BodyWeights == nil {
+			cfg.BodyWeights = make(map[string]int)
+		}
+		cfg.BodyWeights[name] = weight
+	}
+	return cfg
+}
+
+// validateConfig rejects settings a headless run cannot start with
+func validateConfig(cfg runner.Config) {
+	if err := checkConfig(cfg); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// checkConfig reports the first setting a headless run cannot start with
+func checkConfig(cfg runner.Config) error {
+	if !slices.Contains(runner.HTTPEngines, cfg.GetHTTPEngine()) {
+		return fmt.Errorf("unknown --http-engine %q (use %s)", cfg.HTTPEngine, strings.Join(runner.HTTPEngines, ", "))
+	}
+	if cfg.HTTPEngine == runner.EngineFastHTTP {
+		if cfg.GetProtocol() != "http" || cfg.Command != "" {
+			return fmt.Errorf("--http-engine fasthttp only sends HTTP requests")
+		}
+		if missing := runner.FastHTTPUnsupported(cfg); len(missing) > 0 {
+			return fmt.Errorf("--http-engine fasthttp does not support %s; drop them or use net/http", strings.Join(missing, ", "))
+		}
+	}
+	if !slices.Contains(runner.PacingStrategies, cfg.GetPacing()) {
+		return fmt.Errorf("unknown pacing %q (use %s)", cfg.Pacing, strings.Join(runner.PacingStrategies, ", "))
+	}
+
+	if cfg.FanOut < 0 {
+		return fmt.Errorf("--fan-out cannot be negative")
+	}
+	if cfg.MaxResults < 0 {
+		return fmt.Errorf("--max-results cannot be negative")
+	}
+	if cfg.FanOut > 1 && cfg.Mode != "users" {
+		return fmt.Errorf("--fan-out needs users mode (--users)")
+	}
+	if cfg.IterationTimeout < 0 || cfg.StuckAfter < 0 {
+		return fmt.Errorf("--iteration-timeout and --stuck-after cannot be negative")
+	}
+	if cfg.IterationTimeout > 0 && cfg.Mode != "users" {
+		return fmt.Errorf("--iteration-timeout needs users mode (--users)")
+	}
+
+	if cfg.AbortErrorRate < 0 || cfg.AbortErrorRate > 100 {
+		return fmt.Errorf("--abort-error-rate must be between 0 and 100")
+	}
+	if cfg.AbortP99 < 0 || cfg.AbortAfter < 0 || cfg.MaxDuration < 0 {
+		return fmt.Errorf("--abort-p99, --abort-after and --max-duration cannot be negative")
+	}
+	if cfg.AbortAfter > 0 && cfg.AbortAfter < time.Second {
+		return fmt.Errorf("--abort-after must be at least 1s (conditions are checked every second)")
+	}
+	if cfg.Mirror != "" {
+		if cfg.GetProtocol() != "http" {
+			return fmt.Errorf("--mirror needs an HTTP target")
+		}
+		if _, err := runner.ParseMirror(cfg.Mirror); err != nil {
+			return fmt.Errorf("--mirror: %v", err)
+		}
+	}
+	if cfg.DNSTTL < 0 {
+		return fmt.Errorf("--dns-ttl cannot be negative")
+	}
+	if cfg.HistoryRemote != "" {
+		if cfg.History == "" {
+			return fmt.Errorf("--history-remote shares the --history entry, set --history too")
+		}
+		if err := cli.CheckHistoryRemote(cfg.HistoryRemote); err != nil {
+			return fmt.Errorf("--history-remote: %v", err)
+		}
+	}
+	if _, err := runner.ParseThresholds(cfg.Thresholds); err != nil {
+		return fmt.Errorf("--threshold: %v", err)
+	}
+	if err := runner.CheckPercentiles(cfg.Percentiles); err != nil {
+		return fmt.Errorf("--percentiles: %v", err)
+	}
+	if err := runner.CheckSecrets(cfg); err != nil {
+		return err
+	}
+
+	if _, err := runner.ParseBandwidth(cfg.Bandwidth); err != nil {
+		return fmt.Errorf("--bandwidth: %v", err)
+	}
+	if _, err := runner.ParseNetworkProfile(cfg.NetworkProfile); err != nil {
+		return fmt.Errorf("--network: %v", err)
+	}
+	if _, err := runner.LoadFingerprints(cfg.Fingerprints); err != nil {
+		return fmt.Errorf("--fingerprints: %v", err)
+	}
+	if cfg.BodyDir != "" {
+		if _, err := runner.CheckBodyDir(cfg.BodyDir, cfg.BodyWeights); err != nil {
+			return fmt.Errorf("--body-dir: %v", err)
+		}
+	}
+	if cfg.CAFile != "" && !cfg.Insecure {
+		if _, err := runner.LoadCAFile(cfg.CAFile); err != nil {
+			return fmt.Errorf("--ca-file: %v", err)
+		}
+	}
+	if _, err := runner.ParseSuccessCodes(cfg.SuccessCodes); err != nil {
+		return err
+	}
+	return nil
+}
+
+// applyPlan loads --plan and lets explicitly set flags override its values
+func applyPlan(cmd *cobra.Command, flagCfg runner.Config) runner.Config {
+	cfg, err := loadPlan(cmd, flagCfg)
+	if err != nil {
+		fmt.Printf("Error loading plan: %v\n", err)
+		os.Exit(1)
+	}
+	return cfg
+}
+
+// loadPlan reads --plan and applies the explicitly set flags over it (see
+// planFlags); a set flag the plan cannot take is an error, not ignored
+func loadPlan(cmd *cobra.Command, flagCfg runner.Config) (runner.Config, error) {
+	p, err := plan.Load(planFile)
+	if err != nil {
+		return runner.Config{}, err
+	}
+	cfg := p.Config
+
+	var unsupported []string
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if apply, ok := planFlags[f.Name]; ok {
+			apply(&cfg, flagCfg)
+		} else if !planIgnoredFlags[f.Name] {
+			unsupported = append(unsupported, "--"+f.Name)
+		}
+	})
+	if len(unsupported) > 0 {
+		return runner.Config{}, fmt.Errorf("%s cannot be combined with --plan", strings.Join(unsupported, ", "))
+	}
+	if cfg.Name == "" {
+		cfg.Name = p.Name
+	}
+	return cfg, nil
+}
+
+// parseKeyValues parses repeated key=value flags, nil when none are given
+func parseKeyValues(flag string, values []string) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+	kv := make(map[string]string, len(values))
+	for _, t := range values {
+		k, v, ok := strings.Cut(t, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			fmt.Printf("Error: invalid --%s %q (expected key=value)\n", flag, t)
+			os.Exit(1)
+		}
+		kv[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return kv
+}
+
+// mergeKeyValues returns base with override's entries added or replaced
+func mergeKeyValues(base, override map[string]string) map[string]string {
+	if base == nil {
+		base = make(map[string]string, len(override))
+	}
+	for k, v := range override {
+		base[k] = v
+	}
+	return base
+}
+
+// --- Replay Subcommand ---
+var replayCmd = &cobra.Command{
+	Use:   "replay <bundle.zip|_intervals.json>",
+	Short: "Open a read-only dashboard of a finished run",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if t := viper.GetString("theme"); t != "" {
+			styles.Apply(t)
+		}
+		rp, err := app.LoadReplay(args[0])
+		if err != nil {
+			fmt.Printf("Error loading run: %v\n", err)
+			os.Exit(1)
+		}
+		p := tea.NewProgram(app.NewReplayModel(rp), tea.WithAltScreen(), tea.WithMouseCellMotion())
+		if _, err := p.Run(); err != nil {
+			fmt.Printf("Error running SteadyQ: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// --- Probe Subcommand ---
+var probeCmd = &cobra.Command{
+	Use:   "probe",
+	Short: "Send one configured request and print the full response with a timing breakdown",
+	Example: `  steadyq probe -u https://api.example.com/me -H "Authorization: Bearer {{readFile \"token.txt\"}}"
+  steadyq probe --plan checkout`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := flagConfig()
+		if planFile != "" {
+			cfg = applyPlan(cmd, cfg)
+		}
+		validateConfig(cfg)
+		cli.Probe(cfg)
+	},
+}
+
+// --- Merge Subcommand ---
+var mergeOut string
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <bundle.zip|_intervals.json>...",
+	Short: "Combine the results of generators that ran side by side into one report",
+	Example: `  steadyq merge gen1.zip gen2.zip gen3.zip -o combined
+  steadyq merge a_intervals.json b_intervals.json`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := cli.Merge(args, mergeOut); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	mergeCmd.Flags().StringVarP(&mergeOut, "out", "o", "merged", "Prefix of the combined report files")
+}
+
+// --- Compare Subcommand ---
+var compareOut string
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <A bundle.zip|_intervals.json> <B bundle.zip|_intervals.json>",
+	Short: "Test whether finished run B is slower than run A",
+	Example: `  steadyq compare before.zip after.zip
+  steadyq compare runs/01J.../report_intervals.json runs/01K.../report_intervals.json -o release`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		result, err := cli.CompareRuns(args[0], args[1], compareOut)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if result.Worse() {
+			os.Exit(3)
+		}
+	},
+}
+
+func init() {
+	compareCmd.Flags().StringVarP(&compareOut, "out", "o", "", "Also write the comparison to <prefix>_compare.json")
+}
+
+// --- Compare Targets Subcommand ---
+var compareOpts cli.CompareOptions
+
+var compareTargetsCmd = &cobra.Command{
+	Use:   "compare-targets <base URL A> <base URL B>",
+	Short: "Run the same plan against two targets and test whether B is slower than A",
+	Example: `  steadyq compare-targets https://blue.example.com https://green.example.com -u https://api.example.com/search?q=shoes -r 100 -d 120
+  steadyq compare-targets http://stable:8080 http://canary:8080 --plan checkout --rounds 4 -o canary`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, base := range args {
+			if _, err := runner.ParseMirror(base); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		cfg := flagConfig()
+		if planFile != "" {
+			cfg = applyPlan(cmd, cfg)
+		}
+		if cfg.URL == "" {
+			cfg.URL = args[0] // Only the path and query of the URL are used
+		}
+		validateConfig(cfg)
+		if cfg.GetProtocol() != "http" {
+			fmt.Printf("Error: compare-targets needs an HTTP target\n")
+			os.Exit(1)
+		}
+
+		result, err := cli.CompareTargets(cfg, [2]string{args[0], args[1]}, compareOpts)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if result.Worse() {
+			os.Exit(3)
+		}
+	},
+}
+
+func init() {
+	compareTargetsCmd.Flags().IntVar(&compareOpts.Rounds, "rounds", 1, "Alternate the targets this many times, splitting the steady duration (1 = A then B)")
+}
+
+// --- Schedule Subcommand ---
+var (
+	scheduleOpts    cli.ScheduleOptions
+	scheduleOutDir  string
+	scheduleRunsDir string
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule <plan>",
+	Short: "Run a plan repeatedly on a cron expression or interval, appending results to a history file",
+	Example: `  steadyq schedule checkout --every 15m
+  steadyq schedule ./plans/nightly.json --cron "0 2 * * *" --out-dir reports`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := cli.CheckSchedule(scheduleOpts); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		p, err := plan.Load(args[0])
+		if err != nil {
+			fmt.Printf("Error loading plan: %v\n", err)
+			os.Exit(1)
+		}
+		cfg := p.Config
+		if cmd.Flags().Changed("out-dir") {
+			cfg.OutDir = scheduleOutDir
+		}
+		if cmd.Flags().Changed("runs-dir") {
+			cfg.RunsDir = scheduleRunsDir
+		}
+		validateConfig(cfg)
+
+		if err := cli.Schedule(cfg, scheduleOpts); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	scheduleCmd.Flags().DurationVar(&scheduleOpts.Every, "every", 0, "Start a run every interval (e.g. 15m), the first immediately")
+	scheduleCmd.Flags().StringVar(&scheduleOpts.Cron, "cron", "", "Start runs on a cron expression: minute hour day month weekday (e.g. \"*/15 * * * *\", @hourly)")
+	scheduleCmd.Flags().IntVar(&scheduleOpts.Count, "count", 0, "Stop after this many runs (0 = until Ctrl+C)")
+	scheduleCmd.Flags().StringVar(&scheduleOpts.History, "history", cli.DefaultHistoryFile, "JSON Lines file each run's results are appended to")
+	scheduleCmd.Flags().StringVar(&scheduleOpts.Remote, "history-remote", "", "Also push each run to a team history: https://host/path, s3://bucket/prefix or gs://bucket/prefix")
+	scheduleCmd.Flags().StringVar(&scheduleOutDir, "out-dir", "", "Write each run's reports here (timestamped), overriding the plan")
+	scheduleCmd.Flags().StringVar(&scheduleRunsDir, "runs-dir", "", "Keep each run in <dir>/<run ID>/ (config, log, reports), referenced from the history")
+}
+
+// --- History Subcommands ---
+var (
+	historyFile     string
+	historyRunsDir  string
+	historyBefore   string
+	historyWithRuns bool
+	historyLast     int
+)
+
+// historySelection picks the runs named in ids and, with --before, every
+// run started before that date (2006-01-02 or RFC 3339)
+func historySelection(ids []string) (cli.HistorySelection, error) {
+	sel := cli.HistorySelection{RunIDs: ids}
+	if historyBefore == "" {
+		return sel, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", historyBefore, time.Local)
+	if err != nil {
+		if t, err = time.Parse(time.RFC3339, historyBefore); err != nil {
+			return sel, fmt.Errorf("--before %q: want a date (2006-01-02) or RFC 3339 time", historyBefore)
+		}
+	}
+	sel.Before = t
+	return sel, nil
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Track trends in the run history, share it between machines or through a team backend, and prune it",
+}
+
+var historyExportCmd = &cobra.Command{
+	Use:   "export <file.tar.gz>",
+	Short: "Pack the history file and the run directories it references into an archive",
+	Example: `  steadyq history export results-alice.tar.gz
+  steadyq history export nightly.tar.gz --history nightly.jsonl`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		n, err := cli.ExportHistory(historyFile, args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("📦 %d runs of %s exported to %s\n", n, historyFile, args[0])
+	},
+}
+
+var historyImportCmd = &cobra.Command{
+	Use:   "import <file.tar.gz>",
+	Short: "Merge a colleague's exported history into the local one, skipping runs already there",
+	Example: `  steadyq history import results-alice.tar.gz
+  steadyq history import nightly.tar.gz --history nightly.jsonl --runs-dir runs`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		added, skipped, err := cli.ImportHistory(args[0], historyFile, historyRunsDir)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("📥 %d runs imported into %s (%d already there)\n", added, historyFile, skipped)
+	},
+}
+
+var historyPushCmd = &cobra.Command{
+	Use:   "push <remote>",
+	Short: "Share the local history with a team backend (https://, s3:// or gs://)",
+	Example: `  STEADYQ_HISTORY_TOKEN=... steadyq history push https://perf.example.com/api/runs
+  steadyq history push s3://perf-results/steadyq/history`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		n, err := cli.PushHistory(historyFile, args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("🌐 %d runs of %s pushed to %s\n", n, historyFile, args[0])
+	},
+}
+
+var historyPullCmd = &cobra.Command{
+	Use:     "pull <remote>",
+	Short:   "Add the team backend's runs missing from the local history",
+	Example: `  steadyq history pull s3://perf-results/steadyq/history`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		added, skipped, err := cli.PullHistory(historyFile, args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("🌐 %d runs pulled into %s (%d already there)\n", added, historyFile, skipped)
+	},
+}
+
+var historyDeleteCmd = &cobra.Command{
+	Use:   "delete [<run ID>...]",
+	Short: "Remove runs from the history, by run ID or --before a date",
+	Example: `  steadyq history delete 01J9ZQ4W8TQ3S6Y0M7D5XK2B1C
+  steadyq history delete --before 2026-01-01 --runs`,
+	Run: func(cmd *cobra.Command, args []string) {
+		sel, err := historySelection(args)
+		if err == nil {
+			var n int
+			if n, err = cli.DeleteHistory(historyFile, sel, historyWithRuns); err == nil {
+				fmt.Printf("🗑  %d runs deleted from %s\n", n, historyFile)
+				return
+			}
+		}
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	},
+}
+
+var historyArchiveCmd = &cobra.Command{
+	Use:   "archive <file.tar.gz> [<run ID>...]",
+	Short: "Move runs out of the history into an archive, by run ID or --before a date",
+	Example: `  steadyq history archive 2025.tar.gz --before 2026-01-01
+  steadyq history import 2025.tar.gz   # brings them back`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		sel, err := historySelection(args[1:])
+		if err == nil {
+			var n int
+			if n, err = cli.ArchiveHistory(historyFile, args[0], sel); err == nil {
+				fmt.Printf("📦 %d runs moved from %s to %s\n", n, historyFile, args[0])
+				return
+			}
+		}
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	},
+}
+
+var historyTrendCmd = &cobra.Command{
+	Use:   "trend [<label or URL>]",
+	Short: "Show p99 and error rate over past runs, per label (or URL for unlabelled runs)",
+	Example: `  steadyq history trend
+  steadyq history trend checkout --last 50`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		filter := ""
+		if len(args) > 0 {
+			filter = args[0]
+		}
+		if err := cli.HistoryTrend(os.Stdout, historyFile, filter, historyLast); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	historyCmd.PersistentFlags().StringVar(&historyFile, "history", cli.DefaultHistoryFile, "Local JSON Lines history file")
+	historyImportCmd.Flags().StringVar(&historyRunsDir, "runs-dir", cli.DefaultRunsDir, "Unpack imported run directories into <dir>/<run ID>/")
+	historyCmd.AddCommand(historyExportCmd)
+	historyCmd.AddCommand(historyImportCmd)
+	historyCmd.AddCommand(historyPushCmd)
+	historyCmd.AddCommand(historyPullCmd)
+
+	for _, c := range []*cobra.Command{historyDeleteCmd, historyArchiveCmd} {
+		c.Flags().StringVar(&historyBefore, "before", "", "Also select every run started before this date (2006-01-02 or RFC 3339)")
+		historyCmd.AddCommand(c)
+	}
+	historyTrendCmd.Flags().IntVar(&historyLast, "last", 20, "Show the newest N runs of each label or URL (0: all)")
+	historyCmd.AddCommand(historyTrendCmd)
+	historyDeleteCmd.Flags().BoolVar(&historyWithRuns, "runs", false, "Also delete the run directories of the deleted runs")
+}
+
+// --- Selftest Subcommand ---
+var selftestOpts cli.SelfTestOptions
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Measure this machine's generator ceiling: max RPS, pacing accuracy and scheduler lag",
+	Example: `  steadyq selftest
+  steadyq selftest --max-rps 20000 --step 5s --pacing poisson
+  steadyq selftest --max-rps 500000 --http-engine fasthttp`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := cli.SelfTest(selftestOpts); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	selftestCmd.Flags().DurationVar(&selftestOpts.Step, "step", 3*time.Second, "Time spent at each rate")
+	selftestCmd.Flags().IntVar(&selftestOpts.MaxRPS, "max-rps", 100000, "Highest rate tried")
+	selftestCmd.Flags().StringVar(&selftestOpts.Pacing, "pacing", "", "Open-loop pacing strategy to test: "+strings.Join(runner.PacingStrategies, ", "))
+	selftestCmd.Flags().StringVar(&selftestOpts.Engine, "http-engine", "", "HTTP client library to test: "+strings.Join(runner.HTTPEngines, ", "))
 }
 
 // --- Dummy Subcommand ---
@@ -164,11 +1070,24 @@ var dummyCmd = &cobra.Command{
 	Short: "Run internal dummy server",
 	Run: func(cmd *cobra.Command, args []string) {
 		port, _ := cmd.Flags().GetInt("port")
-		dummy.Start(dummy.ServerConfig{Port: port})
+		tlsPort, _ := cmd.Flags().GetInt("tls-port")
+		certOut, _ := cmd.Flags().GetString("cert-out")
+		workers, _ := cmd.Flags().GetInt("workers")
+		queue, _ := cmd.Flags().GetInt("queue")
+		workTime, _ := cmd.Flags().GetDuration("work-time")
+		dummy.Start(dummy.ServerConfig{
+			Port: port, TLSPort: tlsPort, CertOut: certOut,
+			Workers: workers, Queue: queue, WorkTime: workTime,
+		})
 		select {}
 	},
 }
 
 func init() {
 	dummyCmd.Flags().IntP("port", "p", 8080, "Port to run dummy server on")
+	dummyCmd.Flags().Int("tls-port", 0, "Also serve HTTPS with HTTP/2 on this port, self-signed for localhost (0 = off)")
+	dummyCmd.Flags().String("cert-out", "", "Write the self-signed certificate PEM here, to pass to --ca-file")
+	dummyCmd.Flags().Int("workers", dummy.DefaultWorkers, "/limited: requests served at once")
+	dummyCmd.Flags().Int("queue", dummy.DefaultQueue, "/limited: requests waiting for a worker before 503s (-1 = no queue)")
+	dummyCmd.Flags().Duration("work-time", dummy.DefaultWorkTime, "/limited: time each request holds a worker")
 }
@@ -1,30 +1,112 @@
 package cli
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"math"
+	"os"
+	"os/signal"
+	"slices"
 	"strings"
 	"sync/atomic"
+	"syscall"
 	"time"
 
+	"steadyq/internal/monitor"
+	"steadyq/internal/report"
 	"steadyq/internal/runner"
+	"steadyq/internal/stats"
 	"steadyq/internal/tui/app"
 )
 
+// interruptDrain is how long in-flight requests get to finish after Ctrl+C
+// before they are cancelled
+const interruptDrain = 5 * time.Second
+
 func Start(cfg runner.Config) {
-	printHeader(cfg)
+	out, err := execute(cfg, newReportSink(cfg))
+	if err != nil {
+		fmt.Printf("   Aborting run. Fix the target or drop --preflight.\n")
+		os.Exit(1)
+	}
+	if cfg.History != "" {
+		recordRun(cfg.History, out)
+	}
+	if out.Interrupted {
+		os.Exit(130)
+	}
+	if out.Runner.AbortReason() != "" {
+		os.Exit(2)
+	}
+	if !out.Summary.ThresholdsPassed() {
+		os.Exit(3)
+	}
+}
+
+// outcome is a finished headless run
+type outcome struct {
+	Runner  *runner.Runner
+	Elapsed time.Duration
+	Summary report.Summary
+	Reports string // Report prefix, "" when no reports were written
+	RunDir  string // Run directory under Config.RunsDir, "" when not kept
+
+	// Stopped by Ctrl+C or SIGTERM; the caller exits with status 130 once it
+	// has recorded the run
+	Interrupted bool
+}
 
-	updates := make(runner.StatsUpdateChan, 100)
-	r := runner.NewRunner(cfg, updates)
+// execute runs cfg headlessly, printing progress; rep prints the summary and
+// writes the reports when the run completes, then the extra sinks run. A
+// failed preflight returns its error without running. Ctrl+C reports the
+// partial run and returns it as Interrupted.
+func execute(cfg runner.Config, rep *reportSink, extra ...runner.ResultSink) (outcome, error) {
+	printHeader(os.Stdout, cfg)
+	if warnings := runner.HostAdvisories(cfg, monitor.ReadHostLimits()); len(warnings) > 0 {
+		for _, warning := range warnings {
+			fmt.Printf("⚠️  %s\n", warning)
+			slog.Warn("host advisory", "warning", warning)
+		}
+		fmt.Println()
+	}
+
+	if cfg.WantsPreflight() {
+		res, err := runner.Preflight(cfg)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return outcome{}, err
+		}
+		fmt.Printf("🩺 Preflight OK (status %d, %s)\n\n", res.Status, res.ServiceTime.Round(time.Millisecond))
+	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	r := runner.NewRunner(cfg, nil)
+	r.AddSink(rep)
+	for _, sink := range extra {
+		r.AddSink(sink)
+	}
+
+	// Ctrl+C / SIGTERM stop the run early but still report what was measured
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
 
 	// Start Runner
-	go r.Run(ctx)
+	if err := r.Start(context.Background()); err != nil {
+		return outcome{}, err
+	}
+	runDone := r.Done()
+
+	// Keyboard commands typed while the run is active (p, +, -, s)
+	commands := stdinCommands()
+	fmt.Printf("%s\n\n", commandHelp)
 
 	// Start Monitor Loop
 	startTime := time.Now()
+	rep.start = startTime
 	ticker := time.NewTicker(200 * time.Millisecond) // Faster updates for progress bar
 	defer ticker.Stop()
 
@@ -32,15 +114,41 @@ func Start(cfg runner.Config) {
 
 	for {
 		select {
-		case <-updates:
-			// Drain updates
+		case line, ok := <-commands:
+			if !ok {
+				commands = nil // stdin closed, no more commands
+				continue
+			}
+			runCommand(r, line, time.Since(startTime))
+		case sig := <-sigCh:
+			rep.stoppedAt = time.Now()
+			fmt.Printf("\n\n⚠️  %s: stopping, waiting up to %s for %d in-flight requests (again to cancel them)\n",
+				sig, interruptDrain, atomic.LoadInt64(&r.Inflight))
+			r.Stop()
+			drain := time.NewTimer(interruptDrain)
+			select {
+			case <-runDone:
+			case <-drain.C:
+				r.Abort()
+				<-runDone
+			case <-sigCh:
+				r.Abort()
+				<-runDone
+			}
+			drain.Stop()
+			return rep.outcome(r), nil
 		case <-ticker.C:
+			// Once the run completes its sinks own the console
+			if !rep.holdConsole() {
+				<-runDone
+				return rep.outcome(r), nil
+			}
 			elapsed := time.Since(startTime)
-			stats := r.Stats
+			requests := atomic.LoadUint64(&r.Stats.Requests)
 			inflight := atomic.LoadInt64(&r.Inflight)
 			rps := 0.0
 			if elapsed.Seconds() > 0 {
-				rps = float64(stats.Requests) / elapsed.Seconds()
+				rps = float64(requests) / elapsed.Seconds()
 			}
 
 			pct := elapsed.Seconds() / totalDuration.Seconds()
@@ -48,41 +156,124 @@ func Start(cfg runner.Config) {
 				pct = 1.0
 			}
 
-			fmt.Printf("\r%s %3.0f%% | %s/%s | Inf: %3d | RPS: %.1f | OK: %d | Err: %d",
+			state := ""
+			if r.Paused() {
+				state = " | PAUSED"
+			}
+			fmt.Printf("\r%s %3.0f%% | %s/%s | Inf: %3d | RPS: %.1f | OK: %d | Err: %d%s",
 				progressBar(pct, 20), pct*100,
 				elapsed.Round(time.Second), totalDuration,
 				inflight,
 				rps,
-				atomic.LoadUint64(&stats.Success),
-				atomic.LoadUint64(&stats.Fail),
+				atomic.LoadUint64(&r.Stats.Success),
+				atomic.LoadUint64(&r.Stats.Fail),
+				state,
 			)
 
-			if elapsed >= totalDuration {
-				if inflight > 0 {
-					fmt.Printf("\r%s %3.0f%% | %s/%s | Draining: %d requests...                ",
-						progressBar(1.0, 20), 100.0,
-						elapsed.Round(time.Second), totalDuration,
-						inflight)
-					continue
-				}
-				cancel()
-				printSummary(r, elapsed)
-				handleAutoReport(r, cfg)
-				return
+			// Request limit or an abort condition ended generation before the duration
+			runFinished := r.State() >= runner.StateDraining
+
+			if (runFinished || elapsed >= totalDuration) && inflight > 0 {
+				fmt.Printf("\r%s %3.0f%% | %s/%s | Draining: %d requests...                ",
+					progressBar(1.0, 20), 100.0,
+					elapsed.Round(time.Second), totalDuration,
+					inflight)
+			}
+			rep.releaseConsole()
+
+			if (runFinished || elapsed >= totalDuration) && inflight == 0 {
+				r.Stop()
+				<-runDone // The sinks have run
+				return rep.outcome(r), nil
 			}
 		}
 	}
 }
 
-func printHeader(cfg runner.Config) {
-	fmt.Printf("\n🚀 STARTING STEADYQ LOAD TEST\n")
-	fmt.Printf("======================================================================\n")
-	fmt.Printf("Target URL : %s\n", cfg.URL)
-	fmt.Printf("Method     : %s\n", cfg.Method)
-	fmt.Printf("RPS / Users: %d / %d\n", cfg.TargetRPS, cfg.NumUsers)
-	fmt.Printf("Duration   : %ds (Steady) + %ds (RampUp) + %ds (RampDown)\n", cfg.SteadyDur, cfg.RampUp, cfg.RampDown)
-	fmt.Printf("Timeout    : %ds\n", cfg.TimeoutSec)
-	fmt.Printf("======================================================================\n\n")
+func printHeader(w io.Writer, cfg runner.Config) {
+	fmt.Fprintf(w, "\n🚀 STARTING STEADYQ LOAD TEST\n")
+	fmt.Fprintf(w, "======================================================================\n")
+	fmt.Fprintf(w, "Target URL : %s\n", cfg.URL)
+	fmt.Fprintf(w, "Method     : %s\n", cfg.Method)
+	fmt.Fprintf(w, "RPS / Users: %d / %d\n", cfg.TargetRPS, cfg.NumUsers)
+	if cfg.Mode != "users" {
+		fmt.Fprintf(w, "Pacing     : %s\n", pacingLabel(cfg))
+	}
+	fmt.Fprintf(w, "Duration   : %ds (Steady) + %ds (RampUp) + %ds (RampDown)\n", cfg.SteadyDur, cfg.RampUp, cfg.RampDown)
+	fmt.Fprintf(w, "Timeout    : %s (Connect: %s)\n", cfg.GetRequestTimeout(), cfg.GetConnectTimeout())
+	if cfg.HTTPEngine == runner.EngineFastHTTP {
+		fmt.Fprintf(w, "Engine     : fasthttp (HTTP/1.1, no connection reuse or TLS details)\n")
+	}
+	if cfg.Mirror != "" {
+		fmt.Fprintf(w, "Mirror     : %s (every request sent there too)\n", cfg.Mirror)
+	}
+	if cfg.PinsDNS() {
+		if cfg.DNSTTL > 0 {
+			fmt.Fprintf(w, "DNS        : pinned, re-resolved every %s\n", cfg.DNSTTL)
+		} else {
+			fmt.Fprintf(w, "DNS        : pinned at run start\n")
+		}
+	}
+	if cfg.MaxRequests > 0 {
+		fmt.Fprintf(w, "Max Reqs   : %d (ends the run early)\n", cfg.MaxRequests)
+	}
+	if cfg.WantsAbortGuard() {
+		fmt.Fprintf(w, "Abort If   : %s\n", abortLabel(cfg))
+	}
+	if ths := cfg.GetThresholds(); len(ths) > 0 {
+		var specs []string
+		for _, t := range ths {
+			specs = append(specs, t.String())
+		}
+		fmt.Fprintf(w, "Thresholds : %s\n", strings.Join(specs, ", "))
+	}
+	if cfg.Label != "" || len(cfg.Tags) > 0 {
+		fmt.Fprintf(w, "Label      : %s %s\n", cfg.GetLabel(), app.FormatTags(cfg.Tags))
+	}
+	if p, _ := runner.ParseNetworkProfile(cfg.NetworkProfile); p != nil {
+		fmt.Fprintf(w, "Network    : %s (%s)\n", cfg.NetworkProfile, p)
+	}
+	if cfg.SuccessCodes != "" {
+		codes, _ := runner.ParseSuccessCodes(cfg.SuccessCodes)
+		fmt.Fprintf(w, "Success    : %s\n", codes)
+	}
+	if h := monitor.ReadHostLimits(); h.CPUQuota > 0 {
+		fmt.Fprintf(w, "Generator  : %.1f CPU quota, GOMAXPROCS %d\n", h.CPUQuota, h.GOMAXPROCS)
+	}
+	fmt.Fprintf(w, "======================================================================\n\n")
+}
+
+// abortLabel lists the configured early-stop conditions
+func abortLabel(cfg runner.Config) string {
+	var conds []string
+	if cfg.AbortErrorRate > 0 {
+		conds = append(conds, fmt.Sprintf("errors > %.1f%%", cfg.AbortErrorRate))
+	}
+	if cfg.AbortP99 > 0 {
+		conds = append(conds, fmt.Sprintf("P99 > %s", cfg.AbortP99))
+	}
+	label := strings.Join(conds, " or ")
+	if label != "" {
+		label += fmt.Sprintf(" for %s", cfg.GetAbortAfter())
+	}
+	if cfg.MaxDuration > 0 {
+		if label != "" {
+			label += ", "
+		}
+		label += fmt.Sprintf("wall time > %s", cfg.MaxDuration)
+	}
+	return label
+}
+
+// pacingLabel describes the open-loop pacing strategy and its parameter
+func pacingLabel(cfg runner.Config) string {
+	switch cfg.GetPacing() {
+	case runner.PacingTokenBucket:
+		return fmt.Sprintf("%s (burst %d)", runner.PacingTokenBucket, cfg.GetPacingBurst())
+	case runner.PacingBatched:
+		return fmt.Sprintf("%s (every %s)", runner.PacingBatched, cfg.GetPacingTick())
+	}
+	return cfg.GetPacing()
 }
 
 func progressBar(pct float64, width int) string {
@@ -96,42 +287,432 @@ func progressBar(pct float64, width int) string {
 	return "[" + strings.Repeat("█", filled) + strings.Repeat("-", width-filled) + "]"
 }
 
-func printSummary(r *runner.Runner, totalTime time.Duration) {
+// printSummary prints sum, the run's other counters and its failure signatures
+func printSummary(w io.Writer, r *runner.Runner, sum report.Summary) {
 	stats := r.Stats
-	rps := float64(stats.Requests) / totalTime.Seconds()
-
-	fmt.Printf("\n\n📊 LOAD TEST RESULTS\n")
-	fmt.Printf("======================================================================\n")
-	fmt.Printf("Total Duration : %s\n", totalTime.Round(time.Second))
-	fmt.Printf("Requests Sent  : %d\n", stats.Requests)
-	fmt.Printf("Success        : %d\n", stats.Success)
-	fmt.Printf("Failures       : %d\n", stats.Fail)
-	fmt.Printf("Actual RPS     : %.2f\n", rps)
-	fmt.Printf("\n⏱️  RESPONSE TIMES (ms) [Success Only]\n")
-	fmt.Printf("   P50 : %.2f\n", stats.GetP50Service())
-	fmt.Printf("   P90 : %.2f\n", stats.GetP90Service())
-	fmt.Printf("   P95 : %.2f\n", stats.GetP95Service())
-	fmt.Printf("   P99 : %.2f\n", stats.GetP99Service())
-	fmt.Printf("   Max : %d\n", stats.ServiceTime.Max()/1000)
+	totalTime := sum.Duration
+
+	fmt.Fprintf(w, "\n\n📊 LOAD TEST RESULTS\n")
+	fmt.Fprintf(w, "======================================================================\n")
+	if reason := r.AbortReason(); reason != "" {
+		fmt.Fprintf(w, "🛑 ABORTED     : %s\n", reason)
+	}
+	fmt.Fprintf(w, "Total Duration : %s\n", totalTime.Round(time.Second))
+	fmt.Fprintf(w, "Generator      : %s (%s/%s, steadyq %s)\n", r.Meta.Hostname, r.Meta.OS, r.Meta.Arch, r.Meta.Version)
+	if r.Meta.GitSHA != "" {
+		fmt.Fprintf(w, "Git SHA        : %s (%s)\n", r.Meta.GitSHA, r.Meta.GitBranch)
+	}
+	fmt.Fprintf(w, "Run ID         : %s\n", r.Meta.RunID)
+	fmt.Fprintf(w, "Seed           : %d (repeat with --seed %d)\n", r.Seed, r.Seed)
+	fmt.Fprintf(w, "Requests Sent  : %d\n", sum.TotalRequests+sum.ResultsDropped)
+	if sum.ResultsDropped > 0 {
+		fmt.Fprintf(w, "   ⚠️  Only the last %d results were kept (%d dropped to bound memory): the figures below and the exports cover those; raise --max-results\n",
+			sum.TotalRequests, sum.ResultsDropped)
+	}
+	fmt.Fprintf(w, "Success        : %d\n", sum.TotalSuccess)
+	fmt.Fprintf(w, "Failures       : %d (%.2f%%)\n", sum.TotalFail, sum.ErrorRate)
+	if r.Cfg.BreakerErrorRate > 0 {
+		fmt.Fprintf(w, "Short-Circuited: %d (breaker open, not sent)\n", atomic.LoadUint64(&stats.ShortCircuited))
+	}
+	if r.Cfg.HonorRetryAfter {
+		if r.Cfg.Mode == "users" {
+			fmt.Fprintf(w, "Retry-After    : %.1fs user time paused\n", float64(atomic.LoadInt64(&stats.RetryAfterPauseMicro))/1e6)
+		} else {
+			fmt.Fprintf(w, "Retry-After    : %d requests shed\n", atomic.LoadUint64(&stats.RetryAfterShed))
+		}
+	}
+	if n := atomic.LoadUint64(&stats.ForceCancelled); n > 0 {
+		fmt.Fprintf(w, "Force-Cancelled: %d (still in flight at the end, not counted)\n", n)
+	}
+	if skipped := atomic.LoadUint64(&stats.SchedulerSkipped); skipped > 0 {
+		intended := atomic.LoadUint64(&stats.Requests) + skipped
+		fmt.Fprintf(w, "Not Sent       : %d of %d intended (%.1f%%) due to generator saturation\n",
+			skipped, intended, float64(skipped)/float64(intended)*100)
+	}
+	if r.DNS != nil {
+		hosts := r.DNS.Hosts()
+		for _, host := range slices.Sorted(maps.Keys(hosts)) {
+			fmt.Fprintf(w, "DNS Pinned     : %s -> %s\n", host, strings.Join(hosts[host], ", "))
+		}
+		if n := r.DNS.Changes(); n > 0 {
+			fmt.Fprintf(w, "DNS Changed    : %d times mid-run (the run log has each answer)\n", n)
+		}
+	}
+	fmt.Fprintf(w, "Actual RPS     : %.2f\n", sum.AverageRPS)
+	if len(sum.StatusCodes) > 0 {
+		var codes []string
+		for _, code := range slices.Sorted(maps.Keys(sum.StatusCodes)) {
+			codes = append(codes, fmt.Sprintf("%d x %d", code, sum.StatusCodes[code]))
+		}
+		fmt.Fprintf(w, "Status Codes   : %s\n", strings.Join(codes, ", "))
+	}
+	fmt.Fprintf(w, "Apdex          : %.3f (T = %s)\n", sum.Apdex, report.ApdexT)
+	fmt.Fprintf(w, "\n⏱️  RESPONSE TIMES (ms) [Success Only]\n")
+	ps := sum.Percentiles
+	if len(ps) == 0 {
+		ps = []report.PercentileValue{{P: 50, Ms: sum.Service.P50}, {P: 90, Ms: sum.Service.P90}, {P: 95, Ms: sum.Service.P95}, {P: 99, Ms: sum.Service.P99}}
+	}
+	width := 6
+	for _, p := range ps {
+		width = max(width, len(fmt.Sprintf("P%g", p.P)))
+	}
+	for _, p := range ps {
+		fmt.Fprintf(w, "   %-*s : %.2f\n", width, fmt.Sprintf("P%g", p.P), p.Ms)
+	}
+	fmt.Fprintf(w, "   %-*s : %.2f\n", width, "Max", sum.Service.Max)
+	fmt.Fprintf(w, "   %-*s : %.2f\n", width, "Min", sum.Service.Min)
+	fmt.Fprintf(w, "   %-*s : %.2f (trimmed %.2f, without the top and bottom %g%%)\n", width, "Mean", sum.Service.Mean, sum.Service.TrimmedMean, report.TrimPercent)
+	fmt.Fprintf(w, "   %-*s : %.2f\n", width, "StdDev", sum.Service.StdDev)
+	for _, warning := range sum.Warnings {
+		fmt.Fprintf(w, "   ⚠️  %s\n", warning)
+	}
+
+	if len(sum.Thresholds) > 0 {
+		fmt.Fprintf(w, "\n🎯 THRESHOLDS\n")
+		for _, t := range sum.Thresholds {
+			mark := "✅"
+			if !t.Pass {
+				mark = "❌"
+			}
+			fmt.Fprintf(w, "   %s %-22s (actual %.2f)\n", mark, t.Threshold, t.Value)
+		}
+	}
+
+	if sum.Conditional > 0 {
+		fmt.Fprintf(w, "\n🗄️  CACHE REVALIDATION (service ms, successful)\n")
+		fmt.Fprintf(w, "   Conditional : %d sent with validators, %d x 304 (%.1f%% hit)\n",
+			sum.Conditional, sum.NotModified, float64(sum.NotModified)/float64(sum.Conditional)*100)
+		fmt.Fprintf(w, "   304         : P50 %.2f | P99 %.2f\n", sum.NotModifiedMs.P50, sum.NotModifiedMs.P99)
+		fmt.Fprintf(w, "   Full        : P50 %.2f | P99 %.2f\n", sum.FullMs.P50, sum.FullMs.P99)
+	}
+
+	if sum.SentBytes+sum.RecvBytes > 0 {
+		sent, recv := stats.GetTransferRates(math.MaxInt)
+		secs := max(sum.Duration.Seconds(), 1)
+		fmt.Fprintf(w, "\n📶 NETWORK (bytes on the wire, heads included)\n")
+		fmt.Fprintf(w, "   Sent     : %-9s avg %s/s, peak %s/s\n", report.FormatBytes(float64(sum.SentBytes)),
+			report.FormatBytes(float64(sum.SentBytes)/secs), report.FormatBytes(float64(slices.Max(append(sent, 0)))))
+		fmt.Fprintf(w, "   Received : %-9s avg %s/s, peak %s/s\n", report.FormatBytes(float64(sum.RecvBytes)),
+			report.FormatBytes(float64(sum.RecvBytes)/secs), report.FormatBytes(float64(slices.Max(append(recv, 0)))))
+	}
+
+	if c := sum.Concurrency; c != nil {
+		fmt.Fprintf(w, "\n🚦 CONCURRENCY (requests in flight)\n")
+		fmt.Fprintf(w, "   Measured     : avg %.1f, peak %d (sampled every 100ms)\n", c.InflightAvg, c.InflightMax)
+		fmt.Fprintf(w, "   Little's Law : L = λW = %.1f req/s × %.2f ms = %.1f\n", c.Throughput, c.MeanServiceMs, c.LittleL)
+	}
+
+	if sum.NewConns+sum.ReusedConns > 0 {
+		fmt.Fprintf(w, "\n🔌 CONNECTIONS (service ms, successful)\n")
+		fmt.Fprintf(w, "   Pooled   : %d of %d requests (%.1f%% reuse), %d connections opened\n",
+			sum.ReusedConns, sum.NewConns+sum.ReusedConns, sum.ReuseRate(), sum.NewConns)
+		fmt.Fprintf(w, "   Connect  : P50 %.2f | P99 %.2f (DNS, dial and TLS of new connections)\n", sum.ConnectMs.P50, sum.ConnectMs.P99)
+		fmt.Fprintf(w, "   New      : P50 %.2f | P99 %.2f\n", sum.NewConnMs.P50, sum.NewConnMs.P99)
+		if sum.ReusedConns > 0 {
+			fmt.Fprintf(w, "   Reused   : P50 %.2f | P99 %.2f\n", sum.ReusedConnMs.P50, sum.ReusedConnMs.P99)
+		}
+		if sum.NewConns > 100 && sum.ReuseRate() < 50 {
+			fmt.Fprintf(w, "   ⚠️  Most requests opened a connection: check the server's keep-alive (Connection: close, idle timeout, max requests per connection)\n")
+		}
+	}
+
+	if len(sum.ByAddr) > 1 {
+		printPeers(w, sum.ByAddr)
+	}
+
+	if len(sum.Mirror) > 0 {
+		printMirror(w, r.Cfg.Mirror, sum.Mirror, r.MirrorDropped())
+	}
+
+	if len(sum.TLSVersions) > 0 {
+		fmt.Fprintf(w, "\n🔒 TLS\n")
+		fmt.Fprintf(w, "   Versions   : %s\n", countList(sum.TLSVersions))
+		fmt.Fprintf(w, "   Ciphers    : %s\n", countList(sum.TLSCiphers))
+		if sum.TLSHandshakes > 0 {
+			fmt.Fprintf(w, "   Handshakes : %d (%.1f%% resumed)\n", sum.TLSHandshakes, float64(sum.TLSResumed)/float64(sum.TLSHandshakes)*100)
+		}
+		if sum.TLSFullMs != nil {
+			fmt.Fprintf(w, "   Full       : P50 %.2f | P99 %.2f ms\n", sum.TLSFullMs.P50, sum.TLSFullMs.P99)
+		}
+		if sum.TLSResumedMs != nil {
+			fmt.Fprintf(w, "   Resumed    : P50 %.2f | P99 %.2f ms\n", sum.TLSResumedMs.P50, sum.TLSResumedMs.P99)
+		}
+	}
+
+	if r.Cfg.Mode == "users" {
+		iters := atomic.LoadUint64(&stats.Iterations)
+		fmt.Fprintf(w, "\n🔁 ITERATIONS (request + think time)\n")
+		fmt.Fprintf(w, "   Completed : %d (%.2f/s)\n", iters, float64(iters)/totalTime.Seconds())
+		fmt.Fprintf(w, "   P50 (ms)  : %.2f\n", float64(stats.IterationTime.ValueAtQuantile(50))/1000)
+		fmt.Fprintf(w, "   P99 (ms)  : %.2f\n", float64(stats.IterationTime.ValueAtQuantile(99))/1000)
+		fmt.Fprintf(w, "   Mean (ms) : %.2f\n", stats.IterationTime.Mean()/1000)
+		if n := r.Cfg.GetFanOut(); n > 1 {
+			fmt.Fprintf(w, "   Fan-out   : %d concurrent requests per iteration, group P50 %.2f ms / P99 %.2f ms\n", n,
+				float64(stats.GroupTime.ValueAtQuantile(50))/1000, float64(stats.GroupTime.ValueAtQuantile(99))/1000)
+		}
+		if d := r.Cfg.IterationTimeout; d > 0 {
+			fmt.Fprintf(w, "   Deadline  : %s per iteration, %d requests cut off\n", d, atomic.LoadUint64(&stats.IterationDeadlines))
+		}
+		fmt.Fprintf(w, "   Stuck     : %d iterations waited > %s on their requests (peak %d users at once)\n",
+			atomic.LoadUint64(&stats.StuckIterations), r.Cfg.GetStuckAfter(), atomic.LoadInt64(&stats.StuckPeak))
+	}
+
+	if r.Self != nil {
+		printGeneratorHealth(w, r.Self.Samples(0))
+	}
+	printExhaustion(w, stats)
+	if n := atomic.LoadUint64(&stats.TLSVerifyFailed); n > 0 {
+		fmt.Fprintf(w, "\n🔒 TLS VERIFICATION FAILED for %d requests (see the failure summary)\n", n)
+		fmt.Fprintf(w, "   → Trust the target's CA with --ca-file, fix --sni, or skip verification with --insecure.\n")
+	}
 
 	errCounts := stats.GetErrorCounts()
 	if len(errCounts) > 0 {
-		fmt.Printf("\n❌ FAILURE SUMMARY\n")
+		fmt.Fprintf(w, "\n❌ FAILURE SUMMARY\n")
 		for errStr, count := range errCounts {
-			fmt.Printf("   %d x %s\n", count, errStr)
+			fmt.Fprintf(w, "   %d x %s\n", count, errStr)
+		}
+	}
+	fmt.Fprintf(w, "======================================================================\n")
+}
+
+// printPeers shows how requests spread across the target's addresses
+func printPeers(w io.Writer, byAddr map[string]report.AddrSummary) {
+	addrs := slices.Collect(maps.Keys(byAddr))
+	slices.SortFunc(addrs, func(a, b string) int {
+		if c := cmp.Compare(byAddr[b].Requests, byAddr[a].Requests); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+	width := 0
+	for _, addr := range addrs {
+		width = max(width, len(addr))
+	}
+	fmt.Fprintf(w, "\n🌐 PEERS (requests per resolved address, end-to-end ms)\n")
+	for _, addr := range addrs {
+		a := byAddr[addr]
+		fmt.Fprintf(w, "   %-*s : %d (%.1f%%) | Fail %d | P50 %.2f | P99 %.2f\n", width, addr, a.Requests, a.Share, a.Fail, a.P50, a.P99)
+	}
+	most, least := byAddr[addrs[0]].Requests, byAddr[addrs[len(addrs)-1]].Requests
+	if float64(most)/float64(least) >= 1.5 {
+		fmt.Fprintf(w, "   ⚠️  Uneven spread: %s got %.1fx the requests of %s\n",
+			addrs[0], float64(most)/float64(least), addrs[len(addrs)-1])
+	}
+}
+
+// printMirror sets each label's results beside its mirrored copies; dropped
+// mirrored results are left out of the comparison
+func printMirror(w io.Writer, base string, byLabel map[string]report.MirrorComparison, dropped uint64) {
+	fmt.Fprintf(w, "\n🪞 MIRROR (vs %s, service ms, successful)\n", base)
+	if dropped > 0 {
+		fmt.Fprintf(w, "   ⚠️  %d earlier mirrored results were dropped to bound memory (--max-results)\n", dropped)
+	}
+	for _, label := range slices.Sorted(maps.Keys(byLabel)) {
+		c := byLabel[label]
+		if len(byLabel) > 1 {
+			fmt.Fprintf(w, "   %s\n", label)
+		}
+		for _, side := range []struct {
+			name string
+			s    report.MirrorSide
+		}{{"Primary", c.Primary}, {"Mirror", c.Mirror}} {
+			fmt.Fprintf(w, "   %-7s : %d req | %.2f%% err | P50 %.2f | P90 %.2f | P99 %.2f\n",
+				side.name, side.s.Requests, side.s.ErrorRate, side.s.P50, side.s.P90, side.s.P99)
 		}
+		if c.Primary.P99 > 0 {
+			fmt.Fprintf(w, "   Delta   : err %+.2f pts | P50 %+.1f%% | P99 %+.1f%%\n",
+				c.Mirror.ErrorRate-c.Primary.ErrorRate, pctChange(c.Primary.P50, c.Mirror.P50), pctChange(c.Primary.P99, c.Mirror.P99))
+		}
+	}
+}
+
+// pctChange is the change from a to b in percent of a
+func pctChange(a, b float64) float64 {
+	if a == 0 {
+		return 0
+	}
+	return (b - a) / a * 100
+}
+
+// countList formats counts by name as "a x 3, b x 1", most frequent first
+func countList(counts map[string]int) string {
+	names := slices.Collect(maps.Keys(counts))
+	slices.SortFunc(names, func(a, b string) int {
+		if c := counts[b] - counts[a]; c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s x %d", name, counts[name])
 	}
-	fmt.Printf("======================================================================\n")
+	return strings.Join(parts, ", ")
 }
 
-func handleAutoReport(r *runner.Runner, cfg runner.Config) {
-	if cfg.OutPrefix == "" || len(r.Results) == 0 {
+// printExhaustion reports requests lost to local port/descriptor exhaustion with a fix
+func printExhaustion(w io.Writer, s *stats.Stats) {
+	ports := atomic.LoadUint64(&s.PortsExhausted)
+	fds := atomic.LoadUint64(&s.FDsExhausted)
+	if ports == 0 && fds == 0 {
 		return
 	}
+	fmt.Fprintf(w, "\n🔌 LOCAL RESOURCE EXHAUSTION (client-side, not server errors)\n")
+	if ports > 0 {
+		fmt.Fprintf(w, "   %d x ephemeral ports exhausted (EADDRNOTAVAIL)\n", ports)
+		fmt.Fprintf(w, "   → %s\n", runner.ExhaustionHint(runner.ExhaustedPorts))
+	}
+	if fds > 0 {
+		fmt.Fprintf(w, "   %d x too many open files (EMFILE)\n", fds)
+		fmt.Fprintf(w, "   → %s\n", runner.ExhaustionHint(runner.ExhaustedFDs))
+	}
+}
+
+// printGeneratorHealth flags seconds where the load generator itself was saturated
+func printGeneratorHealth(w io.Writer, samples []monitor.SelfSample) {
+	if len(samples) == 0 {
+		return
+	}
+	var peak monitor.SelfSample
+	var flagged int
+	var reasons []string
+	for _, s := range samples {
+		peak.CPUPercent = max(peak.CPUPercent, s.CPUPercent)
+		peak.MemMB = max(peak.MemMB, s.MemMB)
+		peak.Goroutines = max(peak.Goroutines, s.Goroutines)
+		peak.GCPauseMs = max(peak.GCPauseMs, s.GCPauseMs)
+		if w := s.Warnings(); len(w) > 0 {
+			flagged++
+			reasons = w
+		}
+	}
+
+	fmt.Fprintf(w, "\n🖥️  GENERATOR HEALTH (peak)\n")
+	fmt.Fprintf(w, "   CPU: %.1f%% | Mem: %.0f MB | Goroutines: %d | GC pause: %.1f ms/s\n",
+		peak.CPUPercent, peak.MemMB, peak.Goroutines, peak.GCPauseMs)
+	if flagged > 0 {
+		fmt.Fprintf(w, "   ⚠️  Generator was saturated for %ds (e.g. %s); latency may reflect the client, not the server\n",
+			flagged, strings.Join(reasons, ", "))
+	}
+}
+
+// handleAutoReport writes the reports requested by cfg and returns their
+// prefix. With a run directory they always go there, named "report" unless
+// cfg.OutPrefix is set.
+func handleAutoReport(r *runner.Runner, cfg runner.Config, runDir string, results []runner.ExperimentResult, sum report.Summary) string {
+	if (cfg.OutPrefix == "" && cfg.OutDir == "" && runDir == "") || len(results) == 0 {
+		return ""
+	}
+
+	dir, tmpl := cfg.OutDir, cfg.OutPrefix
+	if tmpl == "" {
+		tmpl = app.DefaultReportTemplate
+	}
+	if runDir != "" {
+		dir = runDir
+		if cfg.OutPrefix == "" {
+			tmpl = runReportName
+		}
+	}
+	prefix, err := app.ReportPath(dir, tmpl, cfg, time.Now())
+	if err != nil {
+		fmt.Printf("❌ Cannot create output directory: %v\n", err)
+		return ""
+	}
+
+	fmt.Printf("\n💾 Generating reports with prefix: %s\n", prefix)
+	failed := 0
+	for _, export := range []struct {
+		file string
+		fn   func() error
+	}{
+		{prefix + ".csv", func() error { return app.ExportCSV(results, prefix+".csv") }},
+		{prefix + ".json", func() error { return app.ExportJSON(results, prefix+".json") }},
+		{prefix + "_summary.{json,csv}", func() error { return app.ExportSummary(sum, &r.Meta, prefix) }},
+		{prefix + "_intervals.json", func() error { return app.ExportIntervals(r, prefix+"_intervals.json") }},
+		{prefix + "_percentiles.csv", func() error {
+			return app.ExportPercentiles(r.Stats.GetSnapshots(), r.Cfg.GetPercentiles(), prefix+"_percentiles.csv")
+		}},
+	} {
+		if err := export.fn(); err != nil {
+			failed++
+			fmt.Printf("❌ %s: %v\n", export.file, err)
+			slog.Error("report export failed", "run_id", r.Meta.RunID, "file", export.file, "err", err)
+		}
+	}
+	if failed == 0 {
+		fmt.Printf("✅ Reports saved to %s.{csv,json,_summary.json,_intervals.json,_percentiles.csv}\n", prefix)
+	}
+	slog.Info("reports written", "run_id", r.Meta.RunID, "prefix", prefix, "failed", failed)
 
-	fmt.Printf("\n💾 Generating reports with prefix: %s\n", cfg.OutPrefix)
-	app.ExportCSV(r.Results, cfg.OutPrefix+".csv")
-	app.ExportJSON(r.Results, cfg.OutPrefix+".json")
-	app.ExportSummary(r.Results, cfg.OutPrefix)
-	fmt.Printf("✅ Reports saved to %s.{csv,json,_summary.json}\n", cfg.OutPrefix)
+	if cfg.RequestIDHeader != "" && sum.TotalFail > 0 {
+		if err := app.ExportFailures(results, prefix+"_failures.csv"); err != nil {
+			slog.Error("failures export failed", "run_id", r.Meta.RunID, "err", err)
+			fmt.Printf("❌ Failures report failed: %v\n", err)
+		} else {
+			fmt.Printf("🔎 %d failed request IDs saved to %s_failures.csv; find them in the server logs with\n", sum.TotalFail, prefix)
+			fmt.Printf("   cut -d, -f2 %s_failures.csv | tail -n +2 | grep -F -f - <server log>\n", prefix)
+		}
+	}
+
+	target := app.TargetSamples(r)
+	if len(target) > 0 {
+		if err := app.ExportTargetCSV(target, results, prefix+"_target.csv"); err != nil {
+			slog.Error("target report export failed", "run_id", r.Meta.RunID, "err", err)
+			fmt.Printf("❌ Target report failed: %v\n", err)
+		} else {
+			fmt.Printf("🖥️  Target CPU/memory saved to %s_target.csv\n", prefix)
+		}
+	}
+
+	mirrored := r.MirrorResults()
+	if len(mirrored) > 0 {
+		if err := app.ExportCSV(mirrored, prefix+"_mirror.csv"); err != nil {
+			slog.Error("mirror report export failed", "run_id", r.Meta.RunID, "err", err)
+			fmt.Printf("❌ Mirror report failed: %v\n", err)
+		} else {
+			fmt.Printf("🪞 Mirrored requests saved to %s_mirror.csv\n", prefix)
+		}
+	}
+
+	if cfg.Bundle {
+		if err := app.ExportBundle(r, prefix+".zip"); err != nil {
+			slog.Error("bundle export failed", "run_id", r.Meta.RunID, "err", err)
+			fmt.Printf("❌ Bundle failed: %v\n", err)
+		} else {
+			fmt.Printf("📦 Bundle saved to %s.zip\n", prefix)
+		}
+	}
+
+	if cfg.UploadTo != "" {
+		fmt.Printf("\n☁️  Uploading reports to %s\n", cfg.UploadTo)
+		files := []string{
+			prefix + ".csv",
+			prefix + ".json",
+			prefix + "_summary.json",
+			prefix + "_summary.csv",
+			prefix + "_intervals.json",
+			prefix + "_percentiles.csv",
+		}
+		if cfg.RequestIDHeader != "" && sum.TotalFail > 0 {
+			files = append(files, prefix+"_failures.csv")
+		}
+		if len(target) > 0 {
+			files = append(files, prefix+"_target.csv")
+		}
+		if len(mirrored) > 0 {
+			files = append(files, prefix+"_mirror.csv")
+		}
+		if cfg.Bundle {
+			files = append(files, prefix+".zip")
+		}
+		if err := uploadArtifacts(cfg.UploadTo, files); err != nil {
+			slog.Error("report upload failed", "run_id", r.Meta.RunID, "dest", cfg.UploadTo, "err", err)
+			fmt.Printf("❌ Upload failed: %v\n", err)
+		} else {
+			fmt.Printf("✅ Upload complete\n")
+		}
+	}
+	return prefix
 }
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"steadyq/internal/runner"
+)
+
+const commandHelp = "Commands (then Enter): p = pause/resume, + / - = rate ±10%, s = snapshot, h = help"
+
+var (
+	commandsOnce sync.Once
+	commandLines chan string
+)
+
+// stdinCommands returns the lines typed on stdin. One reader serves every run
+// of the process (scheduled runs too); the channel closes when stdin does
+// (e.g. /dev/null in CI).
+func stdinCommands() <-chan string {
+	commandsOnce.Do(func() {
+		commandLines = make(chan string)
+		go func() {
+			defer close(commandLines)
+			sc := bufio.NewScanner(os.Stdin)
+			for sc.Scan() {
+				commandLines <- sc.Text()
+			}
+		}()
+	})
+	return commandLines
+}
+
+// runCommand applies each command character of a typed line to the run
+func runCommand(r *runner.Runner, line string, elapsed time.Duration) {
+	for _, c := range strings.TrimSpace(line) {
+		switch c {
+		case 'p', 'P':
+			r.SetPaused(!r.Paused())
+			if r.Paused() {
+				fmt.Printf("\n⏸  Paused at %s, no new requests (p to resume; the clock keeps running)\n", elapsed.Round(time.Second))
+			} else {
+				fmt.Printf("\n▶️  Resumed at %s\n", elapsed.Round(time.Second))
+			}
+		case '+', '=', '-':
+			if r.Cfg.Mode == "users" {
+				fmt.Printf("\n+/- adjust the rate of open-loop runs (--rate), not users\n")
+				continue
+			}
+			step := max(1, r.Cfg.TargetRPS/10)
+			if c == '-' {
+				step = -step
+			}
+			fmt.Printf("\n🎚  Target rate: %d RPS\n", r.AdjustRate(step))
+		case 's', 'S':
+			printSnapshot(r, elapsed)
+		case 'h', 'H', '?':
+			fmt.Printf("\n%s\n", commandHelp)
+		}
+	}
+}
+
+// printSnapshot shows the headline numbers so far without ending the run
+func printSnapshot(r *runner.Runner, elapsed time.Duration) {
+	stats := r.Stats
+	requests := atomic.LoadUint64(&stats.Requests)
+	rps := 0.0
+	if elapsed.Seconds() > 0 {
+		rps = float64(requests) / elapsed.Seconds()
+	}
+	fmt.Printf("\n\n📸 SNAPSHOT at %s\n", elapsed.Round(time.Second))
+	fmt.Printf("   Requests: %d | OK: %d | Err: %d | In flight: %d | Avg RPS: %.1f\n",
+		requests, atomic.LoadUint64(&stats.Success), atomic.LoadUint64(&stats.Fail), r.GetInflight(), rps)
+	fmt.Printf("   Service (ms): P50 %.2f | P90 %.2f | P99 %.2f\n",
+		stats.GetP50Service(), stats.GetP90Service(), stats.GetP99Service())
+	if r.Cfg.Mode != "users" {
+		fmt.Printf("   Target rate: %d RPS\n", r.TargetRate())
+	}
+	fmt.Println()
+}
@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"steadyq/internal/report"
+	"steadyq/internal/runner"
+	"steadyq/internal/stats"
+	"steadyq/internal/tui/app"
+)
+
+// CompareOptions controls CompareTargets
+type CompareOptions struct {
+	// Alternate the targets this many times, each run taking 1/Rounds of the
+	// steady duration (1 = all of A, then all of B). Interleaving spreads
+	// drift in the network or shared backends evenly over both targets.
+	Rounds int
+}
+
+// CompareTargets runs cfg against two base URLs in turn (see
+// runner.RebaseURL) and compares the results: percentiles with confidence
+// intervals and error rates (see report.Compare). With an out prefix
+// (Config.OutPrefix) it writes <prefix>_compare.json and each target's raw
+// results as <prefix>_a.csv and <prefix>_b.csv. It returns the comparison
+// for the exit code.
+func CompareTargets(cfg runner.Config, bases [2]string, opts CompareOptions) (report.Comparison, error) {
+	rounds := max(opts.Rounds, 1)
+	steady := cfg.SteadyDur / rounds
+	if steady < 1 {
+		return report.Comparison{}, fmt.Errorf("%d rounds leave no steady time of a %ds run", rounds, cfg.SteadyDur)
+	}
+
+	prefix := cfg.OutPrefix
+	var results [2][]runner.ExperimentResult
+	var intervals [2][]stats.IntervalSnapshot
+	var dropped [2]uint64
+	for round := 1; round <= rounds; round++ {
+		for i, base := range bases {
+			c := cfg
+			c.URL = runner.RebaseURL(cfg.URL, base)
+			c.SteadyDur = steady
+			// One report for the whole comparison, not one per run
+			c.OutPrefix, c.OutDir, c.RunsDir, c.Bundle, c.UploadTo = "", "", "", false, ""
+			c.History, c.HistoryRemote, c.Thresholds = "", "", nil
+
+			fmt.Printf("\n⚖️  Round %d/%d: target %c (%s)\n", round, rounds, 'A'+i, base)
+			out, err := execute(c, newReportSink(c))
+			if err != nil {
+				return report.Comparison{}, fmt.Errorf("target %c: %v", 'A'+i, err)
+			}
+			if out.Interrupted {
+				os.Exit(130)
+			}
+			results[i] = append(results[i], out.Runner.SnapshotResults()...)
+			intervals[i] = append(intervals[i], out.Runner.Stats.GetSnapshots()...)
+			dropped[i] += atomic.LoadUint64(&out.Runner.Stats.ResultsDropped)
+		}
+	}
+
+	var result report.Comparison
+	if dropped[0] == 0 && dropped[1] == 0 {
+		result = report.Compare(bases[0], results[0], bases[1], results[1])
+		printComparison(os.Stdout, result, "service ms, successful")
+	} else {
+		// The kept results are only the end of each run: compare every
+		// request from the interval histograms, as CompareRuns does
+		fmt.Printf("\n⚠️  --max-results dropped %d results of A and %d of B: comparing the interval histograms instead\n", dropped[0], dropped[1])
+		var runs [2]report.ComparedRun
+		var bins [2][]report.Bin
+		for i := range bases {
+			runs[i], bins[i] = app.ReplayBins(app.Replay{Intervals: intervals[i]}, bases[i])
+		}
+		result = report.CompareBins(runs[0], bins[0], runs[1], bins[1])
+		printComparison(os.Stdout, result, "service ms of every request, from histograms")
+	}
+	result.A.ResultsDropped, result.B.ResultsDropped = dropped[0], dropped[1]
+
+	if prefix != "" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err == nil {
+			err = os.WriteFile(prefix+"_compare.json", data, 0644)
+		}
+		if err == nil {
+			err = app.ExportCSV(results[0], prefix+"_a.csv")
+		}
+		if err == nil {
+			err = app.ExportCSV(results[1], prefix+"_b.csv")
+		}
+		if err != nil {
+			return result, err
+		}
+		fmt.Printf("💾 Comparison saved to %s_compare.json, raw results to %s_{a,b}.csv\n", prefix, prefix)
+	}
+	return result, nil
+}
+
+// CompareRuns compares two finished runs (bundles or _intervals.json files)
+// from their interval histograms, writing <prefix>_compare.json when prefix
+// is set
+func CompareRuns(a, b, prefix string) (report.Comparison, error) {
+	var runs [2]report.ComparedRun
+	var bins [2][]report.Bin
+	for i, input := range []string{a, b} {
+		rp, err := app.LoadReplay(input)
+		if err != nil {
+			return report.Comparison{}, fmt.Errorf("%s: %v", input, err)
+		}
+		runs[i], bins[i] = app.ReplayBins(rp, input)
+	}
+
+	result := report.CompareBins(runs[0], bins[0], runs[1], bins[1])
+	printComparison(os.Stdout, result, "service ms of every request, from histograms")
+	if prefix != "" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err == nil {
+			err = os.WriteFile(prefix+"_compare.json", data, 0644)
+		}
+		if err != nil {
+			return result, err
+		}
+		fmt.Printf("💾 Comparison saved to %s_compare.json\n", prefix)
+	}
+	return result, nil
+}
+
+// printComparison prints B against A with each difference's 95% interval;
+// latencies says what was measured
+func printComparison(w io.Writer, c report.Comparison, latencies string) {
+	fmt.Fprintf(w, "\n======================================================================\n")
+	fmt.Fprintf(w, "⚖️  COMPARISON (%s; B - A with 95%% interval)\n", latencies)
+	fmt.Fprintf(w, "   A: %s\n   B: %s\n\n", c.A.Name, c.B.Name)
+	fmt.Fprintf(w, "   %-8s %12s %12s   %s\n", "", "A", "B", "B - A")
+	fmt.Fprintf(w, "   %-8s %12d %12d\n", "Requests", c.A.Requests, c.B.Requests)
+	e := c.ErrorRate
+	fmt.Fprintf(w, "   %-8s %12.2f %12.2f   %+.2f pts (p = %.3f)%s\n", "Error %", e.A, e.B, e.Diff, e.PValue, significance(e.Significant))
+	for _, d := range c.Percentiles {
+		fmt.Fprintf(w, "   %-8s %12.2f %12.2f   %+.2f [%+.2f, %+.2f]%s\n",
+			fmt.Sprintf("P%g", d.P), d.A, d.B, d.Diff, d.Low, d.High, significance(d.Significant))
+	}
+	o := c.Overall
+	fmt.Fprintf(w, "   %-8s %25s   P(B slower) = %.3f, Mann-Whitney p = %.3f%s\n", "Overall", "", o.ProbBSlower, o.PValue, significance(o.Significant))
+
+	var slower []string
+	for _, d := range c.Regressions() {
+		slower = append(slower, fmt.Sprintf("P%g", d.P))
+	}
+	switch {
+	case len(slower) > 0:
+		fmt.Fprintf(w, "\n   ❌ B is significantly slower at %s\n", strings.Join(slower, ", "))
+	case e.Significant && e.Diff > 0:
+		fmt.Fprintf(w, "\n   ❌ B fails significantly more often\n")
+	case o.Significant && o.ProbBSlower > 0.5:
+		fmt.Fprintf(w, "\n   ⚠️  B tends to be slower overall, though no single percentile moved significantly\n")
+	default:
+		fmt.Fprintf(w, "\n   ✅ B is not significantly worse than A\n")
+	}
+	for _, warning := range report.SampleWarnings(min(c.A.Requests-c.A.Fail, c.B.Requests-c.B.Fail)) {
+		fmt.Fprintf(w, "   ⚠️  %s\n", warning)
+	}
+	fmt.Fprintf(w, "======================================================================\n")
+}
+
+func significance(significant bool) string {
+	if significant {
+		return "  significant"
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSpec is a parsed 5-field cron expression: minute hour day-of-month month
+// day-of-week. Each field is a bit set of the values it matches.
+type cronSpec struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool // "*": standard cron matches dom OR dow when both are restricted
+}
+
+// cronMacros are the shorthand schedules accepted in place of five fields
+var cronMacros = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// parseCron parses "*/15 * * * *" style expressions: *, numbers, ranges
+// (1-5), lists (1,15,30) and steps (*/10, 0-30/5). Days of week are 0-7,
+// both 0 and 7 being Sunday.
+func parseCron(expr string) (*cronSpec, error) {
+	expr = strings.TrimSpace(expr)
+	if m, ok := cronMacros[expr]; ok {
+		expr = m
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron %q: expected 5 fields (minute hour day month weekday)", expr)
+	}
+
+	var c cronSpec
+	var err error
+	if c.minute, err = cronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("cron minute: %v", err)
+	}
+	if c.hour, err = cronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("cron hour: %v", err)
+	}
+	if c.dom, err = cronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("cron day of month: %v", err)
+	}
+	if c.month, err = cronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("cron month: %v", err)
+	}
+	if c.dow, err = cronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("cron day of week: %v", err)
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1 // 7 is Sunday too
+	}
+	c.domAny = strings.HasPrefix(fields[2], "*")
+	c.dowAny = strings.HasPrefix(fields[4], "*")
+	return &c, nil
+}
+
+// cronField parses one comma-separated field into a bit set of values in [lo, hi]
+func cronField(field string, lo, hi int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+			step = n
+		}
+
+		from, to := lo, hi
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if from, err = strconv.Atoi(a); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			to = from
+			if isRange {
+				if to, err = strconv.Atoi(b); err != nil {
+					return 0, fmt.Errorf("invalid range %q", part)
+				}
+			} else if hasStep {
+				to = hi // "5/10" means from 5 to the end, every 10
+			}
+		}
+		if from < lo || to > hi || from > to {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, lo, hi)
+		}
+		for v := from; v <= to; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// next returns the first minute after t that matches the schedule
+func (c *cronSpec) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// Every valid expression matches within 4 years (Feb 29 on a given weekday)
+	for limit := t.AddDate(4, 0, 1); t.Before(limit); t = t.Add(time.Minute) {
+		if c.month&(1<<int(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location()).Add(-time.Minute)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location()).Add(-time.Minute)
+			continue
+		}
+		if c.hour&(1<<t.Hour()) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location()).Add(-time.Minute)
+			continue
+		}
+		if c.minute&(1<<t.Minute()) != 0 {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *cronSpec) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<t.Day()) != 0
+	dow := c.dow&(1<<int(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
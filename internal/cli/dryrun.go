@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"steadyq/internal/runner"
+)
+
+// dryRunBodyLimit truncates printed bodies, large payloads are summarized
+const dryRunBodyLimit = 2048
+
+// DryRun prints the first n requests of cfg as they would be sent, without
+// sending them
+func DryRun(cfg runner.Config, n int) {
+	printHeader(os.Stdout, cfg)
+	fmt.Printf("🔍 DRY RUN: %d rendered requests, nothing is sent\n", n)
+
+	for i, d := range runner.DryRun(cfg, n) {
+		fmt.Printf("\n── Request %d (user %s) ──────────────────────────────\n", i+1, d.UserID)
+		switch d.Protocol {
+		case "script":
+			fmt.Printf("$ %s\n", d.Command)
+		case "redis":
+			fmt.Printf("%s\n> %s\n", d.URL, d.Command)
+		case "kafka", "sql", "tcp", "udp":
+			fmt.Printf("%s\n", d.URL)
+			if d.Key != "" {
+				fmt.Printf("Key: %s\n", d.Key)
+			}
+			for j, a := range d.Args {
+				fmt.Printf("Arg %d: %s\n", j+1, a)
+			}
+			printDryBody(d.Body)
+		default:
+			fmt.Printf("%s %s\n", d.Method, d.URL)
+			if d.Host != "" {
+				fmt.Printf("Host: %s\n", d.Host)
+			}
+			keys := make([]string, 0, len(d.Header))
+			for k := range d.Header {
+				keys = append(keys, k)
+			}
+			slices.Sort(keys)
+			for _, k := range keys {
+				fmt.Printf("%s: %s\n", k, strings.Join(d.Header[k], ", "))
+			}
+			if d.BodyFile != "" {
+				fmt.Printf("(body from %s)\n", d.BodyFile)
+			}
+			printDryBody(d.Body)
+		}
+	}
+	fmt.Println()
+}
+
+func printDryBody(body string) {
+	if body == "" {
+		return
+	}
+	if len(body) > dryRunBodyLimit {
+		fmt.Printf("\n%s\n… (%d more bytes)\n", body[:dryRunBodyLimit], len(body)-dryRunBodyLimit)
+		return
+	}
+	fmt.Printf("\n%s\n", body)
+}
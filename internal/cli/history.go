@@ -0,0 +1,377 @@
+package cli
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Layout of a history archive: the entries, and the run directory of each
+// entry that had one under runs/<run ID>/
+const (
+	archiveHistory = "history.jsonl"
+	archiveRuns    = "runs"
+)
+
+// DefaultRunsDir is where imported run directories go unless told otherwise
+const DefaultRunsDir = "runs"
+
+// ReadHistory loads every entry of a history file
+func ReadHistory(path string) ([]HistoryEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return decodeHistory(f)
+}
+
+func decodeHistory(r io.Reader) ([]HistoryEntry, error) {
+	var entries []HistoryEntry
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		var e HistoryEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, sc.Err()
+}
+
+// historyKey identifies a run across machines: its run ID, or its start and
+// target for entries without one (skipped runs, older files)
+func historyKey(e HistoryEntry) string {
+	if e.RunID != "" {
+		return e.RunID
+	}
+	return e.StartedAt.UTC().Format(time.RFC3339Nano) + " " + e.Target
+}
+
+// historyKeys returns the keys of the runs in a history file, none when it
+// does not exist yet
+func historyKeys(history string) (map[string]bool, error) {
+	seen := make(map[string]bool)
+	entries, err := ReadHistory(history)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, e := range entries {
+		seen[historyKey(e)] = true
+	}
+	return seen, nil
+}
+
+// ExportHistory packs the history file and the run directories its entries
+// reference into a gzipped tar, and returns how many entries it holds
+func ExportHistory(history, archive string) (int, error) {
+	entries, err := ReadHistory(history)
+	if err != nil {
+		return 0, err
+	}
+	return len(entries), exportEntries(entries, archive)
+}
+
+// exportEntries writes entries and their run directories to archive, in the
+// layout ImportHistory reads
+func exportEntries(entries []HistoryEntry, archive string) error {
+	out, err := os.Create(archive)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	// Run directories move with the entries, under a path relative to the archive
+	var lines bytes.Buffer
+	enc := json.NewEncoder(&lines)
+	dirs := make(map[string]string) // Archive prefix -> local run directory
+	for _, e := range entries {
+		if info, err := os.Stat(e.RunDir); e.RunDir != "" && e.RunID != "" && err == nil && info.IsDir() {
+			prefix := path.Join(archiveRuns, e.RunID)
+			dirs[prefix] = e.RunDir
+			e.Reports = filepath.ToSlash(archivedReports(e.Reports, e.RunDir, prefix))
+			e.RunDir = prefix
+		} else {
+			// Reports outside a run directory stay on this machine
+			e.RunDir, e.Reports = "", ""
+		}
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	if err := writeTarFile(tw, archiveHistory, lines.Bytes()); err != nil {
+		return err
+	}
+	for prefix, dir := range dirs {
+		if err := addTarDir(tw, dir, prefix); err != nil {
+			return fmt.Errorf("run directory %s: %v", dir, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// HistorySelection picks entries of a history file: by run ID, and/or all
+// runs started before a time. An empty selection picks nothing.
+type HistorySelection struct {
+	RunIDs []string
+	Before time.Time
+}
+
+func (s HistorySelection) empty() bool {
+	return len(s.RunIDs) == 0 && s.Before.IsZero()
+}
+
+func (s HistorySelection) matches(e HistoryEntry) bool {
+	if e.RunID != "" && slices.Contains(s.RunIDs, e.RunID) {
+		return true
+	}
+	return !s.Before.IsZero() && e.StartedAt.Before(s.Before)
+}
+
+// DeleteHistory removes the selected entries from the history file, and
+// their run directories too with withRuns. Returns how many were removed.
+func DeleteHistory(history string, sel HistorySelection, withRuns bool) (int, error) {
+	removed, err := removeHistory(history, sel)
+	if err != nil || !withRuns {
+		return len(removed), err
+	}
+	for _, e := range removed {
+		if e.RunDir == "" || !safeRunID(e.RunID) || filepath.Base(e.RunDir) != e.RunID {
+			continue // Only directories the run itself created
+		}
+		if err := os.RemoveAll(e.RunDir); err != nil {
+			return len(removed), fmt.Errorf("run directory %s: %v", e.RunDir, err)
+		}
+	}
+	return len(removed), nil
+}
+
+// ArchiveHistory moves the selected entries out of the history file into an
+// archive like ExportHistory's (run directories included, left on disk), to
+// bring back with ImportHistory. Returns how many were archived.
+func ArchiveHistory(history, archive string, sel HistorySelection) (int, error) {
+	entries, err := ReadHistory(history)
+	if err != nil {
+		return 0, err
+	}
+	var picked []HistoryEntry
+	for _, e := range entries {
+		if sel.matches(e) {
+			picked = append(picked, e)
+		}
+	}
+	if len(picked) == 0 {
+		return 0, fmt.Errorf("no run in %s matches", history)
+	}
+	if err := exportEntries(picked, archive); err != nil {
+		return 0, err
+	}
+	removed, err := removeHistory(history, sel)
+	return len(removed), err
+}
+
+// removeHistory rewrites the history file without the selected entries and
+// returns them. The file is replaced in one rename, never left half written.
+func removeHistory(history string, sel HistorySelection) ([]HistoryEntry, error) {
+	if sel.empty() {
+		return nil, fmt.Errorf("select runs by run ID or --before")
+	}
+	entries, err := ReadHistory(history)
+	if err != nil {
+		return nil, err
+	}
+	var kept bytes.Buffer
+	enc := json.NewEncoder(&kept)
+	var removed []HistoryEntry
+	for _, e := range entries {
+		if sel.matches(e) {
+			removed = append(removed, e)
+			continue
+		}
+		if err := enc.Encode(e); err != nil {
+			return nil, err
+		}
+	}
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	tmp := history + ".tmp"
+	if err := os.WriteFile(tmp, kept.Bytes(), 0644); err != nil {
+		return nil, err
+	}
+	return removed, os.Rename(tmp, history)
+}
+
+// ImportHistory merges an archive written by ExportHistory into the history
+// file. Runs already there are skipped; the run directories of new ones are
+// unpacked into runsDir. Imported entries record the archive they came from.
+func ImportHistory(archive, history, runsDir string) (added, skipped int, err error) {
+	entries, files, err := readHistoryArchive(archive)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	seen, err := historyKeys(history)
+	if err != nil {
+		return 0, 0, err
+	}
+	if runsDir == "" {
+		runsDir = DefaultRunsDir
+	}
+
+	for _, e := range entries {
+		key := historyKey(e)
+		if seen[key] {
+			skipped++
+			continue
+		}
+		seen[key] = true
+
+		if e.RunDir != "" && !safeRunID(e.RunID) {
+			return added, skipped, fmt.Errorf("%s: unsafe run ID %q", archive, e.RunID)
+		}
+		if e.RunDir != "" {
+			prefix := path.Join(archiveRuns, e.RunID) + "/"
+			dest := filepath.Join(runsDir, e.RunID)
+			for name, data := range files {
+				rel, ok := strings.CutPrefix(name, prefix)
+				if !ok {
+					continue
+				}
+				file := filepath.Join(dest, filepath.FromSlash(rel))
+				if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+					return added, skipped, err
+				}
+				if err := os.WriteFile(file, data, 0644); err != nil {
+					return added, skipped, err
+				}
+			}
+			e.Reports = archivedReports(e.Reports, prefix, dest)
+			e.RunDir = dest
+		}
+		e.Imported = filepath.Base(archive)
+		if err := appendHistory(history, e); err != nil {
+			return added, skipped, err
+		}
+		added++
+	}
+	return added, skipped, nil
+}
+
+// readHistoryArchive returns the entries of an archive and its run files by
+// slash-separated name. Names escaping the archive are rejected.
+func readHistoryArchive(archive string) ([]HistoryEntry, map[string][]byte, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: not a history archive: %v", archive, err)
+	}
+	defer gz.Close()
+
+	var entries []HistoryEntry
+	found := false
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(hdr.Name)
+		if !fs.ValidPath(name) {
+			return nil, nil, fmt.Errorf("%s: unsafe path %q", archive, hdr.Name)
+		}
+		if name == archiveHistory {
+			if entries, err = decodeHistory(tr); err != nil {
+				return nil, nil, fmt.Errorf("%s: %v", archiveHistory, err)
+			}
+			found = true
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, err
+		}
+		files[name] = data
+	}
+	if !found {
+		return nil, nil, fmt.Errorf("%s: no %s inside, not a history archive", archive, archiveHistory)
+	}
+	return entries, files, nil
+}
+
+// archivedReports moves a report prefix inside the run directory from to the
+// same place under to, "" when it is not inside
+func archivedReports(reports, from, to string) string {
+	rel, err := filepath.Rel(from, reports)
+	if reports == "" || err != nil || !filepath.IsLocal(rel) {
+		return ""
+	}
+	return filepath.Join(to, rel)
+}
+
+// safeRunID reports whether id can name a directory under the runs directory
+func safeRunID(id string) bool {
+	return id != "" && id != "." && id != ".." && !strings.ContainsAny(id, `/\`)
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte) error {
+	hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: time.Now()}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// addTarDir stores the regular files under dir as prefix/<relative path>
+func addTarDir(tw *tar.Writer, dir, prefix string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return writeTarFile(tw, path.Join(prefix, filepath.ToSlash(rel)), data)
+	})
+}
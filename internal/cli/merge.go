@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"steadyq/internal/tui/app"
+)
+
+// Merge combines the bundles or _intervals.json files of generators that ran
+// side by side into one report under prefix, and prints each input next to
+// the combined run
+func Merge(inputs []string, prefix string) error {
+	var runs []app.Replay
+	for _, in := range inputs {
+		rp, err := app.LoadReplay(in)
+		if err != nil {
+			return fmt.Errorf("%s: %v", in, err)
+		}
+		runs = append(runs, rp)
+	}
+	merged, err := app.MergeReplays(runs)
+	if err != nil {
+		return err
+	}
+	if err := app.ExportMerged(merged, prefix); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n🔀 MERGED %d GENERATORS (service ms)\n", len(runs))
+	fmt.Printf("======================================================================\n")
+	fmt.Printf("%-28s %10s %9s %8s %9s %9s\n", "Input", "Requests", "RPS", "Errors", "P50", "P99")
+	for i, rp := range runs {
+		s := app.SummarizeIntervals(rp.Intervals, rp.Config.GetPercentiles())
+		fmt.Printf("%-28s %10d %9.1f %7.2f%% %9.2f %9.2f\n", filepath.Base(inputs[i]), s.TotalRequests, s.AverageRPS, s.ErrorRate, s.Service.P50, s.Service.P99)
+	}
+	s := merged.Summary
+	fmt.Printf("%-28s %10d %9.1f %7.2f%% %9.2f %9.2f\n", "= combined", s.TotalRequests, s.AverageRPS, s.ErrorRate, s.Service.P50, s.Service.P99)
+	for _, warning := range s.Warnings {
+		fmt.Printf("⚠️  %s\n", warning)
+	}
+	fmt.Printf("======================================================================\n")
+	fmt.Printf("✅ Merged report saved to %s{_intervals.json,_percentiles.csv,_summary.json,_summary.csv,_service_time.hgrm}\n", prefix)
+	fmt.Printf("   Replay it with: steadyq replay %s_intervals.json\n", prefix)
+	return nil
+}
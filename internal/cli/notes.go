@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// recordRun appends a single run to the history file (Config.History). In a
+// terminal it first asks for a one-line note and a verdict, while the
+// context ("deployed build abc123") is still fresh.
+func recordRun(path string, out outcome) {
+	entry := newHistoryEntry(out.Runner.Cfg)
+	fillHistoryEntry(&entry, out)
+	if interactive() {
+		askNote(&entry)
+	}
+	if err := appendHistory(path, entry); err != nil {
+		slog.Error("history not appended", "file", path, "err", err)
+		fmt.Printf("❌ Cannot append to history: %v\n", err)
+		return
+	}
+	fmt.Printf("📈 Run appended to %s\n", path)
+	if remote := out.Runner.Cfg.HistoryRemote; remote != "" {
+		shareHistory(remote, entry)
+	}
+}
+
+// askNote prompts for the entry's note and verdict; Enter keeps the verdict
+// of the thresholds, if any
+func askNote(e *HistoryEntry) {
+	fmt.Printf("\n📝 Note for the history (Enter to skip): ")
+	note, ok := readLine()
+	if !ok {
+		fmt.Println()
+		return
+	}
+	e.Note = strings.TrimSpace(note)
+
+	def := e.Verdict
+	if def == "" {
+		def = "none"
+	}
+	fmt.Printf("   Verdict [p]ass / [f]ail (Enter: %s): ", def)
+	answer, ok := readLine()
+	if !ok {
+		fmt.Println()
+		return
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "p", "pass":
+		e.Verdict = verdict(true)
+	case "f", "fail":
+		e.Verdict = verdict(false)
+	}
+}
+
+// readLine reads the next line typed on stdin, shared with the run's
+// keyboard commands; false once stdin is closed
+func readLine() (string, bool) {
+	line, ok := <-stdinCommands()
+	return line, ok
+}
+
+// interactive reports whether stdin is a terminal someone can answer from
+func interactive() bool {
+	return isatty.IsTerminal(os.Stdin.Fd())
+}
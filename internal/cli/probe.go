@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"steadyq/internal/runner"
+)
+
+// probeBodyLimit truncates the printed response body
+const probeBodyLimit = 16 << 10
+
+// Probe sends one configured request and prints the request, the response
+// status, headers and body, and a timing breakdown. Exits 1 unless the
+// response counts as a success.
+func Probe(cfg runner.Config) {
+	p := runner.Probe(cfg)
+	req := p.Request
+
+	fmt.Printf("\n🔎 PROBE\n")
+	fmt.Printf("======================================================================\n")
+	switch {
+	case req.Command != "":
+		fmt.Printf("$ %s\n", req.Command)
+	case req.Protocol != "http":
+		fmt.Printf("> %s %s\n", strings.ToUpper(req.Protocol), req.URL)
+	default:
+		fmt.Printf("> %s %s\n", req.Method, req.URL)
+		if req.Host != "" {
+			fmt.Printf("> Host: %s\n", req.Host)
+		}
+		printHeaders("> ", req.Header)
+		if req.Body != "" {
+			fmt.Printf(">\n")
+			printDryBody(req.Body)
+		}
+	}
+	fmt.Println()
+
+	if p.Result.Err != nil {
+		fmt.Printf("❌ %s\n", p)
+	} else {
+		if p.Proto != "" {
+			fmt.Printf("< %s %d %s\n", p.Proto, p.Result.Status, http.StatusText(p.Result.Status))
+			printHeaders("< ", p.Header)
+		} else {
+			fmt.Printf("< status %d\n", p.Result.Status)
+		}
+		printResponseBody(p.Body, p.Header.Get("Content-Type"))
+	}
+
+	t := p.Timing
+	fmt.Printf("\n⏱️  TIMING\n")
+	if p.Proto != "" {
+		fmt.Printf("   DNS: %s | Connect: %s | TLS: %s | Server: %s | Download: %s\n",
+			ms(t.DNS), ms(t.Connect), ms(t.TLS), ms(t.Wait), ms(t.Download))
+	}
+	fmt.Printf("   Total: %s\n", ms(p.Result.ServiceTime))
+	if p.RemoteAddr != "" {
+		tlsInfo := ""
+		if p.TLSVersion != "" {
+			tlsInfo = " over " + p.TLSVersion
+		}
+		fmt.Printf("   Remote: %s%s\n", p.RemoteAddr, tlsInfo)
+	}
+	fmt.Printf("======================================================================\n")
+
+	if !p.Result.Success {
+		if p.Result.Err == nil {
+			fmt.Printf("❌ Status %d is not a success (see --success-codes)\n", p.Result.Status)
+		}
+		os.Exit(1)
+	}
+	fmt.Printf("✅ %s\n", p)
+}
+
+func printHeaders(prefix string, h http.Header) {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		fmt.Printf("%s%s: %s\n", prefix, k, strings.Join(h[k], ", "))
+	}
+}
+
+// printResponseBody prints the body, indenting JSON for readability
+func printResponseBody(body []byte, contentType string) {
+	if len(body) == 0 {
+		return
+	}
+	if strings.Contains(contentType, "json") || json.Valid(body) {
+		var out bytes.Buffer
+		if json.Indent(&out, body, "", "  ") == nil {
+			body = out.Bytes()
+		}
+	}
+	fmt.Println()
+	if len(body) > probeBodyLimit {
+		fmt.Printf("%s\n… (%d more bytes)\n", body[:probeBodyLimit], len(body)-probeBodyLimit)
+		return
+	}
+	fmt.Printf("%s\n", bytes.TrimRight(body, "\n"))
+}
+
+// ms formats a phase duration in milliseconds, "-" when it did not happen
+func ms(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2fms", float64(d.Microseconds())/1000)
+}
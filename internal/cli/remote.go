@@ -0,0 +1,274 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// HistoryTokenEnv holds the bearer token sent to an HTTP history backend
+const HistoryTokenEnv = "STEADYQ_HISTORY_TOKEN"
+
+// historyRemote is a team-shared history backend:
+//   - https://host/path: entries are POSTed as JSON with the token of
+//     HistoryTokenEnv; a GET returns them (JSON array or JSON Lines). Plain
+//     http:// is only accepted for loopback hosts, where the token stays local.
+//   - s3://bucket/prefix or gs://bucket/prefix: one <run ID>.json blob per
+//     entry, copied with the aws or gsutil CLI like --upload
+type historyRemote struct {
+	url    string
+	client *http.Client
+}
+
+// CheckHistoryRemote validates a --history-remote value
+func CheckHistoryRemote(remote string) error {
+	_, err := newHistoryRemote(remote)
+	return err
+}
+
+func newHistoryRemote(remote string) (*historyRemote, error) {
+	remote = strings.TrimSuffix(remote, "/")
+	switch {
+	case strings.HasPrefix(remote, "http://") && !isLoopback(remote):
+		return nil, fmt.Errorf("history backend %q is plain http and would send %s in the clear (use https://; http:// is for localhost only)", remote, HistoryTokenEnv)
+	case strings.HasPrefix(remote, "http://"), strings.HasPrefix(remote, "https://"):
+		return &historyRemote{url: remote, client: &http.Client{Timeout: 30 * time.Second}}, nil
+	case strings.HasPrefix(remote, "s3://"), strings.HasPrefix(remote, "gs://"):
+		return &historyRemote{url: remote}, nil
+	}
+	return nil, fmt.Errorf("unsupported history backend %q (use https://, s3:// or gs://)", remote)
+}
+
+// isLoopback reports whether rawURL points at this machine
+func isLoopback(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (h *historyRemote) isHTTP() bool {
+	return h.client != nil
+}
+
+// push stores one entry. Entries are keyed by run ID, so pushing again
+// overwrites a blob; HTTP backends should treat run_id as the key too.
+func (h *historyRemote) push(e HistoryEntry) error {
+	if e.RunID == "" {
+		return nil // Runs that never started have nothing to share
+	}
+	if !safeRunID(e.RunID) {
+		// Names the blob: "../x" would escape the prefix
+		return fmt.Errorf("unsafe run ID %q", e.RunID)
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if h.isHTTP() {
+		req, err := http.NewRequest(http.MethodPost, h.url, bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		_, err = h.do(req)
+		return err
+	}
+
+	tmp, err := os.MkdirTemp("", "steadyq-history-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	file := filepath.Join(tmp, e.RunID+".json")
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		return err
+	}
+	return h.copy(file, h.url+"/"+e.RunID+".json", false)
+}
+
+// fetch returns every entry the backend holds
+func (h *historyRemote) fetch() ([]HistoryEntry, error) {
+	if h.isHTTP() {
+		req, err := http.NewRequest(http.MethodGet, h.url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+		body, err := h.do(req)
+		if err != nil {
+			return nil, err
+		}
+		if body = bytes.TrimSpace(body); bytes.HasPrefix(body, []byte("[")) {
+			var entries []HistoryEntry
+			if err := json.Unmarshal(body, &entries); err != nil {
+				return nil, fmt.Errorf("%s: %v", h.url, err)
+			}
+			return entries, nil
+		}
+		return decodeHistory(bytes.NewReader(body))
+	}
+
+	tmp, err := os.MkdirTemp("", "steadyq-history-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	if err := h.copy(h.url+"/", tmp, true); err != nil {
+		return nil, err
+	}
+	files, _ := filepath.Glob(filepath.Join(tmp, "*.json"))
+	var entries []HistoryEntry
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		var e HistoryEntry
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, fmt.Errorf("%s: %v", filepath.Base(f), err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// do sends req with the bearer token and returns the response body
+func (h *historyRemote) do(req *http.Request) ([]byte, error) {
+	if token := os.Getenv(HistoryTokenEnv); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			return nil, fmt.Errorf("%s %s: %s (set %s)", req.Method, h.url, resp.Status, HistoryTokenEnv)
+		}
+		return nil, fmt.Errorf("%s %s: %s", req.Method, h.url, resp.Status)
+	}
+	return body, nil
+}
+
+// copy runs the bucket's CLI copy command from src to dst; recursive copies
+// every .json blob under the src prefix into the dst directory
+func (h *historyRemote) copy(src, dst string, recursive bool) error {
+	var args []string
+	if strings.HasPrefix(h.url, "s3://") {
+		if _, err := exec.LookPath("aws"); err != nil {
+			return fmt.Errorf("the %s history needs the aws CLI in PATH", h.url)
+		}
+		args = []string{"aws", "s3", "cp", "--only-show-errors"}
+		if recursive {
+			args = append(args, "--recursive", "--exclude", "*", "--include", "*.json")
+		}
+	} else {
+		if _, err := exec.LookPath("gsutil"); err != nil {
+			return fmt.Errorf("the %s history needs gsutil in PATH", h.url)
+		}
+		args = []string{"gsutil", "-q", "cp"}
+		if recursive {
+			src += "*.json"
+		}
+	}
+	cmd := exec.Command(args[0], append(args[1:], src, dst)...)
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// PushHistory sends every local entry with a run ID to the backend, except
+// those pulled from it
+func PushHistory(history, remote string) (int, error) {
+	h, err := newHistoryRemote(remote)
+	if err != nil {
+		return 0, err
+	}
+	entries, err := ReadHistory(history)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, e := range entries {
+		if e.RunID == "" || e.Imported == remote {
+			continue
+		}
+		if err := h.push(e); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// PullHistory appends the backend's entries missing from the local history.
+// Their run directories and reports stay on the machine that ran them.
+func PullHistory(history, remote string) (added, skipped int, err error) {
+	h, err := newHistoryRemote(remote)
+	if err != nil {
+		return 0, 0, err
+	}
+	entries, err := h.fetch()
+	if err != nil {
+		return 0, 0, err
+	}
+	seen, err := historyKeys(history)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, e := range entries {
+		if e.RunID != "" && !safeRunID(e.RunID) {
+			return added, skipped, fmt.Errorf("%s: unsafe run ID %q", remote, e.RunID)
+		}
+		if seen[historyKey(e)] {
+			skipped++
+			continue
+		}
+		seen[historyKey(e)] = true
+		e.RunDir, e.Reports = "", ""
+		e.Imported = remote
+		if err := appendHistory(history, e); err != nil {
+			return added, skipped, err
+		}
+		added++
+	}
+	return added, skipped, nil
+}
+
+// shareHistory pushes a just-recorded entry, reporting instead of failing
+// the run when the backend is unreachable
+func shareHistory(remote string, e HistoryEntry) {
+	h, err := newHistoryRemote(remote)
+	if err == nil {
+		err = h.push(e)
+	}
+	if err != nil {
+		slog.Error("history not shared", "remote", remote, "err", err)
+		fmt.Printf("❌ Cannot share run with %s: %v\n", remote, err)
+		return
+	}
+	if e.RunID != "" {
+		fmt.Printf("🌐 Run shared with %s\n", remote)
+	}
+}
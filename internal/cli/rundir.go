@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"steadyq/internal/plan"
+	"steadyq/internal/report"
+	"steadyq/internal/runner"
+)
+
+// Files written to every run directory, next to the reports
+const (
+	runConfigFile = "config.json"
+	runLogFile    = "run.log"
+	runReportName = "report"
+)
+
+// writeRunDir creates <RunsDir>/<run ID>/ with the run's config and log and
+// returns it for the reports, "" when cfg.RunsDir is not set
+func writeRunDir(r *runner.Runner, cfg runner.Config, sum report.Summary) string {
+	if cfg.RunsDir == "" {
+		return ""
+	}
+	dir := filepath.Join(cfg.RunsDir, r.Meta.RunID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		slog.Error("run directory not created", "run_id", r.Meta.RunID, "err", err)
+		fmt.Printf("❌ Cannot create run directory: %v\n", err)
+		return ""
+	}
+
+	p := plan.Plan{Name: r.Meta.RunID, SavedAt: r.Meta.StartedAt, Config: runner.RedactConfig(cfg)}
+	if err := plan.WriteFile(filepath.Join(dir, runConfigFile), p); err != nil {
+		slog.Error("run config not written", "run_id", r.Meta.RunID, "err", err)
+		fmt.Printf("❌ Cannot write run config: %v\n", err)
+	}
+
+	var log bytes.Buffer
+	printHeader(&log, cfg)
+	printSummary(&log, r, sum)
+	if err := os.WriteFile(filepath.Join(dir, runLogFile), log.Bytes(), 0644); err != nil {
+		slog.Error("run log not written", "run_id", r.Meta.RunID, "err", err)
+		fmt.Printf("❌ Cannot write run log: %v\n", err)
+	}
+
+	fmt.Printf("\n📁 Run %s saved to %s\n", r.Meta.RunID, dir)
+	return dir
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"steadyq/internal/runner"
+)
+
+// DefaultHistoryFile is where scheduled runs append their results
+const DefaultHistoryFile = "steadyq_history.jsonl"
+
+// ScheduleOptions controls when Schedule repeats a run
+type ScheduleOptions struct {
+	Every   time.Duration // Fixed interval between run starts, first run immediately
+	Cron    string        // 5-field cron expression (overrides Every)
+	Count   int           // Stop after this many runs (0 = forever)
+	History string        // JSON Lines file each result is appended to
+	Remote  string        // Team-shared history each result is also pushed to (see Config.HistoryRemote)
+}
+
+// HistoryEntry is one line of the history file
+type HistoryEntry struct {
+	StartedAt time.Time `json:"started_at"`
+	Name      string    `json:"name,omitempty"`
+	Label     string    `json:"label"`
+	Target    string    `json:"target"`
+	Requests  uint64    `json:"requests"`
+	Success   uint64    `json:"success"`
+	Fail      uint64    `json:"fail"`
+	ErrorRate float64   `json:"error_rate"` // Percent
+	RPS       float64   `json:"rps"`
+	P50Ms     float64   `json:"p50_ms"`
+	P90Ms     float64   `json:"p90_ms"`
+	P99Ms     float64   `json:"p99_ms"`
+	RunID     string    `json:"run_id,omitempty"`
+	RunDir    string    `json:"run_dir,omitempty"`  // Config, log and reports of this run (--runs-dir)
+	Reports   string    `json:"reports,omitempty"`  // Report prefix of this run
+	Error     string    `json:"error,omitempty"`    // Why the run did not happen (preflight)
+	Aborted   string    `json:"aborted,omitempty"`  // Why an abort condition (or "interrupted": Ctrl+C) stopped the run early
+	Verdict   string    `json:"verdict,omitempty"`  // "pass" or "fail": the thresholds' or the operator's call
+	Note      string    `json:"note,omitempty"`     // One line of human context, e.g. "deployed build abc123"
+	Imported  string    `json:"imported,omitempty"` // Archive the entry was imported from (see ImportHistory)
+
+	// Generator host, git SHA and --meta values of the run (nil if it never started)
+	Meta *runner.RunMetadata `json:"meta,omitempty"`
+}
+
+// CheckSchedule validates schedule options before the first run
+func CheckSchedule(opts ScheduleOptions) error {
+	if opts.Remote != "" {
+		if err := CheckHistoryRemote(opts.Remote); err != nil {
+			return err
+		}
+	}
+	if opts.Cron != "" {
+		cron, err := parseCron(opts.Cron)
+		if err != nil {
+			return err
+		}
+		if cron.next(time.Now()).IsZero() {
+			return fmt.Errorf("cron %q never fires (no such date)", opts.Cron)
+		}
+		return nil
+	}
+	if opts.Every <= 0 {
+		return fmt.Errorf("set --every (e.g. 15m) or --cron (e.g. \"*/15 * * * *\")")
+	}
+	return nil
+}
+
+// Schedule runs cfg headlessly on a cron expression or fixed interval and
+// appends each result to the history file, until Count runs or Ctrl+C between
+// runs. Ctrl+C during a run reports it and exits, like a single run.
+func Schedule(cfg runner.Config, opts ScheduleOptions) error {
+	if err := CheckSchedule(opts); err != nil {
+		return err
+	}
+	var cron *cronSpec
+	if opts.Cron != "" {
+		cron, _ = parseCron(opts.Cron)
+	}
+	history := opts.History
+	if history == "" {
+		history = DefaultHistoryFile
+	}
+	if opts.Remote == "" {
+		opts.Remote = cfg.HistoryRemote
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	start := time.Now()
+	for n := 1; opts.Count == 0 || n <= opts.Count; n++ {
+		// Runs that would have started while the previous one ran are skipped
+		now := time.Now()
+		next := now
+		if cron != nil {
+			if next = cron.next(now); next.IsZero() {
+				return fmt.Errorf("cron %q never fires again", opts.Cron)
+			}
+		} else if n > 1 {
+			next = start.Add(now.Sub(start).Truncate(opts.Every) + opts.Every)
+		}
+
+		if wait := time.Until(next); wait > 0 {
+			fmt.Printf("\n⏰ Run %d at %s (in %s), Ctrl+C to stop\n", n, next.Format("2006-01-02 15:04:05"), wait.Round(time.Second))
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-sigCh:
+				timer.Stop()
+				fmt.Printf("\nSchedule stopped after %d runs\n", n-1)
+				return nil
+			}
+		}
+
+		rep := newReportSink(cfg)
+		out, err := execute(cfg, rep, &historySink{path: history, remote: opts.Remote, n: n, rep: rep})
+		if err != nil {
+			// Never ran, so no sink recorded it
+			entry := newHistoryEntry(cfg)
+			entry.Error = err.Error()
+			fmt.Printf("   Skipping run %d.\n", n)
+			writeHistory(history, n, entry)
+		} else if out.Interrupted {
+			// The sink has recorded the partial run
+			os.Exit(130)
+		}
+	}
+	return nil
+}
+
+// newHistoryEntry starts the history line of a run of cfg
+func newHistoryEntry(cfg runner.Config) HistoryEntry {
+	entry := HistoryEntry{StartedAt: time.Now(), Name: cfg.Name, Label: cfg.GetLabel(), Target: cfg.URL}
+	if cfg.Command != "" {
+		entry.Target = cfg.Command
+	}
+	return entry
+}
+
+func fillHistoryEntry(e *HistoryEntry, out outcome) {
+	e.StartedAt = out.Runner.Meta.StartedAt
+	s := out.Summary
+	e.Aborted = out.Runner.AbortReason()
+	if e.Aborted == "" && out.Interrupted {
+		e.Aborted = "interrupted"
+	}
+	e.RunID, e.RunDir = out.Runner.Meta.RunID, out.RunDir
+	meta := out.Runner.Meta
+	e.Meta = &meta
+	e.Requests, e.Success, e.Fail = s.TotalRequests, s.TotalSuccess, s.TotalFail
+	e.ErrorRate, e.RPS = s.ErrorRate, s.AverageRPS
+	e.P50Ms, e.P90Ms, e.P99Ms = s.Service.P50, s.Service.P90, s.Service.P99
+	e.Reports = out.Reports
+	if len(s.Thresholds) > 0 {
+		e.Verdict = verdict(s.ThresholdsPassed())
+	}
+}
+
+func verdict(pass bool) string {
+	if pass {
+		return "pass"
+	}
+	return "fail"
+}
+
+// appendHistory adds one JSON line to the history file, creating it if needed
+func appendHistory(path string, e HistoryEntry) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewEncoder(f).Encode(e)
+}
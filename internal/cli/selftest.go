@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"steadyq/internal/runner"
+)
+
+// SelfTestOptions configures steadyq selftest
+type SelfTestOptions struct {
+	Step   time.Duration // Time spent at each rate
+	MaxRPS int           // Highest rate tried
+	Pacing string        // Open-loop pacing strategy under test
+	Engine string        // HTTP client library under test (runner.HTTPEngines)
+}
+
+// selfTestRates is the rate ladder, cut at MaxRPS
+var selfTestRates = []int{500, 1000, 2000, 5000, 10000, 20000, 50000, 100000, 200000, 500000}
+
+// A step keeps pace when it sends this share of its target on schedule
+const (
+	selfTestMinPacing = 95.0                  // % of target sent
+	selfTestMaxSkip   = 1.0                   // % of intended requests not sent
+	selfTestMaxLagP99 = 10 * time.Millisecond // scheduled start to actual send
+)
+
+// selfTestStep is the outcome of one rate
+type selfTestStep struct {
+	Target   int
+	Achieved float64 // Requests sent per second
+	Pacing   float64 // Achieved as % of Target
+	Skipped  float64 // % of intended requests the scheduler dropped
+	LagAvg   time.Duration
+	LagP99   time.Duration
+	CPU      float64 // Peak CPU of this process, % of all cores
+}
+
+func (s selfTestStep) keptPace() bool {
+	return s.Pacing >= selfTestMinPacing && s.Skipped < selfTestMaxSkip && s.LagP99 < selfTestMaxLagP99
+}
+
+// CheckSelfTest validates the self-test options
+func CheckSelfTest(opts SelfTestOptions) error {
+	if opts.Step < time.Second {
+		return fmt.Errorf("--step must be at least 1s")
+	}
+	if opts.MaxRPS < selfTestRates[0] {
+		return fmt.Errorf("--max-rps must be at least %d", selfTestRates[0])
+	}
+	if pacing := (runner.Config{Pacing: opts.Pacing}).GetPacing(); !slices.Contains(runner.PacingStrategies, pacing) {
+		return fmt.Errorf("unknown pacing %q (use %s)", opts.Pacing, strings.Join(runner.PacingStrategies, ", "))
+	}
+	if opts.Engine != "" && !slices.Contains(runner.HTTPEngines, opts.Engine) {
+		return fmt.Errorf("unknown HTTP engine %q (use %s)", opts.Engine, strings.Join(runner.HTTPEngines, ", "))
+	}
+	return nil
+}
+
+// SelfTest measures this machine's generator ceiling: open-loop runs at
+// rising rates against an in-process handler that does no work, until a step
+// no longer keeps pace. Whatever limits those runs is the generator (CPU,
+// scheduler, loopback), not a server.
+func SelfTest(opts SelfTestOptions) error {
+	if err := CheckSelfTest(opts); err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("starting the in-process target: %v", err)
+	}
+	defer ln.Close()
+	go http.Serve(ln, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	url := "http://" + ln.Addr().String() + "/"
+
+	fmt.Printf("\n🧪 GENERATOR SELF-TEST\n")
+	fmt.Printf("======================================================================\n")
+	fmt.Printf("Target     : in-process handler on %s (no work, loopback only)\n", ln.Addr())
+	fmt.Printf("Machine    : %s/%s, %d CPUs, GOMAXPROCS %d\n", runtime.GOOS, runtime.GOARCH, runtime.NumCPU(), runtime.GOMAXPROCS(0))
+	fmt.Printf("Steps      : %s each, up to %d RPS, %s pacing, %s\n\n", opts.Step, opts.MaxRPS, runner.Config{Pacing: opts.Pacing}.GetPacing(), runner.Config{HTTPEngine: opts.Engine}.GetHTTPEngine())
+	fmt.Printf("   %10s  %10s  %7s  %8s  %9s  %9s  %5s\n", "Target RPS", "Achieved", "Pacing", "Not Sent", "Lag avg", "Lag P99", "CPU")
+
+	var best, peak selfTestStep
+	for _, rate := range selfTestRates {
+		if rate > opts.MaxRPS {
+			break
+		}
+		s := selfTestRun(url, rate, opts)
+		mark := "✅"
+		if !s.keptPace() {
+			mark = "❌"
+		}
+		fmt.Printf("%s %10d  %10.1f  %6.1f%%  %7.2f%%  %9s  %9s  %4.0f%%\n",
+			mark, s.Target, s.Achieved, s.Pacing, s.Skipped, s.LagAvg.Round(time.Microsecond), s.LagP99.Round(time.Microsecond), s.CPU)
+		if s.Achieved > peak.Achieved {
+			peak = s
+		}
+		if !s.keptPace() {
+			break
+		}
+		best = s
+	}
+
+	fmt.Println()
+	if best.Target == 0 {
+		fmt.Printf("⚠️  The generator could not keep pace even at %d RPS on this machine.\n", selfTestRates[0])
+	} else {
+		fmt.Printf("Ceiling    : ~%d RPS (highest step with ≥%.0f%% sent, <%.0f%% not sent, P99 lag < %s)\n",
+			best.Target, selfTestMinPacing, selfTestMaxSkip, selfTestMaxLagP99)
+	}
+	fmt.Printf("Peak       : %.0f RPS sent (at a target of %d)\n", peak.Achieved, peak.Target)
+	fmt.Printf("   → Against a real server each request costs more (TLS, bodies, latency), so plan\n")
+	fmt.Printf("     to stay well below the ceiling; if a run nears it, the client is the bottleneck.\n")
+	fmt.Printf("======================================================================\n")
+	return nil
+}
+
+// selfTestRun runs one open-loop step at rate and measures how well it kept pace
+func selfTestRun(url string, rate int, opts SelfTestOptions) selfTestStep {
+	cfg := runner.Config{
+		URL:        url,
+		Method:     "GET",
+		Mode:       "rps",
+		TargetRPS:  rate,
+		SteadyDur:  int(opts.Step / time.Second),
+		Pacing:     opts.Pacing,
+		HTTPEngine: opts.Engine,
+		Name:       "selftest",
+	}
+	r := runner.NewRunner(cfg, nil)
+	r.Run(context.Background())
+
+	dur := time.Duration(cfg.SteadyDur) * time.Second
+	sent := atomic.LoadUint64(&r.Stats.Requests)
+	skipped := atomic.LoadUint64(&r.Stats.SchedulerSkipped)
+	s := selfTestStep{
+		Target:   rate,
+		Achieved: float64(sent) / dur.Seconds(),
+		LagAvg:   time.Duration(r.Stats.QueueWaitAvgMs() * float64(time.Millisecond)),
+		LagP99:   time.Duration(r.Stats.QueueWait.ValueAtQuantile(99)) * time.Microsecond,
+	}
+	s.Pacing = s.Achieved / float64(rate) * 100
+	if intended := sent + skipped; intended > 0 {
+		s.Skipped = float64(skipped) / float64(intended) * 100
+	}
+	if r.Self != nil {
+		for _, sample := range r.Self.Samples(0) {
+			s.CPU = max(s.CPU, sample.CPUPercent)
+		}
+	}
+	return s
+}
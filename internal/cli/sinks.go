@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"steadyq/internal/report"
+	"steadyq/internal/runner"
+)
+
+// reportSink prints the summary and writes the run directory and reports
+// when a headless run completes
+type reportSink struct {
+	cfg       runner.Config
+	start     time.Time // Set by execute when the run starts
+	stoppedAt time.Time // Ctrl+C: the run is reported up to here
+
+	// The progress line and the summary share the terminal
+	console  sync.Mutex
+	complete bool
+
+	// Filled in on completion
+	elapsed time.Duration
+	summary report.Summary
+	runDir  string
+	reports string
+}
+
+func newReportSink(cfg runner.Config) *reportSink {
+	return &reportSink{cfg: cfg}
+}
+
+func (s *reportSink) OnResult(runner.ExperimentResult) {}
+
+func (s *reportSink) OnInterval(runner.StatsSnapshot) {}
+
+func (s *reportSink) OnComplete(r *runner.Runner) {
+	s.console.Lock()
+	defer s.console.Unlock()
+	s.complete = true
+
+	s.elapsed = time.Since(s.start)
+	if !s.stoppedAt.IsZero() {
+		s.elapsed = s.stoppedAt.Sub(s.start)
+		total := time.Duration(s.cfg.RampUp+s.cfg.SteadyDur+s.cfg.RampDown) * time.Second
+		fmt.Printf("Partial run: stopped after %s of %s\n", s.elapsed.Round(time.Second), total)
+	}
+	results := r.SnapshotResults()
+	s.summary = report.Summarize(results, s.elapsed)
+	s.summary.ResultsDropped = atomic.LoadUint64(&r.Stats.ResultsDropped)
+	if d := s.summary.ResultsDropped; d > 0 && s.summary.Duration > 0 {
+		// The rate is of the whole run, dropped results included
+		s.summary.AverageRPS = float64(s.summary.TotalRequests+d) / s.summary.Duration.Seconds()
+	}
+	s.summary.Percentiles = report.ServicePercentiles(results, s.cfg.GetPercentiles())
+	s.summary.Thresholds = report.CheckThresholds(s.summary, s.cfg.GetThresholds())
+	s.summary.Mirror = report.CompareMirror(results, r.MirrorResults())
+	inflightAvg, inflightMax := r.Stats.InflightAvg()
+	s.summary.Concurrency = report.LittlesLaw(s.summary, results, inflightAvg, inflightMax)
+	printSummary(os.Stdout, r, s.summary)
+	s.runDir = writeRunDir(r, s.cfg, s.summary)
+	s.reports = handleAutoReport(r, s.cfg, s.runDir, results, s.summary)
+}
+
+// holdConsole reserves the terminal for a progress update, false once the
+// run has completed. Pair with releaseConsole.
+func (s *reportSink) holdConsole() bool {
+	s.console.Lock()
+	if s.complete {
+		s.console.Unlock()
+		return false
+	}
+	return true
+}
+
+func (s *reportSink) releaseConsole() {
+	s.console.Unlock()
+}
+
+// outcome is what the completed run produced
+func (s *reportSink) outcome(r *runner.Runner) outcome {
+	return outcome{Runner: r, Elapsed: s.elapsed, Summary: s.summary, Reports: s.reports, RunDir: s.runDir,
+		Interrupted: !s.stoppedAt.IsZero()}
+}
+
+// historySink appends each completed scheduled run to the history file,
+// after rep has written its reports
+type historySink struct {
+	path   string
+	remote string // Config.HistoryRemote, "" when not shared
+	n      int    // Run number in the schedule
+	rep    *reportSink
+}
+
+func (s *historySink) OnResult(runner.ExperimentResult) {}
+
+func (s *historySink) OnInterval(runner.StatsSnapshot) {}
+
+func (s *historySink) OnComplete(r *runner.Runner) {
+	entry := newHistoryEntry(r.Cfg)
+	fillHistoryEntry(&entry, s.rep.outcome(r))
+	writeHistory(s.path, s.n, entry)
+	if s.remote != "" {
+		shareHistory(s.remote, entry)
+	}
+}
+
+// writeHistory appends entry and reports where it went
+func writeHistory(path string, n int, entry HistoryEntry) {
+	if err := appendHistory(path, entry); err != nil {
+		slog.Error("history not appended", "file", path, "err", err)
+		fmt.Printf("❌ Cannot append to history: %v\n", err)
+	} else {
+		fmt.Printf("📈 Run %d appended to %s\n", n, path)
+	}
+}
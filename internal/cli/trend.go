@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Trend is the history of one test: the runs sharing a label, or a target
+// for unlabelled runs, oldest first
+type Trend struct {
+	Key  string // The label, or the target
+	Runs []HistoryEntry
+}
+
+// HistoryTrends groups the entries that ran into trends, ordered by key.
+// With a filter only labels or targets containing it are kept; last > 0
+// keeps the newest last runs of each.
+func HistoryTrends(entries []HistoryEntry, filter string, last int) []Trend {
+	groups := map[string][]HistoryEntry{}
+	for _, e := range entries {
+		if e.Error != "" || e.Requests == 0 {
+			continue // Skipped by the preflight, or nothing was measured
+		}
+		key := e.Label
+		if key == "" {
+			key = e.Target
+		}
+		if filter != "" && !strings.Contains(e.Label, filter) && !strings.Contains(e.Target, filter) {
+			continue
+		}
+		groups[key] = append(groups[key], e)
+	}
+
+	trends := make([]Trend, 0, len(groups))
+	for key, runs := range groups {
+		slices.SortStableFunc(runs, func(a, b HistoryEntry) int { return a.StartedAt.Compare(b.StartedAt) })
+		if last > 0 && len(runs) > last {
+			runs = runs[len(runs)-last:]
+		}
+		trends = append(trends, Trend{Key: key, Runs: runs})
+	}
+	slices.SortFunc(trends, func(a, b Trend) int { return strings.Compare(a.Key, b.Key) })
+	return trends
+}
+
+// PrintTrends writes each trend as p99 and error rate sparklines over its
+// runs, then one row per run
+func PrintTrends(w io.Writer, trends []Trend) {
+	for _, t := range trends {
+		p99 := make([]float64, len(t.Runs))
+		errRate := make([]float64, len(t.Runs))
+		for i, e := range t.Runs {
+			p99[i], errRate[i] = e.P99Ms, e.ErrorRate
+		}
+		first, last := t.Runs[0], t.Runs[len(t.Runs)-1]
+
+		fmt.Fprintf(w, "\n📈 %s: %d runs, %s → %s\n", t.Key, len(t.Runs),
+			first.StartedAt.Local().Format("2006-01-02"), last.StartedAt.Local().Format("2006-01-02"))
+		fmt.Fprintf(w, "   %-8s %s  %s\n", "P99 ms", sparkline(p99), trendSummary(p99))
+		fmt.Fprintf(w, "   %-8s %s  %s\n", "Error %", sparkline(errRate), trendSummary(errRate))
+		fmt.Fprintf(w, "\n   %-16s %10s %10s %8s  %s\n", "Started", "Requests", "P99 ms", "Error %", "Verdict")
+		for _, e := range t.Runs {
+			verdict := e.Verdict
+			if e.Aborted != "" {
+				verdict = strings.TrimSpace(verdict + " (aborted: " + e.Aborted + ")")
+			}
+			fmt.Fprintf(w, "   %-16s %10d %10.2f %8.2f  %s\n",
+				e.StartedAt.Local().Format("2006-01-02 15:04"), e.Requests, e.P99Ms, e.ErrorRate, verdict)
+		}
+	}
+}
+
+// trendSummary is the newest value against the median, min and max of vals
+func trendSummary(vals []float64) string {
+	sorted := slices.Clone(vals)
+	slices.Sort(sorted)
+	return fmt.Sprintf("last %.2f  median %.2f  min %.2f  max %.2f",
+		vals[len(vals)-1], sorted[len(sorted)/2], sorted[0], sorted[len(sorted)-1])
+}
+
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws vals as one block per value, scaled from 0 to their max
+func sparkline(vals []float64) string {
+	top := slices.Max(vals)
+	var b strings.Builder
+	for _, v := range vals {
+		i := 0
+		if top > 0 {
+			i = min(int(v/top*float64(len(sparkLevels)-1)+0.5), len(sparkLevels)-1)
+		}
+		b.WriteRune(sparkLevels[i])
+	}
+	return b.String()
+}
+
+// HistoryTrend prints the trends of a history file (see HistoryTrends)
+func HistoryTrend(w io.Writer, history, filter string, last int) error {
+	entries, err := ReadHistory(history)
+	if err != nil {
+		return err
+	}
+	trends := HistoryTrends(entries, filter, last)
+	if len(trends) == 0 && filter != "" {
+		return fmt.Errorf("no measured run in %s matches %q", history, filter)
+	}
+	if len(trends) == 0 {
+		return fmt.Errorf("no measured run in %s", history)
+	}
+	PrintTrends(w, trends)
+	return nil
+}
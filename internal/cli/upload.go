@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// uploadArtifacts copies report files to s3://bucket/prefix or gs://bucket/prefix.
+// It shells out to the cloud CLI already configured on CI runners (aws, gsutil or gcloud)
+// so credentials, profiles and regions work exactly as they do for other pipeline steps.
+func uploadArtifacts(dest string, files []string) error {
+	dest = strings.TrimSuffix(dest, "/")
+
+	var tool []string
+	switch {
+	case strings.HasPrefix(dest, "s3://"):
+		if _, err := exec.LookPath("aws"); err != nil {
+			return fmt.Errorf("uploading to %s needs the aws CLI in PATH", dest)
+		}
+		tool = []string{"aws", "s3", "cp", "--only-show-errors"}
+	case strings.HasPrefix(dest, "gs://"):
+		if _, err := exec.LookPath("gsutil"); err == nil {
+			tool = []string{"gsutil", "-q", "cp"}
+		} else if _, err := exec.LookPath("gcloud"); err == nil {
+			tool = []string{"gcloud", "storage", "cp", "--quiet"}
+		} else {
+			return fmt.Errorf("uploading to %s needs gsutil or gcloud in PATH", dest)
+		}
+	default:
+		return fmt.Errorf("unsupported upload destination %q (use s3:// or gs://)", dest)
+	}
+
+	for _, f := range files {
+		if _, err := os.Stat(f); err != nil {
+			continue // Optional artifact not generated
+		}
+		target := dest + "/" + filepath.Base(f)
+		args := append(append([]string{}, tool[1:]...), f, target)
+		cmd := exec.Command(tool[0], args...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("upload %s: %w", f, err)
+		}
+		fmt.Printf("   ☁️  %s\n", target)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"steadyq/internal/runner"
+)
+
+// DefaultWatchRequests bounds each --watch run unless the plan sets MaxRequests
+const DefaultWatchRequests = 20
+
+// watchPoll is how often the watched file is checked, and how long a change
+// is left to settle before reloading (editors write in several steps)
+const watchPoll = 300 * time.Millisecond
+
+// Watch runs the config returned by load, then again every time the file at
+// path changes, until Ctrl+C. Runs write no reports; a config that fails to
+// load is reported and the watch carries on.
+func Watch(path string, load func() (runner.Config, error)) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	last := fileStamp(path)
+	for {
+		cfg, err := load()
+		if err != nil {
+			fmt.Printf("\n❌ %s: %v\n", path, err)
+		} else {
+			// Validation runs, not results worth keeping
+			cfg.OutPrefix, cfg.OutDir, cfg.RunsDir, cfg.UploadTo, cfg.Bundle = "", "", "", "", false
+			// A failed preflight has been printed, wait for a fix
+			if out, err := execute(cfg, newReportSink(cfg)); err == nil && out.Interrupted {
+				os.Exit(130)
+			}
+		}
+
+		fmt.Printf("\n👀 Watching %s for changes (Ctrl+C to stop)\n", path)
+		for changed := false; !changed; {
+			select {
+			case <-sigCh:
+				fmt.Println()
+				return
+			case <-time.After(watchPoll):
+			}
+			if stamp := fileStamp(path); stamp != last {
+				time.Sleep(watchPoll)
+				last, changed = fileStamp(path), true
+			}
+		}
+		fmt.Printf("\n🔁 %s changed, re-running\n", path)
+	}
+}
+
+// fileStamp identifies a version of a file by size and modification time, "" if missing
+func fileStamp(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d@%d", info.Size(), info.ModTime().UnixNano())
+}
@@ -0,0 +1,41 @@
+package dummy
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// cachedVersion is how long one version of /cached lives before its ETag changes
+const cachedVersion = 10 * time.Second
+
+// handleCached serves a cacheable document that changes every cachedVersion.
+// It sends ETag and Last-Modified and answers matching If-None-Match or
+// If-Modified-Since with a fast 304; full responses take 20ms.
+func handleCached(w http.ResponseWriter, r *http.Request) {
+	modified := time.Now().Truncate(cachedVersion).UTC()
+	etag := fmt.Sprintf(`"v%d"`, modified.Unix())
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+	w.Header().Set("Cache-Control", "no-cache")
+
+	notModified := false
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			if t := strings.TrimSpace(tag); t == etag || t == "*" {
+				notModified = true
+			}
+		}
+	} else if ims, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !modified.After(ims) {
+		notModified = true
+	}
+	if notModified {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write([]byte(strings.Repeat("cacheable document\n", 200)))
+}
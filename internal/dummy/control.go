@@ -0,0 +1,127 @@
+package dummy
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// faults are injected into every endpoint and changed at runtime via /control
+type faults struct {
+	Latency   time.Duration // Added to every response
+	Jitter    time.Duration // Random extra latency, 0 to Jitter
+	ErrorRate float64       // Percent of requests answered with ErrorCode
+	ErrorCode int
+}
+
+// control holds the current faults for the /control endpoint and middleware
+type control struct {
+	mu     sync.RWMutex
+	faults faults
+}
+
+func newControl() *control {
+	return &control{faults: faults{ErrorCode: http.StatusInternalServerError}}
+}
+
+func (c *control) get() faults {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.faults
+}
+
+// wrap applies the current faults in front of next; /control itself is exempt
+func (c *control) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/control") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		f := c.get()
+		delay := f.Latency
+		if f.Jitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(f.Jitter)))
+		}
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if f.ErrorRate > 0 && rand.Float64()*100 < f.ErrorRate {
+			w.WriteHeader(f.ErrorCode)
+			fmt.Fprintf(w, "%d injected by /control", f.ErrorCode)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleControl shows the faults (GET), changes the given ones (POST
+// ?latency=200ms&jitter=50ms&error_rate=10&error_code=503) or clears them
+// all (DELETE, or POST /control/reset)
+func (c *control) handleControl(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodDelete || (r.Method == http.MethodPost && r.URL.Path == "/control/reset"):
+		c.mu.Lock()
+		c.faults = faults{ErrorCode: http.StatusInternalServerError}
+		c.mu.Unlock()
+		fmt.Println("🎛  /control: reset")
+	case r.Method == http.MethodPost:
+		f := c.get()
+		if err := f.apply(r); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		c.mu.Lock()
+		c.faults = f
+		c.mu.Unlock()
+		fmt.Printf("🎛  /control: latency=%s jitter=%s error_rate=%g%% error_code=%d\n", f.Latency, f.Jitter, f.ErrorRate, f.ErrorCode)
+	case r.Method != http.MethodGet:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET, POST or DELETE"})
+		return
+	}
+	f := c.get()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"latency":    f.Latency.String(),
+		"jitter":     f.Jitter.String(),
+		"error_rate": f.ErrorRate,
+		"error_code": f.ErrorCode,
+	})
+}
+
+// apply overrides f with the parameters present in the request
+func (f *faults) apply(r *http.Request) error {
+	for _, d := range []struct {
+		name string
+		dst  *time.Duration
+	}{{"latency", &f.Latency}, {"jitter", &f.Jitter}} {
+		if v := r.FormValue(d.name); v != "" {
+			dur, err := time.ParseDuration(v)
+			if err != nil || dur < 0 {
+				return fmt.Errorf("%s must be a duration like 200ms", d.name)
+			}
+			*d.dst = dur
+		}
+	}
+	if v := r.FormValue("error_rate"); v != "" {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil || rate < 0 || rate > 100 {
+			return fmt.Errorf("error_rate must be a percentage between 0 and 100")
+		}
+		f.ErrorRate = rate
+	}
+	if v := r.FormValue("error_code"); v != "" {
+		code, err := strconv.Atoi(v)
+		if err != nil || code < 100 || code > 599 {
+			return fmt.Errorf("error_code must be an HTTP status between 100 and 599")
+		}
+		f.ErrorCode = code
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package dummy
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Defaults for the /limited endpoint
+const (
+	DefaultWorkers  = 4
+	DefaultQueue    = 16
+	DefaultWorkTime = 50 * time.Millisecond
+)
+
+func (c ServerConfig) GetWorkers() int {
+	if c.Workers <= 0 {
+		return DefaultWorkers
+	}
+	return c.Workers
+}
+
+func (c ServerConfig) GetQueue() int {
+	if c.Queue < 0 {
+		return 0
+	}
+	if c.Queue == 0 {
+		return DefaultQueue
+	}
+	return c.Queue
+}
+
+func (c ServerConfig) GetWorkTime() time.Duration {
+	if c.WorkTime <= 0 {
+		return DefaultWorkTime
+	}
+	return c.WorkTime
+}
+
+// limitedHandler models a server with a fixed worker pool and a bounded
+// queue: requests wait for a worker while the queue has room and get 503
+// once it is full. Capacity is Workers/WorkTime requests per second; above
+// that, open-loop load fills the queue (latency climbs to the queue depth
+// times WorkTime, then 503s) while closed-loop load just slows down.
+func limitedHandler(cfg ServerConfig) http.HandlerFunc {
+	workers := make(chan struct{}, cfg.GetWorkers())
+	admitted := make(chan struct{}, cfg.GetWorkers()+cfg.GetQueue()) // Working or queued
+	work := cfg.GetWorkTime()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case admitted <- struct{}{}:
+		default:
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("503 Service Unavailable: queue full"))
+			return
+		}
+		defer func() { <-admitted }()
+
+		queued := time.Now()
+		select {
+		case workers <- struct{}{}:
+		case <-r.Context().Done():
+			return
+		}
+		wait := time.Since(queued)
+		time.Sleep(work)
+		<-workers
+
+		w.Header().Set("X-Queue-Wait", wait.Round(time.Microsecond).String())
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "Limited response (queued %s)", wait.Round(time.Millisecond))
+	}
+}
@@ -1,17 +1,103 @@
 package dummy
 
 import (
+	"crypto/tls"
 	"fmt"
 	"math/rand"
+	"net"
 	"net/http"
+	"os"
 	"time"
 )
 
 type ServerConfig struct {
 	Port int
+
+	// HTTPS with HTTP/2 on a self-signed certificate (0 = off)
+	TLSPort int
+	CertOut string // Write the certificate PEM here, for --ca-file
+
+	// /limited: worker pool, queue slots beyond it (-1 = none) and time per request
+	Workers  int
+	Queue    int
+	WorkTime time.Duration
 }
 
 func Start(cfg ServerConfig) {
+	addr := fmt.Sprintf(":%d", cfg.Port)
+	fmt.Printf("👻 Dummy Server running on http://localhost%s\n", addr)
+	fmt.Println("   Endpoints: /fast, /medium, /slow, /spike, /error, /proto, /ws, /sse")
+	fmt.Printf("   Limited:   /limited (%d workers, queue %d, %s each = %.0f RPS capacity, 503 when full)\n",
+		cfg.GetWorkers(), cfg.GetQueue(), cfg.GetWorkTime(), float64(cfg.GetWorkers())/cfg.GetWorkTime().Seconds())
+	fmt.Println("   Flow:      /login → /cart → /checkout (session cookie)")
+	fmt.Println("   Control:   POST /control?latency=200ms&jitter=50ms&error_rate=10&error_code=503, DELETE /control to reset")
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: Handler(cfg),
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("Server failed: %v\n", err)
+		}
+	}()
+
+	if cfg.TLSPort > 0 {
+		startTLS(cfg)
+	}
+}
+
+// startTLS serves the same endpoints over HTTPS, negotiating h2 via ALPN
+func startTLS(cfg ServerConfig) {
+	cert, certPEM, err := selfSigned()
+	if err != nil {
+		fmt.Printf("TLS certificate failed: %v\n", err)
+		return
+	}
+	if cfg.CertOut != "" {
+		if err := os.WriteFile(cfg.CertOut, certPEM, 0644); err != nil {
+			fmt.Printf("Error writing certificate: %v\n", err)
+			return
+		}
+	}
+
+	addr := fmt.Sprintf(":%d", cfg.TLSPort)
+	fmt.Printf("🔒 TLS (h2, http/1.1) on https://localhost%s (self-signed", addr)
+	if cfg.CertOut != "" {
+		fmt.Printf(", trust it with --ca-file %s", cfg.CertOut)
+	} else {
+		fmt.Printf(", use --insecure or --cert-out")
+	}
+	fmt.Println(")")
+
+	// TLSNextProto left nil: net/http adds "h2" to NextProtos
+	server := &http.Server{
+		Addr:      addr,
+		Handler:   Handler(cfg),
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+	}
+
+	go func() {
+		if err := server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("TLS server failed: %v\n", err)
+		}
+	}()
+}
+
+// Listen serves the dummy endpoints on addr (":0" picks a free port) in the
+// background without printing anything, and returns the server's base URL
+func Listen(addr string) (string, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", err
+	}
+	go http.Serve(ln, Handler(ServerConfig{}))
+	return "http://" + ln.Addr().String(), nil
+}
+
+// Handler returns the dummy endpoints
+func Handler(cfg ServerConfig) http.Handler {
 	mux := http.NewServeMux()
 
 	// 1. Fast Endpoint (10-50ms)
@@ -57,6 +143,7 @@ func Start(cfg ServerConfig) {
 			w.WriteHeader(http.StatusInternalServerError)
 			w.Write([]byte("500 Internal Server Error"))
 		} else if rnd < 0.4 {
+			w.Header().Set("Retry-After", "1")
 			w.WriteHeader(http.StatusTooManyRequests)
 			w.Write([]byte("429 Too Many Requests"))
 		} else {
@@ -65,18 +152,36 @@ func Start(cfg ServerConfig) {
 		}
 	})
 
-	addr := fmt.Sprintf(":%d", cfg.Port)
-	fmt.Printf("👻 Dummy Server running on http://localhost%s\n", addr)
-	fmt.Println("   Endpoints: /fast, /medium, /slow, /spike, /error")
+	// 6. Protocol Endpoint: what the client negotiated (HTTP/2, TLS version, ALPN)
+	mux.HandleFunc("/proto", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprintf(w, "proto=%s", r.Proto)
+		if r.TLS != nil {
+			fmt.Fprintf(w, " tls=%s alpn=%s sni=%s", tls.VersionName(r.TLS.Version), r.TLS.NegotiatedProtocol, r.TLS.ServerName)
+		}
+	})
 
-	server := &http.Server{
-		Addr:    addr,
-		Handler: mux,
-	}
+	// 7. Streaming Endpoints: WebSocket echo and Server-Sent Events
+	mux.HandleFunc("/ws", handleWS)
+	mux.HandleFunc("/sse", handleSSE)
 
-	go func() {
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			fmt.Printf("Server failed: %v\n", err)
-		}
-	}()
+	// 8. Stateful Flow: /login sets a session cookie, /cart needs it,
+	// /checkout needs it and a non-empty cart
+	store := newSessionStore()
+	mux.HandleFunc("/login", store.handleLogin)
+	mux.HandleFunc("/cart", store.handleCart)
+	mux.HandleFunc("/checkout", store.handleCheckout)
+
+	// 9. Concurrency-Limited Endpoint: worker pool + bounded queue, 503 when full
+	mux.HandleFunc("/limited", limitedHandler(cfg))
+
+	// 10. Cacheable Endpoint: ETag/Last-Modified, 304 on revalidation
+	mux.HandleFunc("/cached", handleCached)
+
+	// 11. Control Endpoint: inject latency and errors into all of the above mid-run
+	ctl := newControl()
+	mux.HandleFunc("/control", ctl.handleControl)
+	mux.HandleFunc("/control/reset", ctl.handleControl)
+
+	return ctl.wrap(mux)
 }
@@ -0,0 +1,138 @@
+package dummy
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// sessionCookie carries the session ID set by /login
+const sessionCookie = "sid"
+
+// sessionTTL expires sessions idle this long
+const sessionTTL = 10 * time.Minute
+
+// session is one logged-in user's cart
+type session struct {
+	User     string
+	Cart     []string
+	LastSeen time.Time
+}
+
+// sessionStore backs the /login → /cart → /checkout flow
+type sessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*session
+	orders   int
+}
+
+func newSessionStore() *sessionStore {
+	return &sessionStore{sessions: make(map[string]*session)}
+}
+
+// lookup returns the request's live session, nil without a valid cookie
+func (s *sessionStore) lookup(r *http.Request) *session {
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return nil
+	}
+	sess := s.sessions[c.Value]
+	if sess == nil || time.Since(sess.LastSeen) > sessionTTL {
+		delete(s.sessions, c.Value)
+		return nil
+	}
+	sess.LastSeen = time.Now()
+	return sess
+}
+
+// sweep drops expired sessions so long runs do not grow the store forever
+func (s *sessionStore) sweep() {
+	for id, sess := range s.sessions {
+		if time.Since(sess.LastSeen) > sessionTTL {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// handleLogin starts a session for ?user= (or form field user) and sets its cookie
+func (s *sessionStore) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+		return
+	}
+	user := r.FormValue("user")
+	if user == "" {
+		user = "guest"
+	}
+	var b [16]byte
+	rand.Read(b[:])
+	id := hex.EncodeToString(b[:])
+
+	s.mu.Lock()
+	if len(s.sessions) >= 10000 {
+		s.sweep()
+	}
+	s.sessions[id] = &session{User: user, Cart: []string{}, LastSeen: time.Now()}
+	s.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: id, Path: "/", HttpOnly: true})
+	writeJSON(w, http.StatusOK, map[string]string{"user": user, "session": id})
+}
+
+// handleCart lists the cart (GET) or adds ?item= to it (POST); needs /login first
+func (s *sessionStore) handleCart(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess := s.lookup(r)
+	if sess == nil {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "no session: POST /login first"})
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		item := r.FormValue("item")
+		if item == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "item is required"})
+			return
+		}
+		sess.Cart = append(sess.Cart, item)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use GET or POST"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"user": sess.User, "cart": sess.Cart})
+}
+
+// handleCheckout places an order for the cart and empties it; needs a
+// session and at least one item
+func (s *sessionStore) handleCheckout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess := s.lookup(r)
+	if sess == nil {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "no session: POST /login first"})
+		return
+	}
+	if len(sess.Cart) == 0 {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "cart is empty: POST /cart?item=... first"})
+		return
+	}
+	s.orders++
+	order := map[string]any{"order": s.orders, "user": sess.User, "items": sess.Cart}
+	sess.Cart = []string{}
+	writeJSON(w, http.StatusOK, order)
+}
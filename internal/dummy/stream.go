@@ -0,0 +1,193 @@
+package dummy
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// wsGUID is appended to the client key for Sec-WebSocket-Accept (RFC 6455)
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket opcodes used by the echo endpoint
+const (
+	wsText   = 0x1
+	wsBinary = 0x2
+	wsClose  = 0x8
+	wsPing   = 0x9
+	wsPong   = 0xA
+)
+
+// maxWSFrame bounds an echoed message so a bad client cannot exhaust memory
+const maxWSFrame = 1 << 20
+
+// handleWS upgrades to a WebSocket and echoes every text and binary message
+// back, answering pings and the closing handshake. Fragmented messages are
+// echoed frame by frame.
+func handleWS(w http.ResponseWriter, r *http.Request) {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || r.Header.Get("Sec-WebSocket-Key") == "" {
+		http.Error(w, "websocket upgrade required", http.StatusUpgradeRequired)
+		return
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket needs HTTP/1.1", http.StatusHTTPVersionNotSupported)
+		return
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	sum := sha1.Sum([]byte(r.Header.Get("Sec-WebSocket-Key") + wsGUID))
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n",
+		base64.StdEncoding.EncodeToString(sum[:]))
+	if rw.Flush() != nil {
+		return
+	}
+
+	for {
+		fin, op, payload, err := readWSFrame(rw.Reader)
+		if err != nil {
+			return
+		}
+		switch op {
+		case wsClose:
+			writeWSFrame(rw.Writer, true, wsClose, payload)
+			rw.Flush()
+			return
+		case wsPing:
+			writeWSFrame(rw.Writer, true, wsPong, payload)
+		case wsPong:
+			continue
+		default: // text, binary and continuation frames
+			writeWSFrame(rw.Writer, fin, op, payload)
+		}
+		if rw.Flush() != nil {
+			return
+		}
+	}
+}
+
+// readWSFrame reads one client frame and unmasks its payload
+func readWSFrame(r *bufio.Reader) (fin bool, op byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err = io.ReadFull(r, head[:]); err != nil {
+		return
+	}
+	fin, op = head[0]&0x80 != 0, head[0]&0x0F
+	masked := head[1]&0x80 != 0
+
+	n := uint64(head[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(r, ext[:]); err != nil {
+			return
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(r, ext[:]); err != nil {
+			return
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n > maxWSFrame {
+		return fin, op, nil, fmt.Errorf("frame of %d bytes exceeds %d", n, maxWSFrame)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(r, mask[:]); err != nil {
+			return
+		}
+	}
+	payload = make([]byte, n)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return
+}
+
+// writeWSFrame writes one unmasked server frame
+func writeWSFrame(w *bufio.Writer, fin bool, op byte, payload []byte) {
+	b0 := op
+	if fin {
+		b0 |= 0x80
+	}
+	w.WriteByte(b0)
+	switch n := len(payload); {
+	case n < 126:
+		w.WriteByte(byte(n))
+	case n <= 0xFFFF:
+		w.WriteByte(126)
+		binary.Write(w, binary.BigEndian, uint16(n))
+	default:
+		w.WriteByte(127)
+		binary.Write(w, binary.BigEndian, uint64(n))
+	}
+	w.Write(payload)
+}
+
+// handleSSE streams Server-Sent Events: ?count= events (default 10, 0 =
+// until the client leaves) every ?interval= (default 100ms). Event IDs count
+// up, and a reconnect with Last-Event-ID resumes after it.
+func handleSSE(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	count, interval := 10, 100*time.Millisecond
+	if v := r.URL.Query().Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			http.Error(w, "count must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		count = n
+	}
+	if v := r.URL.Query().Get("interval"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			http.Error(w, "interval must be a positive duration like 250ms", http.StatusBadRequest)
+			return
+		}
+		interval = d
+	}
+	first := 1
+	if last, err := strconv.Atoi(r.Header.Get("Last-Event-ID")); err == nil {
+		first = last + 1
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for id := first; count == 0 || id < first+count; id++ {
+		select {
+		case <-r.Context().Done():
+			return
+		case t := <-ticker.C:
+			fmt.Fprintf(w, "id: %d\nevent: tick\ndata: {\"seq\":%d,\"ts\":\"%s\"}\n\n", id, id, t.UTC().Format(time.RFC3339Nano))
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// Package logging configures SteadyQ's internal diagnostic log. Packages log
+// through the slog default logger; output only ever goes to a file so it never
+// lands on the TUI screen or mixes with the headless report.
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Levels accepted by Setup
+var Levels = []string{"debug", "info", "warn", "error"}
+
+// Setup sends the default slog logger to path as logfmt lines at level and
+// above. With no path, logs are discarded.
+func Setup(path, level string) error {
+	if path == "" {
+		slog.SetDefault(slog.New(slog.DiscardHandler))
+		return nil
+	}
+
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("unknown log level %q (use %s)", level, strings.Join(Levels, ", "))
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(f, &slog.HandlerOptions{Level: lvl})))
+	slog.Info("steadyq started", "args", os.Args[1:], "pid", os.Getpid())
+	return nil
+}
@@ -0,0 +1,86 @@
+package monitor
+
+import (
+	"math"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// HostLimits are the load generator machine's own ceilings, read at startup
+type HostLimits struct {
+	CPUs       int     // runtime.NumCPU
+	CPUQuota   float64 // Container (cgroup) CPU quota in CPUs, 0 = none
+	GOMAXPROCS int
+	FDSoft     int // RLIMIT_NOFILE, -1 = unknown or unlimited
+	FDHard     int
+	Ports      int // Linux: ephemeral ports (net.ipv4.ip_local_port_range), 0 = unknown
+	TWReuse    int // Linux: net.ipv4.tcp_tw_reuse (0 off, 1 on, 2 loopback only), -1 = unknown
+}
+
+// ReadHostLimits reads the CPU, descriptor and port limits of this machine
+func ReadHostLimits() HostLimits {
+	h := HostLimits{
+		CPUs:       runtime.NumCPU(),
+		CPUQuota:   cgroupCPUQuota(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		TWReuse:    -1,
+	}
+	h.FDSoft, h.FDHard = fdLimits()
+	if f := strings.Fields(readProc("/proc/sys/net/ipv4/ip_local_port_range")); len(f) == 2 {
+		lo, err1 := strconv.Atoi(f[0])
+		hi, err2 := strconv.Atoi(f[1])
+		if err1 == nil && err2 == nil && hi >= lo {
+			h.Ports = hi - lo + 1
+		}
+	}
+	if v, err := strconv.Atoi(readProc("/proc/sys/net/ipv4/tcp_tw_reuse")); err == nil {
+		h.TWReuse = v
+	}
+	return h
+}
+
+// AdjustGOMAXPROCS lowers GOMAXPROCS to a container CPU quota (rounded
+// down, at least 1), as Go otherwise schedules on every CPU of the host and
+// the quota throttles the whole process. A GOMAXPROCS environment variable
+// wins. It returns the value before and after.
+func AdjustGOMAXPROCS() (from, to int) {
+	from = runtime.GOMAXPROCS(0)
+	if _, set := os.LookupEnv("GOMAXPROCS"); set {
+		return from, from
+	}
+	quota := cgroupCPUQuota()
+	if n := max(1, int(math.Floor(quota))); quota > 0 && n < from {
+		runtime.GOMAXPROCS(n)
+		return from, n
+	}
+	return from, from
+}
+
+// cgroupCPUQuota reads the CPU quota of this process's cgroup (v2 cpu.max,
+// else v1 cfs_quota_us / cfs_period_us) in CPUs, 0 when there is none
+func cgroupCPUQuota() float64 {
+	if f := strings.Fields(readProc("/sys/fs/cgroup/cpu.max")); len(f) == 2 {
+		return quotaCPUs(f[0], f[1])
+	}
+	return quotaCPUs(readProc("/sys/fs/cgroup/cpu/cpu.cfs_quota_us"), readProc("/sys/fs/cgroup/cpu/cpu.cfs_period_us"))
+}
+
+func quotaCPUs(quota, period string) float64 {
+	q, err1 := strconv.ParseFloat(quota, 64) // "max" or -1 = unlimited
+	p, err2 := strconv.ParseFloat(period, 64)
+	if err1 != nil || err2 != nil || q <= 0 || p <= 0 {
+		return 0
+	}
+	return q / p
+}
+
+// readProc reads a one-line /proc or /sys file, "" when it cannot
+func readProc(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
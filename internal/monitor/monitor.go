@@ -0,0 +1,262 @@
+package monitor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sample is one reading of the target's resource usage
+type Sample struct {
+	Time       time.Time `json:"time"`
+	CPUPercent float64   `json:"cpu_percent"` // Busy CPU across all cores (0-100)
+	MemPercent float64   `json:"mem_percent"` // Used memory (total - available)
+	Err        string    `json:"error,omitempty"`
+}
+
+// Monitor periodically reads CPU and memory from the target host, either by
+// scraping a Prometheus node_exporter (http(s)://host:9100/metrics) or by
+// reading /proc over SSH (ssh://[user@]host[:port]).
+type Monitor struct {
+	Source   string
+	Interval time.Duration
+
+	mu      sync.Mutex
+	samples []Sample
+
+	// Previous CPU counters, CPU% is the delta between two reads
+	prevBusy  float64
+	prevTotal float64
+
+	client *http.Client
+}
+
+// maxSamples caps the retained history
+const maxSamples = 7200
+
+// New returns a monitor for source; interval defaults to 2s
+func New(source string, interval time.Duration) (*Monitor, error) {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid monitor source: %v", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "ssh":
+	default:
+		return nil, fmt.Errorf("unsupported monitor source %q (use http(s)://host:9100/metrics or ssh://user@host)", source)
+	}
+	return &Monitor{
+		Source:   source,
+		Interval: interval,
+		client:   &http.Client{Timeout: interval},
+	}, nil
+}
+
+// Run samples until ctx is cancelled
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+
+	// Prime the CPU counters so the first sample has a delta
+	m.read(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s := m.read(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			m.mu.Lock()
+			m.samples = append(m.samples, s)
+			if len(m.samples) > maxSamples {
+				m.samples = m.samples[len(m.samples)-maxSamples:]
+			}
+			m.mu.Unlock()
+		}
+	}
+}
+
+// Samples returns a copy of the last n samples (all when n <= 0)
+func (m *Monitor) Samples(n int) []Sample {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	start := 0
+	if n > 0 && len(m.samples) > n {
+		start = len(m.samples) - n
+	}
+	return append([]Sample(nil), m.samples[start:]...)
+}
+
+// read takes one sample from the source
+func (m *Monitor) read(ctx context.Context) Sample {
+	s := Sample{Time: time.Now()}
+
+	var busy, total, memTotal, memAvail float64
+	var err error
+	if strings.HasPrefix(m.Source, "ssh://") {
+		busy, total, memTotal, memAvail, err = m.readSSH(ctx)
+	} else {
+		busy, total, memTotal, memAvail, err = m.readNodeExporter(ctx)
+	}
+	if err != nil {
+		slog.Warn("target monitor sample failed", "source", m.Source, "err", err)
+		s.Err = err.Error()
+		return s
+	}
+
+	if m.prevTotal > 0 && total > m.prevTotal {
+		s.CPUPercent = (busy - m.prevBusy) / (total - m.prevTotal) * 100
+	}
+	m.prevBusy, m.prevTotal = busy, total
+	if memTotal > 0 {
+		s.MemPercent = (memTotal - memAvail) / memTotal * 100
+	}
+	return s
+}
+
+// readNodeExporter scrapes node_cpu_seconds_total and node_memory_* from a metrics page
+func (m *Monitor) readNodeExporter(ctx context.Context) (busy, total, memTotal, memAvail float64, err error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", m.Source, nil)
+	if err != nil {
+		return
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("metrics endpoint returned %d", resp.StatusCode)
+		return
+	}
+
+	var idle float64
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := splitMetric(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(name, "node_cpu_seconds_total{"):
+			total += value
+			if strings.Contains(name, `mode="idle"`) || strings.Contains(name, `mode="iowait"`) {
+				idle += value
+			}
+		case name == "node_memory_MemTotal_bytes":
+			memTotal = value
+		case name == "node_memory_MemAvailable_bytes":
+			memAvail = value
+		}
+	}
+	if err = sc.Err(); err != nil {
+		return
+	}
+	if total == 0 {
+		err = fmt.Errorf("no node_cpu_seconds_total in metrics")
+		return
+	}
+	busy = total - idle
+	return
+}
+
+// readSSH reads /proc/stat and /proc/meminfo on the target over ssh
+func (m *Monitor) readSSH(ctx context.Context) (busy, total, memTotal, memAvail float64, err error) {
+	u, _ := url.Parse(m.Source)
+	args := []string{"-o", "BatchMode=yes", "-o", "ConnectTimeout=5"}
+	if u.Port() != "" {
+		args = append(args, "-p", u.Port())
+	}
+	host := u.Hostname()
+	if u.User != nil {
+		host = u.User.Username() + "@" + host
+	}
+	args = append(args, host, "head -1 /proc/stat; grep -E '^(MemTotal|MemAvailable):' /proc/meminfo")
+
+	cctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(cctx, "ssh", args...).Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			err = fmt.Errorf("ssh: %s", strings.TrimSpace(string(ee.Stderr)))
+		}
+		return
+	}
+	return parseProc(strings.NewReader(string(out)))
+}
+
+// parseProc reads the aggregate "cpu" line of /proc/stat and MemTotal/MemAvailable of /proc/meminfo
+func parseProc(r io.Reader) (busy, total, memTotal, memAvail float64, err error) {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "cpu":
+			// user nice system idle iowait irq softirq steal ...
+			var idle float64
+			for i, f := range fields[1:] {
+				v, _ := strconv.ParseFloat(f, 64)
+				if i >= 8 {
+					break // guest time is already counted in user
+				}
+				total += v
+				if i == 3 || i == 4 {
+					idle += v
+				}
+			}
+			busy = total - idle
+		case "MemTotal:":
+			memTotal, _ = strconv.ParseFloat(fields[1], 64)
+		case "MemAvailable:":
+			memAvail, _ = strconv.ParseFloat(fields[1], 64)
+		}
+	}
+	if total == 0 {
+		err = fmt.Errorf("unexpected /proc output")
+	}
+	return
+}
+
+// splitMetric splits an exposition line into name (with labels) and value
+func splitMetric(line string) (string, float64, bool) {
+	idx := strings.LastIndexByte(line, '}')
+	if idx == -1 {
+		idx = strings.IndexByte(line, ' ')
+	} else {
+		idx++
+	}
+	if idx <= 0 || idx >= len(line) {
+		return "", 0, false
+	}
+	fields := strings.Fields(line[idx:])
+	if len(fields) == 0 {
+		return "", 0, false
+	}
+	v, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return strings.TrimSpace(line[:idx]), v, true
+}
@@ -0,0 +1,112 @@
+package monitor
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// SelfSample is one reading of the load generator's own resource usage
+type SelfSample struct {
+	Time       time.Time `json:"time"`
+	CPUPercent float64   `json:"cpu_percent"` // Share of all cores used by this process (0-100)
+	MemMB      float64   `json:"mem_mb"`      // Memory obtained from the OS by the Go runtime
+	HeapMB     float64   `json:"heap_mb"`
+	Goroutines int       `json:"goroutines"`
+	GCPauseMs  float64   `json:"gc_pause_ms"` // Stop-the-world time since the previous sample
+	NumGC      uint32    `json:"num_gc"`
+	OpenFDs    int       `json:"open_fds"` // -1 when unknown
+	FDLimit    int       `json:"fd_limit"` // -1 when unknown
+	Interval   float64   `json:"interval_sec"`
+}
+
+// Generator health thresholds beyond which client-side results are suspect
+const (
+	selfCPUThreshold     = 85.0 // % of all cores
+	selfGCPauseThreshold = 0.05 // fraction of the interval spent paused
+	selfFDThreshold      = 0.9  // fraction of the descriptor limit
+)
+
+// SelfMonitor samples the load generator's CPU, memory, goroutines, GC and descriptors
+type SelfMonitor struct {
+	mu   sync.Mutex
+	self []SelfSample
+
+	prevTime  time.Time
+	prevCPU   time.Duration
+	prevPause uint64
+}
+
+// NewSelf returns a self monitor primed with the current counters
+func NewSelf() *SelfMonitor {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return &SelfMonitor{
+		prevTime:  time.Now(),
+		prevCPU:   processCPU(),
+		prevPause: ms.PauseTotalNs,
+	}
+}
+
+// Sample takes one reading, stores it and returns it
+func (m *SelfMonitor) Sample() SelfSample {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	now := time.Now()
+	cpu := processCPU()
+	open, limit := openFDs()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	wall := now.Sub(m.prevTime)
+	s := SelfSample{
+		Time:       now,
+		MemMB:      float64(ms.Sys) / (1 << 20),
+		HeapMB:     float64(ms.HeapAlloc) / (1 << 20),
+		Goroutines: runtime.NumGoroutine(),
+		GCPauseMs:  float64(ms.PauseTotalNs-m.prevPause) / 1e6,
+		NumGC:      ms.NumGC,
+		OpenFDs:    open,
+		FDLimit:    limit,
+		Interval:   wall.Seconds(),
+	}
+	if wall > 0 && cpu > 0 {
+		s.CPUPercent = float64(cpu-m.prevCPU) / (float64(wall) * float64(runtime.NumCPU())) * 100
+	}
+	m.prevTime, m.prevCPU, m.prevPause = now, cpu, ms.PauseTotalNs
+
+	m.self = append(m.self, s)
+	if len(m.self) > maxSamples {
+		m.self = m.self[len(m.self)-maxSamples:]
+	}
+	return s
+}
+
+// Samples returns a copy of the last n readings (all when n <= 0)
+func (m *SelfMonitor) Samples(n int) []SelfSample {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	start := 0
+	if n > 0 && len(m.self) > n {
+		start = len(m.self) - n
+	}
+	return append([]SelfSample(nil), m.self[start:]...)
+}
+
+// Warnings lists the reasons this reading suggests the generator, not the
+// server, is the bottleneck. Empty when the generator looks healthy.
+func (s SelfSample) Warnings() []string {
+	var w []string
+	if s.CPUPercent >= selfCPUThreshold {
+		w = append(w, fmt.Sprintf("generator CPU at %.0f%%", s.CPUPercent))
+	}
+	if s.Interval > 0 && s.GCPauseMs/1000/s.Interval >= selfGCPauseThreshold {
+		w = append(w, fmt.Sprintf("GC paused %.0fms in %.0fs", s.GCPauseMs, s.Interval))
+	}
+	if s.OpenFDs > 0 && s.FDLimit > 0 && float64(s.OpenFDs) >= selfFDThreshold*float64(s.FDLimit) {
+		w = append(w, fmt.Sprintf("%d/%d file descriptors open", s.OpenFDs, s.FDLimit))
+	}
+	return w
+}
@@ -0,0 +1,20 @@
+//go:build !unix
+
+package monitor
+
+import "time"
+
+// processCPU is not available on this platform
+func processCPU() time.Duration {
+	return 0
+}
+
+// openFDs is not available on this platform
+func openFDs() (open, limit int) {
+	return -1, -1
+}
+
+// fdLimits is not available on this platform
+func fdLimits() (soft, hard int) {
+	return -1, -1
+}
@@ -0,0 +1,46 @@
+//go:build unix
+
+package monitor
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// processCPU returns the user + system CPU time consumed by this process
+func processCPU() time.Duration {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0
+	}
+	return time.Duration(ru.Utime.Nano() + ru.Stime.Nano())
+}
+
+// openFDs counts open descriptors and reads the soft limit (-1 when unknown)
+func openFDs() (open, limit int) {
+	open, limit = -1, -1
+	for _, dir := range []string{"/proc/self/fd", "/dev/fd"} {
+		if entries, err := os.ReadDir(dir); err == nil {
+			open = len(entries)
+			break
+		}
+	}
+	limit, _ = fdLimits()
+	return open, limit
+}
+
+// fdLimits reads the soft and hard descriptor limits (-1 when unknown or unlimited)
+func fdLimits() (soft, hard int) {
+	soft, hard = -1, -1
+	var rl syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rl); err == nil {
+		if rl.Cur < 1<<31 {
+			soft = int(rl.Cur)
+		}
+		if rl.Max < 1<<31 {
+			hard = int(rl.Max)
+		}
+	}
+	return soft, hard
+}
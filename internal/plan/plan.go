@@ -0,0 +1,181 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"steadyq/internal/runner"
+)
+
+// Plan is a named, saved run configuration. Plan files, run directories'
+// config.json and bundles' plan.json share this layout (see SchemaVersion).
+type Plan struct {
+	Version int           `json:"version"` // SchemaVersion of the file
+	Name    string        `json:"name"`
+	SavedAt time.Time     `json:"saved_at"`
+	Config  runner.Config `json:"config"`
+}
+
+// Info describes a plan file on disk (for pickers)
+type Info struct {
+	Name    string
+	Path    string
+	ModTime time.Time
+}
+
+// Dir returns the directory where named plans are stored ($HOME/.steadyq/plans)
+func Dir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "plans"
+	}
+	return filepath.Join(home, ".steadyq", "plans")
+}
+
+// Resolve turns a plan name or path into a file path.
+// Bare names ("checkout") are looked up in Dir(), as .json, else .yaml or .yml.
+func Resolve(nameOrPath string) string {
+	if strings.ContainsAny(nameOrPath, `/\`) || strings.HasSuffix(nameOrPath, ".json") || isYAML(nameOrPath) {
+		return nameOrPath
+	}
+	path := filepath.Join(Dir(), fileName(nameOrPath))
+	if fileExists(path) {
+		return path
+	}
+	for _, ext := range []string{".yaml", ".yml"} {
+		if alt := strings.TrimSuffix(path, ".json") + ext; fileExists(alt) {
+			return alt
+		}
+	}
+	return path
+}
+
+// Save writes cfg as a named plan into Dir() and returns the file path
+func Save(name string, cfg runner.Config) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("plan name is empty")
+	}
+	if err := os.MkdirAll(Dir(), 0755); err != nil {
+		return "", err
+	}
+
+	if cfg.Name == "" {
+		cfg.Name = name
+	}
+	path := filepath.Join(Dir(), fileName(name))
+	return path, WriteFile(path, Plan{Name: name, SavedAt: time.Now(), Config: cfg})
+}
+
+// WriteFile writes a plan to an explicit path, at SchemaVersion
+func WriteFile(path string, p Plan) error {
+	p.Version = SchemaVersion
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Load reads a plan by name or path, JSON or YAML (.yaml, .yml), see Decode
+func Load(nameOrPath string) (Plan, error) {
+	path := Resolve(nameOrPath)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Plan{}, err
+	}
+	decode := Decode
+	if isYAML(path) {
+		decode = DecodeYAML
+	}
+	p, err := decode(data)
+	if err != nil {
+		return p, fmt.Errorf("invalid plan file: %w", err)
+	}
+	return p, nil
+}
+
+// List returns saved plans, most recently modified first
+func List() ([]Info, error) {
+	entries, err := os.ReadDir(Dir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var plans []Info
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".json" && !isYAML(e.Name())) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		plans = append(plans, Info{
+			Name:    strings.TrimSuffix(e.Name(), ext),
+			Path:    filepath.Join(Dir(), e.Name()),
+			ModTime: info.ModTime(),
+		})
+	}
+	sort.Slice(plans, func(i, j int) bool {
+		return plans[i].ModTime.After(plans[j].ModTime)
+	})
+	return plans, nil
+}
+
+// Delete removes a saved plan file
+func Delete(path string) error {
+	return os.Remove(path)
+}
+
+// Archive moves a saved plan into Dir()/archive, out of the List() results.
+// A plan archived earlier under the same name is kept: the new one gets the
+// time it was archived in its name.
+func Archive(path string) (string, error) {
+	dir := filepath.Join(filepath.Dir(path), "archive")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	base := filepath.Base(path)
+	dst := filepath.Join(dir, base)
+	if fileExists(dst) {
+		ext := filepath.Ext(base)
+		stem := strings.TrimSuffix(base, ext) + "-" + time.Now().Format("20060102-150405")
+		dst = filepath.Join(dir, stem+ext)
+		for n := 2; fileExists(dst); n++ {
+			dst = filepath.Join(dir, fmt.Sprintf("%s-%d%s", stem, n, ext))
+		}
+	}
+	return dst, os.Rename(path, dst)
+}
+
+// fileName sanitizes a plan name into a file name
+func fileName(name string) string {
+	clean := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, strings.TrimSpace(name))
+	return clean + ".json"
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func isYAML(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".yaml" || ext == ".yml"
+}
@@ -0,0 +1,187 @@
+package plan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+
+	"steadyq/internal/runner"
+)
+
+// SchemaVersion is the layout of plan files written by this build. Files
+// without a version predate versioning and are read as version 1, whose
+// layout they have.
+//
+// Adding a Config field keeps the version: older files lack it and get its
+// default (the zero value). Renaming, removing or changing the meaning of a
+// field bumps it, with a migration from the previous version.
+const SchemaVersion = 1
+
+// migrations[v] rewrites a decoded version v plan into version v+1. Version 1
+// is the first, so bumping SchemaVersion to 2 adds migrations[1].
+var migrations = map[int]func(raw map[string]any) error{}
+
+// Modes and Protocols accepted in Config.Mode and Config.Protocol ("" = default)
+var (
+	Modes     = []string{"rps", "users", "script"}
+	Protocols = []string{"http", "tcp", "udp", "redis", "kafka", "sql"}
+)
+
+// Decode reads a JSON plan: it migrates older versions to SchemaVersion,
+// accepts durations as strings ("30s") as well as nanoseconds, rejects
+// unknown fields and validates the config (see Validate)
+func Decode(data []byte) (Plan, error) {
+	var p Plan
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return p, err
+	}
+	if err := migrate(raw); err != nil {
+		return p, err
+	}
+	if cfg, ok := raw["config"].(map[string]any); ok {
+		if err := parseDurations(cfg); err != nil {
+			return p, err
+		}
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return p, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return p, err
+	}
+	if err := Validate(p.Config); err != nil {
+		return p, fmt.Errorf("config: %w", err)
+	}
+	return p, nil
+}
+
+// DecodeYAML reads a YAML plan, with the same keys as the JSON one
+func DecodeYAML(data []byte) (Plan, error) {
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return Plan{}, err
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return Plan{}, err
+	}
+	return Decode(data)
+}
+
+// Validate checks the values of a stored config that need no files or
+// network: mode, protocol, HTTP engine, pacing, counts and durations, percentiles,
+// thresholds and success codes
+func Validate(cfg runner.Config) error {
+	if cfg.Mode != "" && !slices.Contains(Modes, cfg.Mode) {
+		return fmt.Errorf("unknown Mode %q (use %s)", cfg.Mode, strings.Join(Modes, ", "))
+	}
+	if cfg.Protocol != "" && !slices.Contains(Protocols, cfg.Protocol) {
+		return fmt.Errorf("unknown Protocol %q (use %s)", cfg.Protocol, strings.Join(Protocols, ", "))
+	}
+	if !slices.Contains(runner.HTTPEngines, cfg.GetHTTPEngine()) {
+		return fmt.Errorf("unknown HTTPEngine %q (use %s)", cfg.HTTPEngine, strings.Join(runner.HTTPEngines, ", "))
+	}
+	if !slices.Contains(runner.PacingStrategies, cfg.GetPacing()) {
+		return fmt.Errorf("unknown Pacing %q (use %s)", cfg.Pacing, strings.Join(runner.PacingStrategies, ", "))
+	}
+	if err := checkNonNegative(cfg); err != nil {
+		return err
+	}
+	if cfg.AbortErrorRate > 100 {
+		return fmt.Errorf("AbortErrorRate must be between 0 and 100")
+	}
+	if cfg.BreakerErrorRate > 1 {
+		return fmt.Errorf("BreakerErrorRate must be between 0 and 1")
+	}
+	if err := runner.CheckPercentiles(cfg.Percentiles); err != nil {
+		return fmt.Errorf("Percentiles: %v", err)
+	}
+	if _, err := runner.ParseThresholds(cfg.Thresholds); err != nil {
+		return fmt.Errorf("Thresholds: %v", err)
+	}
+	if _, err := runner.ParseSuccessCodes(cfg.SuccessCodes); err != nil {
+		return fmt.Errorf("SuccessCodes: %v", err)
+	}
+	return nil
+}
+
+// migrate brings a decoded plan to SchemaVersion
+func migrate(raw map[string]any) error {
+	version := 1 // Unversioned
+	if v, ok := raw["version"]; ok {
+		f, ok := v.(float64)
+		if !ok || f != float64(int(f)) || f < 1 {
+			return fmt.Errorf("version %v is not a schema version", v)
+		}
+		version = int(f)
+	}
+	if version > SchemaVersion {
+		return fmt.Errorf("schema version %d is newer than this SteadyQ supports (%d), upgrade to load it", version, SchemaVersion)
+	}
+	for ; version < SchemaVersion; version++ {
+		migration, ok := migrations[version]
+		if !ok {
+			return fmt.Errorf("no migration from schema version %d", version)
+		}
+		if err := migration(raw); err != nil {
+			return fmt.Errorf("migrating from schema version %d: %v", version, err)
+		}
+	}
+	raw["version"] = SchemaVersion
+	return nil
+}
+
+// checkNonNegative rejects negative numbers and durations in cfg; zero
+// always means the default or off
+func checkNonNegative(cfg runner.Config) error {
+	v := reflect.ValueOf(cfg)
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		switch f.Kind() {
+		case reflect.Int, reflect.Int64:
+			if f.Int() < 0 && v.Type().Field(i).Name != "Seed" {
+				return fmt.Errorf("%s cannot be negative", v.Type().Field(i).Name)
+			}
+		case reflect.Float64:
+			if f.Float() < 0 {
+				return fmt.Errorf("%s cannot be negative", v.Type().Field(i).Name)
+			}
+		}
+	}
+	return nil
+}
+
+// parseDurations turns duration strings ("1m30s") of the config's
+// time.Duration fields into nanoseconds, the form encoding/json reads.
+// Keys match field names case-insensitively, as encoding/json does.
+func parseDurations(cfg map[string]any) error {
+	durationType := reflect.TypeOf(time.Duration(0))
+	t := reflect.TypeOf(runner.Config{})
+	for key, value := range cfg {
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.Type == durationType && strings.EqualFold(f.Name, key) {
+				d, err := time.ParseDuration(s)
+				if err != nil {
+					return fmt.Errorf("%s: %v", key, err)
+				}
+				cfg[key] = int64(d)
+			}
+		}
+	}
+	return nil
+}
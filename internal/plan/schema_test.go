@@ -0,0 +1,138 @@
+package plan
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+
+	"steadyq/internal/runner"
+)
+
+// fullConfig sets every Config field to a non-zero value Validate accepts, so
+// a new field is covered as soon as it is added
+func fullConfig(t *testing.T) runner.Config {
+	t.Helper()
+	var cfg runner.Config
+	v := reflect.ValueOf(&cfg).Elem()
+	durationType := reflect.TypeOf(time.Duration(0))
+	for i := 0; i < v.NumField(); i++ {
+		f, name := v.Field(i), v.Type().Field(i).Name
+		switch {
+		case f.Type() == durationType:
+			f.SetInt(int64(1500 * time.Millisecond))
+		case f.Kind() == reflect.String:
+			f.SetString(name + " value")
+		case f.Kind() == reflect.Int, f.Kind() == reflect.Int64:
+			f.SetInt(7)
+		case f.Kind() == reflect.Float64:
+			f.SetFloat(0.5)
+		case f.Kind() == reflect.Bool:
+			f.SetBool(true)
+		case f.Kind() == reflect.Slice && f.Type().Elem().Kind() == reflect.String:
+			f.Set(reflect.ValueOf([]string{name + " a", name + " b"}))
+		case f.Kind() == reflect.Map && f.Type().Key().Kind() == reflect.String:
+			m := reflect.MakeMap(f.Type())
+			elem := reflect.New(f.Type().Elem()).Elem()
+			switch elem.Kind() {
+			case reflect.String:
+				elem.SetString("v")
+			case reflect.Int:
+				elem.SetInt(3)
+			}
+			m.SetMapIndex(reflect.ValueOf("k"), elem)
+			f.Set(m)
+		}
+	}
+
+	// Fields Validate checks the values of
+	cfg.Mode = "users"
+	cfg.Protocol = "http"
+	cfg.HTTPEngine = runner.EngineFastHTTP
+	cfg.Pacing = runner.PacingPoisson
+	cfg.Percentiles = []float64{50, 99.9}
+	cfg.Thresholds = []string{"p99<500ms", "error_rate<1%"}
+	cfg.SuccessCodes = "200-299,304"
+
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).IsZero() {
+			t.Fatalf("fullConfig leaves %s (%s) unset", v.Type().Field(i).Name, v.Field(i).Type())
+		}
+	}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("fullConfig is invalid: %v", err)
+	}
+	return cfg
+}
+
+// TestRoundTrip writes a plan with every field set and reads it back, as
+// JSON (durations in nanoseconds, as WriteFile writes them) and as YAML with
+// durations as strings
+func TestRoundTrip(t *testing.T) {
+	want := Plan{Version: SchemaVersion, Name: "full", SavedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), Config: fullConfig(t)}
+
+	path := filepath.Join(t.TempDir(), "full.json")
+	if err := WriteFile(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("JSON round trip:\n got %+v\nwant %+v", got, want)
+	}
+
+	// YAML as a person writes it: the same keys, "1.5s" durations
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	cfg := raw["config"].(map[string]any)
+	durationType := reflect.TypeOf(time.Duration(0))
+	ct := reflect.TypeOf(runner.Config{})
+	for i := 0; i < ct.NumField(); i++ {
+		if f := ct.Field(i); f.Type == durationType {
+			cfg[f.Name] = time.Duration(cfg[f.Name].(float64)).String()
+		}
+	}
+	if data, err = yaml.Marshal(raw); err != nil {
+		t.Fatal(err)
+	}
+	got, err = DecodeYAML(data)
+	if err != nil {
+		t.Fatalf("YAML: %v\n%s", err, data)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("YAML round trip:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestMigrateVersions(t *testing.T) {
+	for _, tc := range []struct {
+		json string
+		ok   bool
+	}{
+		{`{"name": "old"}`, true}, // Unversioned: version 1
+		{`{"version": 1, "name": "v1"}`, true},
+		{`{"version": 0}`, false},
+		{`{"version": 1.5}`, false},
+		{`{"version": 99}`, false},
+	} {
+		p, err := Decode([]byte(tc.json))
+		if (err == nil) != tc.ok {
+			t.Errorf("Decode(%s): err %v, want ok %v", tc.json, err, tc.ok)
+		}
+		if err == nil && p.Version != SchemaVersion {
+			t.Errorf("Decode(%s): version %d, want %d", tc.json, p.Version, SchemaVersion)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package report
+
+import (
+	"math"
+	"sort"
+
+	"steadyq/internal/runner"
+)
+
+// ComparedPercentiles are the percentiles two runs are compared on
+var ComparedPercentiles = []float64{50, 90, 95, 99}
+
+// z95 is the two-sided 95% normal quantile
+const z95 = 1.959964
+
+// Comparison sets run B against run A. Latencies are service times of
+// successful requests, in milliseconds; differences are B - A.
+type Comparison struct {
+	A           ComparedRun      `json:"a"`
+	B           ComparedRun      `json:"b"`
+	ErrorRate   RateDiff         `json:"error_rate"`
+	Percentiles []PercentileDiff `json:"percentiles"`
+	Overall     RankTest         `json:"mann_whitney"`
+}
+
+// Bin is a latency (ms) and how many samples had it. A run's latencies are
+// its bins in ascending order: one per request from raw results, one per
+// bucket from a histogram.
+type Bin struct {
+	Value float64
+	Count uint64
+}
+
+// ComparedRun is one side of a Comparison
+type ComparedRun struct {
+	Name     string    `json:"name"`
+	Requests uint64    `json:"requests"`
+	Fail     uint64    `json:"fail"`
+	Service  Latencies `json:"service_ms"`
+
+	// Results --max-results dropped: the comparison then comes from
+	// histograms, and raw result exports hold only the kept ones
+	ResultsDropped uint64 `json:"results_dropped,omitempty"`
+}
+
+// PercentileDiff is how one percentile moved, with a 95% confidence interval
+// of the difference. Each side's interval comes from order statistics (the
+// ranks a binomial puts around the percentile), so no distribution is
+// assumed; the difference is significant when its interval excludes 0.
+type PercentileDiff struct {
+	P           float64 `json:"p"`
+	A           float64 `json:"a_ms"`
+	B           float64 `json:"b_ms"`
+	Diff        float64 `json:"diff_ms"`
+	Low         float64 `json:"ci_low_ms"`
+	High        float64 `json:"ci_high_ms"`
+	Significant bool    `json:"significant"`
+}
+
+// RateDiff compares error rates (percent) with a two-proportion z-test
+type RateDiff struct {
+	A           float64 `json:"a"`
+	B           float64 `json:"b"`
+	Diff        float64 `json:"diff"` // Percentage points
+	PValue      float64 `json:"p_value"`
+	Significant bool    `json:"significant"` // PValue < 0.05
+}
+
+// Compare sets the results of run b against run a
+func Compare(nameA string, a []runner.ExperimentResult, nameB string, b []runner.ExperimentResult) Comparison {
+	ra, binsA := comparedRun(nameA, a)
+	rb, binsB := comparedRun(nameB, b)
+	return CompareBins(ra, binsA, rb, binsB)
+}
+
+// CompareBins sets run b against run a from their service time bins, e.g.
+// from histograms when the raw results are gone. Requests and Fail of a and
+// b must be set; their Service is computed.
+func CompareBins(a ComparedRun, binsA []Bin, b ComparedRun, binsB []Bin) Comparison {
+	a.Service, b.Service = binLatencies(binsA), binLatencies(binsB)
+	c := Comparison{
+		A:         a,
+		B:         b,
+		ErrorRate: compareRates(a.Fail, a.Requests, b.Fail, b.Requests),
+		Overall:   mannWhitney(binsA, binsB),
+	}
+	for _, p := range ComparedPercentiles {
+		c.Percentiles = append(c.Percentiles, comparePercentile(binsA, binsB, p))
+	}
+	return c
+}
+
+// Regressions lists the percentiles that got significantly slower in B
+func (c Comparison) Regressions() []PercentileDiff {
+	var slower []PercentileDiff
+	for _, d := range c.Percentiles {
+		if d.Significant && d.Diff > 0 {
+			slower = append(slower, d)
+		}
+	}
+	return slower
+}
+
+// Worse reports whether B is significantly slower at any percentile or
+// fails significantly more often than A
+func (c Comparison) Worse() bool {
+	return len(c.Regressions()) > 0 || (c.ErrorRate.Significant && c.ErrorRate.Diff > 0)
+}
+
+// comparedRun counts results and returns the bins of their service times
+func comparedRun(name string, results []runner.ExperimentResult) (ComparedRun, []Bin) {
+	run := ComparedRun{Name: name, Requests: uint64(len(results))}
+	var bins []Bin
+	for _, r := range results {
+		if r.Success {
+			bins = append(bins, Bin{Value: ms(r.ServiceTime), Count: 1})
+		} else {
+			run.Fail++
+		}
+	}
+	sort.Slice(bins, func(i, j int) bool { return bins[i].Value < bins[j].Value })
+	return run, bins
+}
+
+func comparePercentile(a, b []Bin, p float64) PercentileDiff {
+	d := PercentileDiff{P: p, A: binPercentile(a, p), B: binPercentile(b, p)}
+	d.Diff = d.B - d.A
+	if len(a) == 0 || len(b) == 0 {
+		return d
+	}
+	se := math.Hypot(percentileSE(a, p), percentileSE(b, p))
+	d.Low, d.High = d.Diff-z95*se, d.Diff+z95*se
+	d.Significant = d.Low > 0 || d.High < 0
+	return d
+}
+
+// percentileSE estimates the standard error of the p-th percentile of bins
+// from the spread of its distribution-free 95% confidence interval
+func percentileSE(bins []Bin, p float64) float64 {
+	n := float64(binCount(bins))
+	q := p / 100
+	half := z95 * math.Sqrt(n*q*(1-q))
+	lo := binAt(bins, int64(math.Floor(n*q-half))-1)
+	hi := binAt(bins, int64(math.Ceil(n*q+half))-1)
+	return (hi - lo) / (2 * z95)
+}
+
+func binCount(bins []Bin) uint64 {
+	var n uint64
+	for _, b := range bins {
+		n += b.Count
+	}
+	return n
+}
+
+// binAt is the value at a 0-based rank, clamped to the samples
+func binAt(bins []Bin, rank int64) float64 {
+	if len(bins) == 0 {
+		return 0
+	}
+	rank = max(rank, 0)
+	for _, b := range bins {
+		if rank < int64(b.Count) {
+			return b.Value
+		}
+		rank -= int64(b.Count)
+	}
+	return bins[len(bins)-1].Value
+}
+
+// binPercentile is the nearest-rank p-th percentile of bins (see Percentile)
+func binPercentile(bins []Bin, p float64) float64 {
+	return binAt(bins, int64(math.Ceil(p/100*float64(binCount(bins))))-1)
+}
+
+func binLatencies(bins []Bin) Latencies {
+	n := binCount(bins)
+	if n == 0 {
+		return Latencies{}
+	}
+	sum := 0.0
+	for _, b := range bins {
+		sum += b.Value * float64(b.Count)
+	}
+	mean := sum / float64(n)
+	variance := 0.0
+	for _, b := range bins {
+		variance += (b.Value - mean) * (b.Value - mean) * float64(b.Count)
+	}
+	return Latencies{
+		P50:         binPercentile(bins, 50),
+		P90:         binPercentile(bins, 90),
+		P95:         binPercentile(bins, 95),
+		P99:         binPercentile(bins, 99),
+		Mean:        mean,
+		Max:         bins[len(bins)-1].Value,
+		Min:         bins[0].Value,
+		StdDev:      math.Sqrt(variance / float64(n)),
+		TrimmedMean: binTrimmedMean(bins),
+	}
+}
+
+// binTrimmedMean is trimmedMean over bins
+func binTrimmedMean(bins []Bin) float64 {
+	n := binCount(bins)
+	drop := uint64(float64(n) * TrimPercent / 100)
+	lo, hi := drop, n-drop // Keep ranks [lo, hi)
+	var rank uint64
+	sum := 0.0
+	for _, b := range bins {
+		if from, to := max(rank, lo), min(rank+b.Count, hi); to > from {
+			sum += b.Value * float64(to-from)
+		}
+		rank += b.Count
+	}
+	return sum / float64(hi-lo)
+}
+
+func compareRates(failA, nA, failB, nB uint64) RateDiff {
+	d := RateDiff{PValue: 1}
+	if nA == 0 || nB == 0 {
+		return d
+	}
+	pa, pb := float64(failA)/float64(nA), float64(failB)/float64(nB)
+	d.A, d.B, d.Diff = pa*100, pb*100, (pb-pa)*100
+	pooled := float64(failA+failB) / float64(nA+nB)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(nA) + 1/float64(nB)))
+	if se > 0 {
+		d.PValue = math.Erfc(math.Abs(pb-pa) / se / math.Sqrt2)
+	}
+	d.Significant = d.PValue < 0.05
+	return d
+}
@@ -0,0 +1,39 @@
+package report
+
+import (
+	"steadyq/internal/runner"
+)
+
+// Concurrency sets the requests in flight, sampled during the run, against
+// Little's Law: on average L = λW requests are in flight, λ being the
+// completion rate and W the mean service time. The two agree over a steady
+// run; open loop fixes λ, so a slower W shows as more in flight, while
+// closed loop fixes L (the users), so a slower W lowers λ instead.
+type Concurrency struct {
+	InflightAvg   float64 `json:"inflight_avg"`    // Measured L
+	InflightMax   int64   `json:"inflight_max"`    // Peak sample
+	Throughput    float64 `json:"throughput"`      // λ, completed requests per second
+	MeanServiceMs float64 `json:"mean_service_ms"` // W, over every request
+	LittleL       float64 `json:"littles_law_l"`   // λW
+}
+
+// LittlesLaw derives λ and W from the summarized results and pairs them with
+// the measured in-flight average and peak (runner.Stats.InflightAvg); nil
+// without results
+func LittlesLaw(s Summary, results []runner.ExperimentResult, inflightAvg float64, inflightMax int64) *Concurrency {
+	if len(results) == 0 || s.Duration <= 0 {
+		return nil
+	}
+	sum := 0.0
+	for _, r := range results {
+		sum += ms(r.ServiceTime)
+	}
+	c := &Concurrency{
+		InflightAvg:   inflightAvg,
+		InflightMax:   inflightMax,
+		Throughput:    float64(len(results)) / s.Duration.Seconds(),
+		MeanServiceMs: sum / float64(len(results)),
+	}
+	c.LittleL = c.Throughput * c.MeanServiceMs / 1000
+	return c
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"sort"
+
+	"steadyq/internal/runner"
+)
+
+// MirrorComparison sets one label's requests against their mirrored copies
+// (runner.Config.Mirror)
+type MirrorComparison struct {
+	Primary MirrorSide `json:"primary"`
+	Mirror  MirrorSide `json:"mirror"`
+}
+
+// MirrorSide is one target's share of a comparison. Latencies are service
+// times of successful requests, in milliseconds.
+type MirrorSide struct {
+	Requests  uint64  `json:"requests"`
+	ErrorRate float64 `json:"error_rate"`
+	P50       float64 `json:"p50_ms"`
+	P90       float64 `json:"p90_ms"`
+	P99       float64 `json:"p99_ms"`
+}
+
+// CompareMirror compares primary and mirrored results per label, nil when
+// nothing was mirrored
+func CompareMirror(primary, mirrored []runner.ExperimentResult) map[string]MirrorComparison {
+	if len(mirrored) == 0 {
+		return nil
+	}
+	p, m := mirrorSides(primary), mirrorSides(mirrored)
+	byLabel := make(map[string]MirrorComparison)
+	for label, side := range p {
+		byLabel[label] = MirrorComparison{Primary: side, Mirror: m[label]}
+	}
+	for label, side := range m {
+		if _, ok := p[label]; !ok {
+			byLabel[label] = MirrorComparison{Mirror: side}
+		}
+	}
+	return byLabel
+}
+
+func mirrorSides(results []runner.ExperimentResult) map[string]MirrorSide {
+	service := make(map[string][]float64)
+	sides := make(map[string]MirrorSide)
+	for _, r := range results {
+		label := Label(r)
+		side := sides[label]
+		side.Requests++
+		if r.Success {
+			service[label] = append(service[label], ms(r.ServiceTime))
+		}
+		sides[label] = side
+	}
+	for label, side := range sides {
+		lats := service[label]
+		sort.Float64s(lats)
+		side.ErrorRate = float64(side.Requests-uint64(len(lats))) / float64(side.Requests) * 100
+		side.P50, side.P90, side.P99 = Percentile(lats, 50), Percentile(lats, 90), Percentile(lats, 99)
+		sides[label] = side
+	}
+	return sides
+}
@@ -0,0 +1,57 @@
+package report
+
+import "math"
+
+// RankTest is a Mann-Whitney U test of whether B's latencies tend to be
+// higher or lower than A's over the whole distribution, not at one
+// percentile. Ties (common with histogram buckets) count half and correct
+// the variance.
+type RankTest struct {
+	ProbBSlower float64 `json:"prob_b_slower"` // Chance a random B request is slower than a random A one
+	Z           float64 `json:"z"`
+	PValue      float64 `json:"p_value"`
+	Significant bool    `json:"significant"` // PValue < 0.05
+}
+
+func mannWhitney(a, b []Bin) RankTest {
+	t := RankTest{ProbBSlower: 0.5, PValue: 1}
+	nA, nB := float64(binCount(a)), float64(binCount(b))
+	if nA == 0 || nB == 0 {
+		return t
+	}
+
+	// Walk both sorted bin lists, giving each group of equal values its mid-rank
+	var rankB, ties, below float64
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		v := math.Inf(1)
+		if i < len(a) {
+			v = a[i].Value
+		}
+		if j < len(b) {
+			v = min(v, b[j].Value)
+		}
+		var inA, inB float64
+		for ; i < len(a) && a[i].Value == v; i++ {
+			inA += float64(a[i].Count)
+		}
+		for ; j < len(b) && b[j].Value == v; j++ {
+			inB += float64(b[j].Count)
+		}
+		group := inA + inB
+		rankB += inB * (below + (group+1)/2)
+		ties += group*group*group - group
+		below += group
+	}
+
+	u := rankB - nB*(nB+1)/2 // Pairs where B is slower, ties counting half
+	t.ProbBSlower = u / (nA * nB)
+	n := nA + nB
+	variance := nA * nB / 12 * ((n + 1) - ties/(n*(n-1)))
+	if variance > 0 {
+		t.Z = (u - nA*nB/2) / math.Sqrt(variance)
+		t.PValue = math.Erfc(math.Abs(t.Z) / math.Sqrt2)
+	}
+	t.Significant = t.PValue < 0.05
+	return t
+}
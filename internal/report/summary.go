@@ -0,0 +1,453 @@
+// Package report derives the summary of a finished run from its results.
+// The CLI summary, the run log, the schedule history, the summary exports and
+// bundles all use Summarize, so they agree on every number.
+package report
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+
+	"steadyq/internal/runner"
+	"steadyq/internal/stats"
+)
+
+// ApdexT is the Apdex target: successful responses within T satisfy, within
+// 4T tolerate; slower ones and failures frustrate
+const ApdexT = 500 * time.Millisecond
+
+// Summary is what a run achieved. Top-level latencies are end to end
+// (Latency, queue wait included) over every request; Service covers the
+// server's share of successful requests only.
+type Summary struct {
+	TotalRequests uint64         `json:"total_requests"`
+	TotalSuccess  uint64         `json:"total_success"`
+	TotalFail     uint64         `json:"total_fail"`
+	TotalBytes    int64          `json:"total_bytes"`
+	ErrorRate     float64        `json:"error_rate"` // Percent of requests that failed
+	P50           float64        `json:"p50_ms"`
+	P90           float64        `json:"p90_ms"`
+	P95           float64        `json:"p95_ms"`
+	P99           float64        `json:"p99_ms"`
+	Mean          float64        `json:"mean_ms"`
+	Max           float64        `json:"max_ms"`
+	Min           float64        `json:"min_ms"`
+	StdDev        float64        `json:"stddev_ms"`
+	TrimmedMean   float64        `json:"trimmed_mean_ms"` // Mean without the top and bottom TrimPercent
+	Service       Latencies      `json:"service_ms"`
+	Apdex         float64        `json:"apdex"` // 0-1, see ApdexT
+	ApdexTMs      float64        `json:"apdex_t_ms"`
+	StatusCodes   map[int]int    `json:"status_codes"`
+	Errors        map[string]int `json:"errors"`
+	Duration      time.Duration  `json:"duration"`
+	AverageRPS    float64        `json:"avg_rps"`
+
+	// Results the run's bounded buffer dropped, oldest first: the figures
+	// above cover the rest (runner.Config.MaxResults)
+	ResultsDropped uint64 `json:"results_dropped,omitempty"`
+
+	// Bytes on the wire: request heads and bodies sent, response heads and
+	// bodies received (TotalBytes counts response bodies only)
+	SentBytes int64 `json:"sent_bytes"`
+	RecvBytes int64 `json:"recv_bytes"`
+
+	// Cache revalidation (Config.Conditional): conditional requests, the 304s
+	// they got, and service times of 304s versus full successful responses
+	Conditional   uint64     `json:"conditional,omitempty"`
+	NotModified   uint64     `json:"not_modified,omitempty"`
+	NotModifiedMs *Latencies `json:"not_modified_ms,omitempty"`
+	FullMs        *Latencies `json:"full_ms,omitempty"`
+
+	// HTTP connection reuse: requests sent on a newly opened versus a pooled
+	// keep-alive connection, the time to open one (DNS, dial, TLS), and
+	// service times of successful requests on each
+	NewConns     uint64     `json:"new_conns,omitempty"`
+	ReusedConns  uint64     `json:"reused_conns,omitempty"`
+	ConnectMs    *Latencies `json:"connect_ms,omitempty"`
+	NewConnMs    *Latencies `json:"new_conn_ms,omitempty"`
+	ReusedConnMs *Latencies `json:"reused_conn_ms,omitempty"`
+
+	// TLS (HTTPS): requests by negotiated version and cipher suite, and the
+	// handshakes of new connections, full versus resumed from a cached session
+	TLSVersions   map[string]int `json:"tls_versions,omitempty"`
+	TLSCiphers    map[string]int `json:"tls_ciphers,omitempty"`
+	TLSHandshakes uint64         `json:"tls_handshakes,omitempty"`
+	TLSResumed    uint64         `json:"tls_resumed,omitempty"`
+	TLSFullMs     *Latencies     `json:"tls_full_ms,omitempty"`
+	TLSResumedMs  *Latencies     `json:"tls_resumed_ms,omitempty"`
+
+	// Service time at each configured percentile (runner.Config.Percentiles,
+	// see ServicePercentiles)
+	Percentiles []PercentileValue `json:"percentiles,omitempty"`
+
+	// Percentiles computed from too few samples to be trusted (see SampleWarnings)
+	Warnings []string `json:"warnings,omitempty"`
+
+	// Requests versus their copies sent to Config.Mirror, by label (see CompareMirror)
+	Mirror map[string]MirrorComparison `json:"mirror,omitempty"`
+
+	// Config.Thresholds checked against this summary (see CheckThresholds)
+	Thresholds []ThresholdResult `json:"thresholds,omitempty"`
+
+	// Measured requests in flight against Little's Law (see LittlesLaw)
+	Concurrency *Concurrency `json:"concurrency,omitempty"`
+
+	// Requests by the peer IP they were sent to, which shows how evenly a
+	// multi-address host or load balancer spread them
+	ByAddr map[string]AddrSummary `json:"by_addr,omitempty"`
+
+	// Per-label breakdown and the run's tags (Config.Label / Config.Tags)
+	ByLabel map[string]LabelSummary `json:"by_label,omitempty"`
+	Tags    map[string]string       `json:"tags,omitempty"`
+
+	// Host, build and repo details of the run (see runner.RunMetadata)
+	Metadata *runner.RunMetadata `json:"metadata,omitempty"`
+}
+
+// Latencies are the percentiles of one set of durations, in milliseconds
+type Latencies struct {
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P95  float64 `json:"p95"`
+	P99  float64 `json:"p99"`
+	Mean float64 `json:"mean"`
+	Max  float64 `json:"max"`
+	Min  float64 `json:"min"`
+
+	// Dispersion: population standard deviation, and the mean without the
+	// top and bottom TrimPercent
+	StdDev      float64 `json:"stddev"`
+	TrimmedMean float64 `json:"trimmed_mean"`
+}
+
+// TrimPercent is the share (%) of the fastest and of the slowest samples a
+// trimmed mean leaves out, the same as for live histograms
+const TrimPercent = stats.TrimPercent
+
+// PercentileValue is the service time (ms) at percentile P
+type PercentileValue struct {
+	P  float64 `json:"p"`
+	Ms float64 `json:"ms"`
+}
+
+// LabelSummary is the share of a run carrying one request label
+type LabelSummary struct {
+	Requests uint64  `json:"requests"`
+	Fail     uint64  `json:"fail"`
+	P50      float64 `json:"p50_ms"`
+	P99      float64 `json:"p99_ms"`
+}
+
+// AddrSummary is the share of a run sent to one peer IP
+type AddrSummary struct {
+	Requests uint64  `json:"requests"`
+	Share    float64 `json:"share"` // Percent of the requests that reached a peer
+	Fail     uint64  `json:"fail"`
+	P50      float64 `json:"p50_ms"`
+	P99      float64 `json:"p99_ms"`
+}
+
+// Summarize derives the summary of results. elapsed is the run's wall time
+// for the achieved RPS; when 0 it is the span from the first request sent
+// to the last response.
+func Summarize(results []runner.ExperimentResult, elapsed time.Duration) Summary {
+	s := Summary{
+		StatusCodes: make(map[int]int),
+		Errors:      make(map[string]int),
+		ApdexTMs:    ms(ApdexT),
+		Duration:    elapsed,
+	}
+	if len(results) == 0 {
+		return s
+	}
+
+	var latencies, service, notModified, full []float64
+	var connect, newConn, reusedConn []float64
+	var tlsFull, tlsResumed []float64
+	var satisfied, tolerating float64
+	byLabel := make(map[string][]float64)
+	labelFails := make(map[string]uint64)
+	byAddr := make(map[string][]float64)
+	addrFails := make(map[string]uint64)
+	first, last := results[0].TimeStamp, results[0].TimeStamp
+	for _, r := range results {
+		first = minTime(first, r.TimeStamp)
+		last = maxTime(last, r.TimeStamp.Add(r.Latency))
+
+		lat := ms(r.Latency)
+		latencies = append(latencies, lat)
+		label := Label(r)
+		byLabel[label] = append(byLabel[label], lat)
+		addr := peerIP(r.RemoteAddr)
+		if addr != "" {
+			byAddr[addr] = append(byAddr[addr], lat)
+		}
+		s.TotalBytes += r.Bytes
+		s.SentBytes += r.SentHeaderBytes + r.SentBytes
+		s.RecvBytes += r.RecvHeaderBytes + r.Bytes
+		s.StatusCodes[r.Status]++
+		if r.Err != nil {
+			s.Errors[r.Err.Error()]++
+		}
+		if r.Conditional {
+			s.Conditional++
+		}
+		if r.NewConn {
+			s.NewConns++
+			connect = append(connect, ms(r.ConnectTime))
+		} else if r.Reused {
+			s.ReusedConns++
+		}
+		if r.TLS != nil {
+			if s.TLSVersions == nil {
+				s.TLSVersions, s.TLSCiphers = make(map[string]int), make(map[string]int)
+			}
+			s.TLSVersions[r.TLS.Version]++
+			s.TLSCiphers[r.TLS.Cipher]++
+			if r.TLS.Handshake > 0 && r.TLS.Resumed {
+				tlsResumed = append(tlsResumed, ms(r.TLS.Handshake))
+			} else if r.TLS.Handshake > 0 {
+				tlsFull = append(tlsFull, ms(r.TLS.Handshake))
+			}
+		}
+		if !r.Success {
+			labelFails[label]++
+			if addr != "" {
+				addrFails[addr]++
+			}
+			continue
+		}
+		s.TotalSuccess++
+		service = append(service, ms(r.ServiceTime))
+		if r.Conditional && r.Status == http.StatusNotModified {
+			notModified = append(notModified, ms(r.ServiceTime))
+		} else {
+			full = append(full, ms(r.ServiceTime))
+		}
+		if r.NewConn {
+			newConn = append(newConn, ms(r.ServiceTime))
+		} else if r.Reused {
+			reusedConn = append(reusedConn, ms(r.ServiceTime))
+		}
+		switch {
+		case r.Latency <= ApdexT:
+			satisfied++
+		case r.Latency <= 4*ApdexT:
+			tolerating++
+		}
+	}
+
+	n := uint64(len(results))
+	s.TotalRequests = n
+	s.TotalFail = n - s.TotalSuccess
+	s.ErrorRate = float64(s.TotalFail) / float64(n) * 100
+	s.Apdex = (satisfied + tolerating/2) / float64(n)
+
+	all := percentiles(latencies)
+	s.P50, s.P90, s.P95, s.P99 = all.P50, all.P90, all.P95, all.P99
+	s.Mean, s.Max, s.Min = all.Mean, all.Max, all.Min
+	s.StdDev, s.TrimmedMean = all.StdDev, all.TrimmedMean
+	s.Service = percentiles(service)
+	s.Warnings = SampleWarnings(uint64(len(service)))
+	if s.Conditional > 0 {
+		s.NotModified = uint64(len(notModified))
+		nm, f := percentiles(notModified), percentiles(full)
+		s.NotModifiedMs, s.FullMs = &nm, &f
+	}
+	if s.NewConns+s.ReusedConns > 0 {
+		c, nc, rc := percentiles(connect), percentiles(newConn), percentiles(reusedConn)
+		s.ConnectMs, s.NewConnMs, s.ReusedConnMs = &c, &nc, &rc
+	}
+	if len(tlsFull) > 0 {
+		f := percentiles(tlsFull)
+		s.TLSFullMs = &f
+	}
+	if len(tlsResumed) > 0 {
+		r := percentiles(tlsResumed)
+		s.TLSResumedMs = &r
+	}
+	s.TLSResumed = uint64(len(tlsResumed))
+	s.TLSHandshakes = uint64(len(tlsFull)) + s.TLSResumed
+
+	if s.Duration <= 0 {
+		s.Duration = last.Sub(first)
+	}
+	if s.Duration > 0 {
+		s.AverageRPS = float64(n) / s.Duration.Seconds()
+	}
+
+	s.ByLabel = make(map[string]LabelSummary, len(byLabel))
+	for label, lats := range byLabel {
+		sort.Float64s(lats)
+		s.ByLabel[label] = LabelSummary{
+			Requests: uint64(len(lats)),
+			Fail:     labelFails[label],
+			P50:      Percentile(lats, 50),
+			P99:      Percentile(lats, 99),
+		}
+	}
+	if len(byAddr) > 0 {
+		var reached int
+		for _, lats := range byAddr {
+			reached += len(lats)
+		}
+		s.ByAddr = make(map[string]AddrSummary, len(byAddr))
+		for addr, lats := range byAddr {
+			sort.Float64s(lats)
+			s.ByAddr[addr] = AddrSummary{
+				Requests: uint64(len(lats)),
+				Share:    float64(len(lats)) / float64(reached) * 100,
+				Fail:     addrFails[addr],
+				P50:      Percentile(lats, 50),
+				P99:      Percentile(lats, 99),
+			}
+		}
+	}
+	s.Tags = results[0].Tags
+	return s
+}
+
+// FormatBytes renders a byte count with a decimal unit: 512 B, 1.5 kB, 2.3 MB
+func FormatBytes(n float64) string {
+	units := []string{"B", "kB", "MB", "GB", "TB"}
+	i := 0
+	for n >= 1000 && i < len(units)-1 {
+		n /= 1000
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f B", n)
+	}
+	return fmt.Sprintf("%.1f %s", n, units[i])
+}
+
+// peerIP is the IP of a RemoteAddr ("ip:port"), "" when there is none
+func peerIP(remote string) string {
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		return host
+	}
+	return remote
+}
+
+// ReuseRate is the percent of HTTP requests sent on a pooled connection
+func (s Summary) ReuseRate() float64 {
+	if n := s.NewConns + s.ReusedConns; n > 0 {
+		return float64(s.ReusedConns) / float64(n) * 100
+	}
+	return 0
+}
+
+// MinSamples is how many samples the p-th percentile needs to have 10 above
+// it, so it is not set by a handful of outliers: 1000 for P99, 20 for P50
+func MinSamples(p float64) uint64 {
+	if p >= 100 {
+		return 0
+	}
+	return uint64(math.Ceil(1000 / (100 - p)))
+}
+
+// SampleWarnings lists the reported percentiles (P50-P99) that n samples
+// are too few for, highest first
+func SampleWarnings(n uint64) []string {
+	var warnings []string
+	for _, p := range []float64{99, 95, 90, 50} {
+		if need := MinSamples(p); n > 0 && n < need {
+			warnings = append(warnings, fmt.Sprintf("P%g from %d samples: needs %d+ to be reliable", p, n, need))
+		}
+	}
+	return warnings
+}
+
+// Label is the CSV/summary label of a result (older results have none)
+func Label(res runner.ExperimentResult) string {
+	if res.Label != "" {
+		return res.Label
+	}
+	return runner.DefaultLabel
+}
+
+// Percentile is the nearest-rank p-th percentile (0-100) of sorted values
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	return sorted[min(max(i, 0), len(sorted)-1)]
+}
+
+// ServicePercentiles is the service time of successful results at each of ps
+func ServicePercentiles(results []runner.ExperimentResult, ps []float64) []PercentileValue {
+	var service []float64
+	for _, r := range results {
+		if r.Success {
+			service = append(service, ms(r.ServiceTime))
+		}
+	}
+	sort.Float64s(service)
+	out := make([]PercentileValue, len(ps))
+	for i, p := range ps {
+		out[i] = PercentileValue{P: p, Ms: Percentile(service, p)}
+	}
+	return out
+}
+
+// percentiles sorts values and summarizes them
+func percentiles(values []float64) Latencies {
+	if len(values) == 0 {
+		return Latencies{}
+	}
+	sort.Float64s(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return Latencies{
+		P50:         Percentile(values, 50),
+		P90:         Percentile(values, 90),
+		P95:         Percentile(values, 95),
+		P99:         Percentile(values, 99),
+		Mean:        mean,
+		Max:         values[len(values)-1],
+		Min:         values[0],
+		StdDev:      math.Sqrt(variance / float64(len(values))),
+		TrimmedMean: trimmedMean(values),
+	}
+}
+
+// trimmedMean is the mean of sorted values without the lowest and the
+// highest TrimPercent
+func trimmedMean(sorted []float64) float64 {
+	drop := int(float64(len(sorted)) * TrimPercent / 100)
+	kept := sorted[drop : len(sorted)-drop]
+	sum := 0.0
+	for _, v := range kept {
+		sum += v
+	}
+	return sum / float64(len(kept))
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
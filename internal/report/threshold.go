@@ -0,0 +1,59 @@
+package report
+
+import "steadyq/internal/runner"
+
+// ThresholdResult is one threshold (Config.Thresholds) checked against a
+// finished run
+type ThresholdResult struct {
+	Threshold string  `json:"threshold"`
+	Value     float64 `json:"value"`
+	Pass      bool    `json:"pass"`
+}
+
+// CheckThresholds evaluates ths against the summary, with the same metrics
+// the dashboard shows live
+func CheckThresholds(s Summary, ths []runner.Threshold) []ThresholdResult {
+	var out []ThresholdResult
+	for _, t := range ths {
+		v := s.metric(t.Metric)
+		out = append(out, ThresholdResult{Threshold: t.String(), Value: v, Pass: t.Pass(v)})
+	}
+	return out
+}
+
+// ThresholdsPassed reports whether every checked threshold passed
+func (s Summary) ThresholdsPassed() bool {
+	for _, t := range s.Thresholds {
+		if !t.Pass {
+			return false
+		}
+	}
+	return true
+}
+
+// metric is the value of a runner.ThresholdMetrics name
+func (s Summary) metric(name string) float64 {
+	switch name {
+	case "p50":
+		return s.Service.P50
+	case "p90":
+		return s.Service.P90
+	case "p95":
+		return s.Service.P95
+	case "p99":
+		return s.Service.P99
+	case "mean":
+		return s.Service.Mean
+	case "trimmed_mean":
+		return s.Service.TrimmedMean
+	case "stddev":
+		return s.Service.StdDev
+	case "max":
+		return s.Service.Max
+	case "error_rate":
+		return s.ErrorRate
+	case "rps":
+		return s.AverageRPS
+	}
+	return 0
+}
@@ -0,0 +1,95 @@
+package runner
+
+import (
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"steadyq/internal/stats"
+)
+
+// abortGuard ends a run early when it is clearly hammering a broken target
+// (Config.AbortErrorRate, AbortP99) or has run past Config.MaxDuration. It is
+// checked once per second with that second's completions.
+type abortGuard struct {
+	cfg   Config
+	start time.Time
+	last  [2]uint64 // Requests and Fail at the previous check
+
+	errSecs int // Consecutive seconds above AbortErrorRate
+	p99Secs int // Consecutive seconds above AbortP99
+}
+
+func newAbortGuard(cfg Config, start time.Time) *abortGuard {
+	return &abortGuard{cfg: cfg, start: start}
+}
+
+// check returns why the run must stop, "" to carry on. Seconds without
+// completions neither breach nor reset a condition.
+func (g *abortGuard) check(now time.Time, requests, fail uint64, sec *stats.SafeHistogram) string {
+	done, failed := requests-g.last[0], fail-g.last[1]
+	g.last = [2]uint64{requests, fail}
+	sustain := int(g.cfg.GetAbortAfter() / time.Second)
+
+	if g.cfg.MaxDuration > 0 && now.Sub(g.start) >= g.cfg.MaxDuration {
+		return fmt.Sprintf("max duration %s reached", g.cfg.MaxDuration)
+	}
+
+	if g.cfg.AbortErrorRate > 0 && done > 0 {
+		rate := float64(failed) / float64(done) * 100
+		if rate > g.cfg.AbortErrorRate {
+			g.errSecs++
+		} else {
+			g.errSecs = 0
+		}
+		if g.errSecs >= sustain {
+			return fmt.Sprintf("error rate above %.1f%% for %ds (last second: %.1f%%)", g.cfg.AbortErrorRate, g.errSecs, rate)
+		}
+	}
+
+	if g.cfg.AbortP99 > 0 && sec != nil && sec.TotalCount() > 0 {
+		p99 := time.Duration(sec.ValueAtQuantile(99)) * time.Microsecond
+		if p99 > g.cfg.AbortP99 {
+			g.p99Secs++
+		} else {
+			g.p99Secs = 0
+		}
+		if g.p99Secs >= sustain {
+			return fmt.Sprintf("P99 above %s for %ds (last second: %s)", g.cfg.AbortP99, g.p99Secs, p99.Round(time.Millisecond))
+		}
+	}
+	return ""
+}
+
+// WantsAbortGuard reports whether any early-stop condition is configured
+func (c Config) WantsAbortGuard() bool {
+	return c.AbortErrorRate > 0 || c.AbortP99 > 0 || c.MaxDuration > 0
+}
+
+// checkAbort evaluates the early-stop conditions with the second just closed
+// and stops the run on the first breach
+func (r *Runner) checkAbort(sec *stats.SafeHistogram) {
+	if r.guard == nil || r.AbortReason() != "" {
+		return
+	}
+	reason := r.guard.check(time.Now(), atomic.LoadUint64(&r.Stats.Requests), atomic.LoadUint64(&r.Stats.Fail), sec)
+	if reason == "" {
+		return
+	}
+	slog.Warn("abort condition met, stopping run", "run_id", r.Meta.RunID, "reason", reason)
+	r.mu.Lock()
+	r.abortReason = reason
+	stop := r.stopRun
+	r.mu.Unlock()
+	if stop != nil {
+		stop()
+	}
+}
+
+// AbortReason says why the run was stopped early by an abort condition, "" if it was not
+func (r *Runner) AbortReason() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.abortReason
+}
@@ -0,0 +1,143 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// bandwidthChunk is the largest body read released at once, keeping the
+// egress rate smooth at low caps
+const bandwidthChunk = 16 << 10
+
+// bandwidthLimiter is a token bucket over bytes, shared by all requests it throttles
+type bandwidthLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // Bytes per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newBandwidthLimiter(bytesPerSec int64) *bandwidthLimiter {
+	burst := max(float64(bandwidthChunk), float64(bytesPerSec)/10)
+	return &bandwidthLimiter{rate: float64(bytesPerSec), burst: burst, tokens: burst, last: time.Now()}
+}
+
+// wait takes n bytes from the bucket, sleeping while it is in debt
+func (l *bandwidthLimiter) wait(ctx context.Context, n int) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens -= float64(n)
+	debt := l.tokens
+	l.mu.Unlock()
+
+	if debt >= 0 {
+		return nil
+	}
+	t := time.NewTimer(time.Duration(-debt / l.rate * float64(time.Second)))
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// globalBandwidth caps the egress of every run in the process (nil = unlimited)
+var globalBandwidth atomic.Pointer[bandwidthLimiter]
+
+// SetGlobalBandwidth caps request body egress across all concurrent runs (0 = unlimited)
+func SetGlobalBandwidth(bytesPerSec int64) {
+	if bytesPerSec <= 0 {
+		globalBandwidth.Store(nil)
+		return
+	}
+	globalBandwidth.Store(newBandwidthLimiter(bytesPerSec))
+}
+
+// throttle wraps a request body so it is released no faster than the run's and
+// the global bandwidth caps, and the emulated client uplink, allow. Returns body
+// unchanged when none is set.
+func (r *Runner) throttle(ctx context.Context, body io.Reader, uplink *bandwidthLimiter) io.Reader {
+	var limiters []*bandwidthLimiter
+	if uplink != nil {
+		limiters = append(limiters, uplink)
+	}
+	if r.bandwidth != nil {
+		limiters = append(limiters, r.bandwidth)
+	}
+	if g := globalBandwidth.Load(); g != nil {
+		limiters = append(limiters, g)
+	}
+	if len(limiters) == 0 || body == nil {
+		return body
+	}
+	return &throttledReader{ctx: ctx, r: body, limiters: limiters}
+}
+
+type throttledReader struct {
+	ctx      context.Context
+	r        io.Reader
+	limiters []*bandwidthLimiter
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > bandwidthChunk {
+		p = p[:bandwidthChunk]
+	}
+	n, err := t.r.Read(p)
+	for _, l := range t.limiters {
+		if werr := l.wait(t.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+// ParseBandwidth parses a rate like "50Mbit", "50Mbps", "2MB/s" or "512KB" into
+// bytes per second. Lowercase b / bit / bps mean bits, uppercase B bytes;
+// k, M and G are decimal. A plain number is bytes per second; "" is 0 (unlimited).
+func ParseBandwidth(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	i := 0
+	for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+		i++
+	}
+	num, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil || num < 0 {
+		return 0, fmt.Errorf("invalid bandwidth %q (e.g. 50Mbit or 2MB/s)", s)
+	}
+	unit := strings.TrimSuffix(strings.TrimSpace(s[i:]), "/s")
+
+	mult := 1.0
+	if unit != "" {
+		switch unit[0] {
+		case 'k', 'K':
+			mult, unit = 1e3, unit[1:]
+		case 'm', 'M':
+			mult, unit = 1e6, unit[1:]
+		case 'g', 'G':
+			mult, unit = 1e9, unit[1:]
+		}
+	}
+	switch unit {
+	case "", "B":
+	case "b", "bit", "bits", "bps":
+		mult /= 8
+	default:
+		return 0, fmt.Errorf("invalid bandwidth unit in %q (e.g. 50Mbit or 2MB/s)", s)
+	}
+	return int64(num * mult), nil
+}
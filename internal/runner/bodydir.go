@@ -0,0 +1,107 @@
+package runner
+
+import (
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// bodyFile is one payload of Config.BodyDir
+type bodyFile struct {
+	Name        string
+	Weight      int
+	Raw         string
+	Tmpl        *template.Template // nil when the file is not a valid template
+	ContentType string             // From the extension, "" when unknown
+}
+
+// initBodyDir loads every regular file of Config.BodyDir as a weighted payload.
+// Files are templated like Body; files that fail to parse are sent verbatim.
+func (r *Runner) initBodyDir() error {
+	r.bodyFiles = nil
+	r.bodyWeight = 0
+	if r.Cfg.BodyDir == "" {
+		return nil
+	}
+
+	entries, err := os.ReadDir(r.Cfg.BodyDir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !e.Type().IsRegular() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		weight := 1
+		if w, ok := r.Cfg.BodyWeights[e.Name()]; ok {
+			weight = w
+		}
+		if weight <= 0 {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(r.Cfg.BodyDir, e.Name()))
+		if err != nil {
+			return err
+		}
+		f := bodyFile{
+			Name:        e.Name(),
+			Weight:      weight,
+			Raw:         string(data),
+			ContentType: mime.TypeByExtension(filepath.Ext(e.Name())),
+		}
+		if strings.Contains(f.Raw, "{{") {
+			if t, err := r.TmplEngine.Parse("body:"+e.Name(), f.Raw); err == nil {
+				f.Tmpl = t
+			}
+		}
+		r.bodyFiles = append(r.bodyFiles, f)
+		r.bodyWeight += weight
+	}
+	if len(r.bodyFiles) == 0 {
+		return fmt.Errorf("%s: no body files", r.Cfg.BodyDir)
+	}
+	return nil
+}
+
+// pickBody selects a payload from the body directory by weight
+func (r *Runner) pickBody() *bodyFile {
+	pick := r.rand.Intn(r.bodyWeight)
+	for i := range r.bodyFiles {
+		if pick < r.bodyFiles[i].Weight {
+			return &r.bodyFiles[i]
+		}
+		pick -= r.bodyFiles[i].Weight
+	}
+	return &r.bodyFiles[len(r.bodyFiles)-1]
+}
+
+// CheckBodyDir validates a body directory and its weights before a run and
+// returns how many payloads it provides
+func CheckBodyDir(dir string, weights map[string]int) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, e := range entries {
+		if !e.Type().IsRegular() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		if w, ok := weights[e.Name()]; ok && w <= 0 {
+			continue
+		}
+		n++
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("%s: no body files", dir)
+	}
+	for name := range weights {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return 0, fmt.Errorf("weight for %s: no such file in %s", name, dir)
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,147 @@
+package runner
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is recorded for requests the client-side breaker refused to send
+var ErrCircuitOpen = errors.New("circuit open (not sent)")
+
+// Breaker States
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// CircuitBreaker simulates a resilient client fleet: once the error rate in the
+// rolling window crosses the threshold the circuit opens and requests are shed
+// until the cooldown elapses, then a few half-open probes decide whether to close.
+type CircuitBreaker struct {
+	ErrorRate   float64
+	MinRequests int
+	Window      time.Duration
+	Cooldown    time.Duration
+	Probes      int
+
+	mu             sync.Mutex
+	state          string
+	windowStart    time.Time
+	windowTotal    int
+	windowFail     int
+	openedAt       time.Time
+	probesInflight int
+	probesOK       int
+}
+
+func NewCircuitBreaker(cfg Config) *CircuitBreaker {
+	b := &CircuitBreaker{
+		ErrorRate:   cfg.BreakerErrorRate,
+		MinRequests: cfg.BreakerMinRequests,
+		Window:      cfg.BreakerWindow,
+		Cooldown:    cfg.BreakerCooldown,
+		Probes:      cfg.BreakerProbes,
+		state:       BreakerClosed,
+		windowStart: time.Now(),
+	}
+	if b.MinRequests <= 0 {
+		b.MinRequests = 20
+	}
+	if b.Window <= 0 {
+		b.Window = 10 * time.Second
+	}
+	if b.Cooldown <= 0 {
+		b.Cooldown = 5 * time.Second
+	}
+	if b.Probes <= 0 {
+		b.Probes = 1
+	}
+	return b
+}
+
+// Allow reports whether a request may be sent, and whether it is a half-open probe
+func (b *CircuitBreaker) Allow() (bool, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.Cooldown {
+			return false, false
+		}
+		b.state = BreakerHalfOpen
+		b.probesInflight = 0
+		b.probesOK = 0
+		fallthrough
+	case BreakerHalfOpen:
+		if b.probesInflight+b.probesOK >= b.Probes {
+			return false, false
+		}
+		b.probesInflight++
+		return true, true
+	}
+	return true, false
+}
+
+// Record feeds the outcome of a sent request back into the breaker
+func (b *CircuitBreaker) Record(success, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if probe {
+		if b.state != BreakerHalfOpen {
+			return
+		}
+		b.probesInflight--
+		if !success {
+			b.trip(now)
+			return
+		}
+		b.probesOK++
+		if b.probesOK >= b.Probes {
+			slog.Info("circuit breaker closed", "probes_ok", b.probesOK)
+			b.state = BreakerClosed
+			b.resetWindow(now)
+		}
+		return
+	}
+
+	if b.state != BreakerClosed {
+		return
+	}
+	if now.Sub(b.windowStart) > b.Window {
+		b.resetWindow(now)
+	}
+	b.windowTotal++
+	if !success {
+		b.windowFail++
+	}
+	if b.windowTotal >= b.MinRequests && float64(b.windowFail)/float64(b.windowTotal) >= b.ErrorRate {
+		b.trip(now)
+	}
+}
+
+// State returns the current breaker state
+func (b *CircuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *CircuitBreaker) trip(now time.Time) {
+	slog.Warn("circuit breaker opened", "from", b.state, "error_rate", b.ErrorRate, "cooldown", b.Cooldown)
+	b.state = BreakerOpen
+	b.openedAt = now
+	b.probesInflight = 0
+	b.probesOK = 0
+}
+
+func (b *CircuitBreaker) resetWindow(now time.Time) {
+	b.windowStart = now
+	b.windowTotal = 0
+	b.windowFail = 0
+}
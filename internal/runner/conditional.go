@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"net/http"
+	"sync"
+)
+
+// maxValidators bounds the validator cache; past it new URLs are sent
+// unconditionally (templated URLs can be endless)
+const maxValidators = 100000
+
+// validators are the cache validators a response carried
+type validators struct {
+	ETag         string
+	LastModified string
+}
+
+// validatorCache remembers the validators of earlier responses for
+// Config.Conditional. Users mode keys them per user and URL, like a browser
+// cache; open-loop requests share one cache per URL.
+type validatorCache struct {
+	mu sync.Mutex
+	m  map[string]validators
+}
+
+func newValidatorCache() *validatorCache {
+	return &validatorCache{m: make(map[string]validators)}
+}
+
+func (r *Runner) validatorKey(userID, url string) string {
+	if r.Cfg.Mode == "users" {
+		return userID + " " + url
+	}
+	return url
+}
+
+// addConditional sets If-None-Match / If-Modified-Since from the cached
+// validators of key and reports whether it did. Explicit headers win.
+func (c *validatorCache) addConditional(h http.Header, key string) bool {
+	c.mu.Lock()
+	v, ok := c.m[key]
+	c.mu.Unlock()
+	if !ok || h.Get("If-None-Match") != "" || h.Get("If-Modified-Since") != "" {
+		return false
+	}
+	if v.ETag != "" {
+		h.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		h.Set("If-Modified-Since", v.LastModified)
+	}
+	return true
+}
+
+// store keeps the validators of a full response; a 304 keeps the old ones
+func (c *validatorCache) store(key string, resp *http.Response) {
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+	v := validators{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v.ETag == "" && v.LastModified == "" {
+		delete(c.m, key)
+		return
+	}
+	if _, ok := c.m[key]; ok || len(c.m) < maxValidators {
+		c.m[key] = v
+	}
+}
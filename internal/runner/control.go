@@ -0,0 +1,78 @@
+package runner
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// pausePoll is how often paused schedulers and users check for a resume
+const pausePoll = 100 * time.Millisecond
+
+// runControl holds live adjustments made while a run is active (headless
+// keyboard commands). Pausing does not extend the run's duration.
+type runControl struct {
+	paused    atomic.Bool
+	rateDelta atomic.Int64 // Added to Config.TargetRPS
+	started   atomic.Int64 // Requests started, for Config.MaxRequests
+}
+
+func (c *runControl) reset() {
+	c.paused.Store(false)
+	c.rateDelta.Store(0)
+	c.started.Store(0)
+}
+
+// takeRequest claims the next request of the run; false once
+// Config.MaxRequests have been started
+func (r *Runner) takeRequest() bool {
+	if r.Cfg.MaxRequests <= 0 {
+		return true
+	}
+	if r.control.started.Add(1) > int64(r.Cfg.MaxRequests) {
+		r.setState(StateDraining, StateRunning)
+		return false
+	}
+	return true
+}
+
+// SetPaused stops (or resumes) sending new requests; in-flight ones finish.
+// Users pause at their next iteration boundary.
+func (r *Runner) SetPaused(paused bool) {
+	r.control.paused.Store(paused)
+}
+
+// Paused reports whether the run is paused
+func (r *Runner) Paused() bool {
+	return r.control.paused.Load()
+}
+
+// AdjustRate changes the open-loop target by delta RPS, never below 1, and
+// returns the new target. Ramps scale toward the adjusted target.
+func (r *Runner) AdjustRate(delta int) int {
+	for {
+		cur := r.control.rateDelta.Load()
+		next := max(cur+int64(delta), int64(1-r.Cfg.TargetRPS))
+		if r.control.rateDelta.CompareAndSwap(cur, next) {
+			return r.Cfg.TargetRPS + int(next)
+		}
+	}
+}
+
+// TargetRate is the open-loop target including live adjustments
+func (r *Runner) TargetRate() int {
+	return r.Cfg.TargetRPS + int(r.control.rateDelta.Load())
+}
+
+// waitWhilePaused blocks a virtual user at an iteration boundary until the run
+// is resumed; false if ctx ended first
+func (r *Runner) waitWhilePaused(ctx context.Context) bool {
+	for r.Paused() {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(pausePoll):
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package runner
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DNSPin resolves each host once and dials the pinned addresses, round robin,
+// so resolver hiccups stay out of the results (Config.PinDNS). With a TTL the
+// host is re-resolved that often; a changed answer is logged and counted.
+// A failed re-resolution keeps the previous addresses.
+type DNSPin struct {
+	ttl      time.Duration
+	resolver *net.Resolver
+
+	mu      sync.Mutex
+	hosts   map[string]*pinnedHost
+	changes atomic.Uint64
+}
+
+type pinnedHost struct {
+	addrs    []string
+	resolved time.Time
+	next     atomic.Uint64
+}
+
+func newDNSPin(ttl time.Duration) *DNSPin {
+	return &DNSPin{ttl: ttl, resolver: net.DefaultResolver, hosts: make(map[string]*pinnedHost)}
+}
+
+// Hosts returns the currently pinned addresses by host
+func (p *DNSPin) Hosts() map[string][]string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	hosts := make(map[string][]string, len(p.hosts))
+	for host, h := range p.hosts {
+		hosts[host] = slices.Clone(h.addrs)
+	}
+	return hosts
+}
+
+// Changes is how many re-resolutions returned different addresses
+func (p *DNSPin) Changes() uint64 {
+	return p.changes.Load()
+}
+
+// lookup returns the pinned addresses of host, resolving it the first time
+// and once the TTL has passed
+func (p *DNSPin) lookup(ctx context.Context, host string) ([]string, *pinnedHost, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h := p.hosts[host]
+	if h != nil && (p.ttl <= 0 || time.Since(h.resolved) < p.ttl) {
+		return h.addrs, h, nil
+	}
+
+	addrs, err := p.resolver.LookupHost(ctx, host)
+	if err != nil {
+		if h != nil {
+			slog.Warn("dns re-resolution failed, keeping pinned addresses", "host", host, "addrs", h.addrs, "err", err)
+			h.resolved = time.Now()
+			return h.addrs, h, nil
+		}
+		return nil, nil, err
+	}
+	slices.Sort(addrs)
+	if h == nil {
+		h = &pinnedHost{}
+		p.hosts[host] = h
+		slog.Info("dns pinned", "host", host, "addrs", addrs)
+	} else if !slices.Equal(h.addrs, addrs) {
+		p.changes.Add(1)
+		slog.Warn("dns answer changed mid-run", "host", host, "from", h.addrs, "to", addrs)
+	}
+	h.addrs, h.resolved = addrs, time.Now()
+	return addrs, h, nil
+}
+
+// resolve pins the host of target (a URL or host[:port]) ahead of the first
+// dial; templated or unparsable targets are left to the first dial
+func (p *DNSPin) resolve(ctx context.Context, target string) {
+	host := target
+	if u, err := url.Parse(target); err == nil && u.Host != "" {
+		host = u.Hostname()
+	} else if h, _, err := net.SplitHostPort(target); err == nil {
+		host = h
+	}
+	if host == "" || strings.Contains(host, "{{") || net.ParseIP(host) != nil {
+		return
+	}
+	if _, _, err := p.lookup(ctx, host); err != nil {
+		slog.Warn("dns pre-resolution failed", "host", host, "err", err)
+	}
+}
+
+// dialer wraps dial to connect to the pinned addresses of the host in addr
+func (p *DNSPin) dialer(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || net.ParseIP(host) != nil {
+			return dial(ctx, network, addr)
+		}
+		addrs, h, err := p.lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		ip := addrs[int(h.next.Add(1)-1)%len(addrs)]
+		return dial(ctx, network, net.JoinHostPort(ip, port))
+	}
+}
@@ -0,0 +1,158 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"time"
+)
+
+// Response is what an executor observed for one request. The orchestrator
+// (executeRequest) adds timing, success, breaker and stats bookkeeping.
+type Response struct {
+	Status      int   // HTTP status, or 200/exit code for other protocols
+	Bytes       int64 // Received
+	SentBytes   int64
+	Body        string // Failure body (kept for samples and failure signatures), see maxResponseBody
+	ConnectTime time.Duration
+	RetryAfter  time.Duration // Server-requested backoff (HTTP 429/503)
+	URL         string        // Target actually hit, "" for scripts
+	Method      string
+	HeadersHash string
+	RemoteAddr  string
+	Query       string // Payload label: redis command, body file, "custom"
+	Conditional bool   // Sent with cached validators (Config.Conditional)
+	NewConn     bool   // HTTP: sent on a connection opened for it
+	Reused      bool   // HTTP: sent on a pooled keep-alive connection
+	TLS         *TLSInfo
+	RequestID   string // Config.RequestIDHeader value sent
+	Err         error
+
+	// HTTP: size of the request head sent and the response head received
+	// (start line and headers), see requestHeadBytes
+	SentHeaderBytes int64
+	RecvHeaderBytes int64
+}
+
+// maxResponseBody is the most of a response body a result keeps: enough for
+// an error page's message, while a full buffer of results stays bounded
+const maxResponseBody = 4 << 10
+
+// Executor sends one request of a protocol. ctx is cancelled when the run is
+// aborted; executors apply the request timeout themselves.
+type Executor interface {
+	Execute(ctx context.Context, userID, reqID string) Response
+}
+
+// ExecutorFunc adapts a function to an Executor
+type ExecutorFunc func(ctx context.Context, userID, reqID string) Response
+
+func (f ExecutorFunc) Execute(ctx context.Context, userID, reqID string) Response {
+	return f(ctx, userID, reqID)
+}
+
+// executors builds the executor of each protocol for a set-up Runner; a new
+// mode registers here. "command" is used whenever Config.Command is set,
+// "fasthttp" for HTTP with Config.HTTPEngine "fasthttp".
+var executors = map[string]func(r *Runner) Executor{
+	"command":  func(r *Runner) Executor { return ExecutorFunc(r.executeCommand) },
+	"http":     func(r *Runner) Executor { return ExecutorFunc(r.executeHTTP) },
+	"fasthttp": newFastHTTPExecutor,
+	"tcp":      socketExecutor("tcp"),
+	"udp":      socketExecutor("udp"),
+	"redis": func(r *Runner) Executor {
+		return ExecutorFunc(func(ctx context.Context, userID, reqID string) Response {
+			var res Response
+			res.Status, res.Bytes, res.Body, res.Query, res.Err = r.executeRedis(ctx, userID, reqID)
+			res.URL = r.Cfg.URL
+			return res
+		})
+	},
+	"kafka": func(r *Runner) Executor {
+		return ExecutorFunc(func(ctx context.Context, userID, reqID string) Response {
+			res := Response{URL: r.Cfg.URL, Query: "custom"}
+			res.Status, res.Bytes, res.Body, res.Err = r.executeKafka(ctx, userID, reqID)
+			return res
+		})
+	},
+	"sql": func(r *Runner) Executor {
+		return ExecutorFunc(func(ctx context.Context, userID, reqID string) Response {
+			res := Response{URL: r.Cfg.URL, Query: "custom"}
+			res.Status, res.Bytes, res.Body, res.Err = r.executeSQL(ctx, userID, reqID)
+			return res
+		})
+	},
+}
+
+func socketExecutor(network string) func(r *Runner) Executor {
+	return func(r *Runner) Executor {
+		return ExecutorFunc(func(ctx context.Context, userID, reqID string) Response {
+			res := Response{URL: r.Cfg.URL, Query: "custom"}
+			res.ConnectTime, res.Status, res.Bytes, res.Body, res.Err = r.executeSocket(ctx, network, userID, reqID)
+			return res
+		})
+	}
+}
+
+// newExecutor picks the executor for the runner's config (HTTP when the
+// protocol has none)
+func (r *Runner) newExecutor() Executor {
+	name := r.Cfg.GetProtocol()
+	if r.Cfg.Command != "" {
+		name = "command"
+	} else if name == "http" && r.Cfg.HTTPEngine == EngineFastHTTP {
+		name = "fasthttp"
+	}
+	build, ok := executors[name]
+	if !ok {
+		build = executors["http"]
+	}
+	return build(r)
+}
+
+// commandWaitDelay is how long a killed command's output is still read
+const commandWaitDelay = 500 * time.Millisecond
+
+// executeCommand runs the templated shell command. Exit code 0 is status
+// 200, otherwise the exit code is the status and stderr the failure body.
+// The shell is killed once the request timeout passes or ctx ends.
+func (r *Runner) executeCommand(ctx context.Context, userID, reqID string) Response {
+	cmdStr := r.Cfg.Command
+	if r.TmplCmd != nil {
+		cmdStr = r.applyTemplates(r.TmplCmd, userID, reqID)
+	}
+	res := Response{Query: "custom"}
+
+	reqCtx, cancel := context.WithTimeout(ctx, r.Cfg.GetRequestTimeout())
+	defer cancel()
+
+	// sh -c allows pipes and other shell syntax
+	cmd := exec.CommandContext(reqCtx, "sh", "-c", cmdStr)
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	// Children of the killed shell may hold its output open; stop waiting for them
+	cmd.WaitDelay = commandWaitDelay
+
+	res.Err = cmd.Run()
+	if res.Err != nil && reqCtx.Err() != nil {
+		// Killed: the overall deadline, or the run's / iteration's context
+		res.Err = reqCtx.Err()
+		if ctx.Err() == nil {
+			res.Err = ErrRequestDeadline
+		}
+		return res
+	}
+	if res.Err == nil {
+		res.Status = 200
+		res.Bytes = int64(out.Len())
+		res.Body = out.String()
+		return res
+	}
+	res.Status = 500
+	if exitErr, ok := res.Err.(*exec.ExitError); ok {
+		res.Status = exitErr.ExitCode()
+	}
+	res.Body = stderr.String()
+	return res
+}
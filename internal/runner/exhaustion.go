@@ -0,0 +1,51 @@
+package runner
+
+import (
+	"errors"
+	"strings"
+	"syscall"
+)
+
+// Local resource exhaustion kinds: the load generator, not the server, ran out
+const (
+	ExhaustedPorts = "ports" // EADDRNOTAVAIL: no free ephemeral port for a new connection
+	ExhaustedFDs   = "fds"   // EMFILE/ENFILE: file descriptor limit reached
+)
+
+// exhaustionKind classifies err as local port/descriptor exhaustion, "" otherwise
+func exhaustionKind(err error) string {
+	if err == nil {
+		return ""
+	}
+	switch {
+	case errors.Is(err, syscall.EADDRNOTAVAIL):
+		return ExhaustedPorts
+	case errors.Is(err, syscall.EMFILE), errors.Is(err, syscall.ENFILE):
+		return ExhaustedFDs
+	}
+	// Script mode only sees the child's stderr
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "cannot assign requested address"):
+		return ExhaustedPorts
+	case strings.Contains(msg, "too many open files"):
+		return ExhaustedFDs
+	}
+	return ""
+}
+
+// exhaustionError is the error breakdown label, kept apart from server-side failures
+func exhaustionError(kind string) string {
+	if kind == ExhaustedPorts {
+		return "local: ephemeral ports exhausted (EADDRNOTAVAIL)"
+	}
+	return "local: too many open files (EMFILE)"
+}
+
+// ExhaustionHint explains how to fix local port/descriptor exhaustion
+func ExhaustionHint(kind string) string {
+	if kind == ExhaustedPorts {
+		return "Widen net.ipv4.ip_local_port_range, enable net.ipv4.tcp_tw_reuse, or lower --max-conns so connections are reused instead of re-dialed."
+	}
+	return "Raise the descriptor limit (ulimit -n), or lower --max-conns / --users."
+}
@@ -0,0 +1,156 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// HTTP client libraries for Config.HTTPEngine
+const (
+	EngineNetHTTP  = "net/http"
+	EngineFastHTTP = "fasthttp"
+)
+
+// HTTPEngines are the accepted Config.HTTPEngine values ("" = EngineNetHTTP)
+var HTTPEngines = []string{EngineNetHTTP, EngineFastHTTP}
+
+// FastHTTPUnsupported lists the options of cfg the fasthttp engine cannot
+// honour. Beyond these it speaks HTTP/1.1 only, does not decompress bodies
+// or report connection reuse, connect times and TLS sessions, and a request
+// in flight runs to its deadline even when the run is aborted.
+func FastHTTPUnsupported(cfg Config) []string {
+	var out []string
+	if cfg.Bandwidth != "" {
+		out = append(out, "bandwidth caps")
+	}
+	if cfg.NetworkProfile != "" {
+		out = append(out, "network profiles")
+	}
+	if cfg.Conditional {
+		out = append(out, "conditional requests")
+	}
+	if cfg.ResponseHeaderTimeout > 0 {
+		out = append(out, "the response header timeout")
+	}
+	return out
+}
+
+// fastHTTPExecutor sends HTTP requests through fasthttp, which reuses
+// request and response buffers instead of allocating per request
+type fastHTTPExecutor struct {
+	r      *Runner
+	client *fasthttp.Client
+}
+
+func newFastHTTPExecutor(r *Runner) Executor {
+	maxConns := defaultMaxConns
+	if r.Cfg.MaxConns > 0 {
+		maxConns = r.Cfg.MaxConns
+	}
+	dial := dialContext(r.Cfg, r.DNS)
+	return &fastHTTPExecutor{r: r, client: &fasthttp.Client{
+		MaxConnsPerHost: maxConns,
+		// Wait for a connection like net/http does instead of failing at once
+		MaxConnWaitTimeout: r.Cfg.GetRequestTimeout(),
+		// One attempt: a retried request would hide a failure
+		MaxIdemponentCallAttempts:     1,
+		TLSConfig:                     tlsConfig(r.Cfg),
+		DisableHeaderNamesNormalizing: true,
+		DisablePathNormalizing:        true,
+		DialTimeout: func(addr string, timeout time.Duration) (net.Conn, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			return dial(ctx, "tcp", addr)
+		},
+	}}
+}
+
+func (e *fastHTTPExecutor) Execute(ctx context.Context, userID, reqID string) Response {
+	r := e.r
+	rr := r.renderHTTP(userID, reqID)
+	if r.mirror != nil {
+		r.mirror.send(r, userID, rr)
+	}
+	res := Response{URL: rr.URL, Method: rr.Method, Query: "custom"}
+	if r.Cfg.RequestIDHeader != "" {
+		res.RequestID = rr.Header.Get(r.Cfg.RequestIDHeader)
+	}
+	if rr.BodyFile != "" {
+		res.Query = rr.BodyFile
+	}
+	if err := ctx.Err(); err != nil {
+		res.Err = err
+		return res
+	}
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(rr.URL)
+	req.Header.SetMethod(rr.Method)
+	for k, vs := range rr.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	if rr.Host != "" {
+		req.UseHostHeader = true
+		req.Header.SetHost(rr.Host)
+	}
+	if rr.HasBody {
+		req.SetBodyString(rr.Body)
+		res.SentBytes = int64(len(rr.Body))
+	}
+	res.HeadersHash = headersHash(rr.Header)
+
+	// fasthttp takes no context: the iteration's deadline, if sooner, is
+	// the request's
+	deadline := time.Now().Add(r.Cfg.GetRequestTimeout())
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	err := e.client.DoDeadline(req, resp, deadline)
+	res.SentHeaderBytes = int64(len(req.Header.Header()))
+	if errors.Is(err, fasthttp.ErrTimeout) {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		} else {
+			err = ErrRequestDeadline
+		}
+	}
+	if err != nil {
+		res.Err = fastHTTPError(err)
+		return res
+	}
+
+	if addr := resp.RemoteAddr(); addr != nil {
+		res.RemoteAddr = addr.String()
+	}
+	res.Status = resp.StatusCode()
+	res.RecvHeaderBytes = int64(len(resp.Header.Header()))
+	if r.Cfg.HonorRetryAfter && (res.Status == fasthttp.StatusTooManyRequests || res.Status == fasthttp.StatusServiceUnavailable) {
+		res.RetryAfter = parseRetryAfter(string(resp.Header.Peek("Retry-After")), time.Now())
+	}
+	body := resp.Body()
+	res.Bytes = int64(len(body))
+	if res.Status >= 400 {
+		res.Body = string(body[:min(len(body), maxResponseBody)])
+	}
+	return res
+}
+
+// fastHTTPError explains fasthttp's failure to get a pooled connection in
+// time, which net/http reports as the request deadline
+func fastHTTPError(err error) error {
+	if errors.Is(err, fasthttp.ErrNoFreeConns) {
+		return fmt.Errorf("no free connection within the request timeout (--max-conns): %w", err)
+	}
+	return err
+}
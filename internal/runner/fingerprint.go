@@ -0,0 +1,84 @@
+package runner
+
+import (
+	"bufio"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Fingerprint is the identity headers of one simulated client
+type Fingerprint struct {
+	UserAgent      string
+	AcceptLanguage string // "" leaves the header unset
+}
+
+// BuiltinFingerprints is a desktop and mobile browser mix, used with
+// Config.Fingerprints = "builtin"
+var BuiltinFingerprints = []Fingerprint{
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36", "en-US,en;q=0.9"},
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:125.0) Gecko/20100101 Firefox/125.0", "en-GB,en;q=0.7"},
+	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15", "en-US,en;q=0.9"},
+	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36", "de-DE,de;q=0.9,en;q=0.8"},
+	{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36", "en-US,en;q=0.9"},
+	{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1", "en-US,en;q=0.9"},
+	{"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36", "hi-IN,hi;q=0.9,en;q=0.8"},
+	{"Mozilla/5.0 (Linux; Android 13; SM-S911B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36", "es-ES,es;q=0.9"},
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0", "fr-FR,fr;q=0.9,en;q=0.8"},
+	{"okhttp/4.12.0", ""},
+}
+
+// LoadFingerprints resolves Config.Fingerprints: "" (none), "builtin", or a
+// file with one User-Agent per line, optionally followed by " | " and an
+// Accept-Language value. Blank lines and # comments are skipped.
+func LoadFingerprints(spec string) ([]Fingerprint, error) {
+	switch spec {
+	case "":
+		return nil, nil
+	case "builtin":
+		return BuiltinFingerprints, nil
+	}
+	f, err := os.Open(spec)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var fps []Fingerprint
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ua, lang, _ := strings.Cut(line, " | ")
+		fps = append(fps, Fingerprint{UserAgent: strings.TrimSpace(ua), AcceptLanguage: strings.TrimSpace(lang)})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(fps) == 0 {
+		return nil, fmt.Errorf("%s: no user agents", spec)
+	}
+	return fps, nil
+}
+
+// applyFingerprint sets the identity headers of userID's client. A user keeps
+// the same fingerprint for the whole run (open-loop requests each get a fresh
+// user); headers set explicitly in Config.Headers win.
+func (r *Runner) applyFingerprint(h http.Header, userID string) {
+	if len(r.fingerprints) == 0 {
+		return
+	}
+	f := fnv.New32a()
+	f.Write([]byte(userID))
+	fp := r.fingerprints[f.Sum32()%uint32(len(r.fingerprints))]
+	if h.Get("User-Agent") == "" {
+		h.Set("User-Agent", fp.UserAgent)
+	}
+	if fp.AcceptLanguage != "" && h.Get("Accept-Language") == "" {
+		h.Set("Accept-Language", fp.AcceptLanguage)
+	}
+}
@@ -0,0 +1,83 @@
+package runner
+
+import (
+	"fmt"
+	"math"
+
+	"steadyq/internal/monitor"
+)
+
+// Rough ceilings of the load generator, for HostAdvisories
+const (
+	// Descriptors kept for files, logs and the terminal
+	fdReserve = 64
+	// Linux keeps the port of a closed connection in TIME_WAIT this long
+	timeWaitSecs = 60
+	// Keep-alive HTTP/1.1 requests one CPU typically sustains; `steadyq
+	// selftest` measures the real figure
+	rpsPerCPU = 5000
+	// Default Transport.MaxConnsPerHost (Config.MaxConns)
+	defaultMaxConns = 2000
+)
+
+// PeakConnections estimates how many connections cfg may hold open at once:
+// users (times FanOut) in users mode; in open loop, the rate times the
+// request timeout a stalled target would pile up, capped by MaxConns. A
+// mirror doubles it.
+func (c Config) PeakConnections() int {
+	var n int
+	if c.Mode == "users" {
+		n = max(c.NumUsers, 1) * c.GetFanOut()
+	} else {
+		n = int(math.Ceil(float64(c.TargetRPS) * c.GetRequestTimeout().Seconds()))
+		maxConns := defaultMaxConns
+		if c.MaxConns > 0 {
+			maxConns = c.MaxConns
+		}
+		n = min(n, maxConns)
+	}
+	if c.Mirror != "" {
+		n *= 2
+	}
+	return n
+}
+
+// HostAdvisories warns, with the estimated safe ceiling, when the machine
+// cannot deliver cfg: a descriptor limit below its concurrency, too few
+// ephemeral ports for new connections, or a rate beyond its CPUs (container
+// quota included). Without a warning the generator would quietly send less
+// and the target would look better than it is.
+func HostAdvisories(cfg Config, h monitor.HostLimits) []string {
+	var out []string
+	need := cfg.PeakConnections()
+
+	if h.FDSoft > 0 && need > h.FDSoft-fdReserve {
+		hint := "raise it with ulimit -n"
+		if h.FDHard > 0 && h.FDHard <= h.FDSoft {
+			hint = fmt.Sprintf("the hard limit is %d too, raise it in limits.conf or the container runtime", h.FDHard)
+		}
+		out = append(out, fmt.Sprintf("Descriptor limit %d allows ~%d concurrent connections, this run may need %d: %s",
+			h.FDSoft, h.FDSoft-fdReserve, need, hint))
+	}
+
+	if h.Ports > 0 {
+		if need > h.Ports {
+			out = append(out, fmt.Sprintf("%d ephemeral ports allow ~%d concurrent connections per target address, this run may need %d: widen net.ipv4.ip_local_port_range",
+				h.Ports, h.Ports, need))
+		}
+		if churn := h.Ports / timeWaitSecs; h.TWReuse != 1 && cfg.Mode != "users" && cfg.TargetRPS > churn {
+			out = append(out, fmt.Sprintf("With net.ipv4.tcp_tw_reuse not set to 1, %d ephemeral ports sustain ~%d new connections/s: fine while keep-alive reuses connections, "+
+				"but if the target closes them %d RPS will fail with EADDRNOTAVAIL (sysctl net.ipv4.tcp_tw_reuse=1)", h.Ports, churn, cfg.TargetRPS))
+		}
+	}
+
+	cpus := float64(h.GOMAXPROCS)
+	if h.CPUQuota > 0 {
+		cpus = min(cpus, h.CPUQuota)
+	}
+	if safe := int(cpus * rpsPerCPU); cpus > 0 && cfg.Mode != "users" && cfg.TargetRPS > safe {
+		out = append(out, fmt.Sprintf("%d RPS is beyond the ~%d a generator with %.1f CPUs typically sustains; check the queue wait and generator health, or measure with `steadyq selftest`",
+			cfg.TargetRPS, safe, cpus))
+	}
+	return out
+}
@@ -0,0 +1,155 @@
+package runner
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// executeHTTP sends the rendered request through the pooled client, applying
+// the emulated network and bandwidth caps, and drains the response
+func (r *Runner) executeHTTP(ctx context.Context, userID, reqID string) Response {
+	rr := r.renderHTTP(userID, reqID)
+	if r.mirror != nil {
+		r.mirror.send(r, userID, rr)
+	}
+	res := Response{URL: rr.URL, Method: rr.Method, Query: "custom"}
+	if r.Cfg.RequestIDHeader != "" {
+		res.RequestID = rr.Header.Get(r.Cfg.RequestIDHeader)
+	}
+	if rr.BodyFile != "" {
+		res.Query = rr.BodyFile
+	}
+
+	var body io.Reader
+	if rr.HasBody {
+		body = strings.NewReader(rr.Body)
+		res.SentBytes = int64(len(rr.Body))
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, r.Cfg.GetRequestTimeout())
+	defer cancel()
+	uplink, downlink := r.linkLimiters(userID)
+	throttled := r.throttle(reqCtx, body, uplink)
+	req, _ := http.NewRequestWithContext(reqCtx, rr.Method, rr.URL, throttled)
+	if throttled != body {
+		// Throttled bodies are opaque readers, keep the Content-Length
+		req.ContentLength = res.SentBytes
+	}
+	req.Header = rr.Header
+	if rr.Host != "" {
+		req.Host = rr.Host
+	}
+	var cacheKey string
+	if r.validators != nil {
+		cacheKey = r.validatorKey(userID, rr.URL)
+		res.Conditional = r.validators.addConditional(req.Header, cacheKey)
+	}
+	res.HeadersHash = headersHash(req.Header)
+	res.SentHeaderBytes = requestHeadBytes(req)
+
+	// Record where the request actually went (after DNS, proxies, pooling)
+	// and whether it paid for a new connection: DNS, dial and TLS handshake.
+	// Dials may run on another goroutine, and may finish after this request
+	// took an idle connection instead, hence the atomics.
+	var sent time.Time
+	var tlsStart, handshake atomic.Int64
+	trace := &httptrace.ClientTrace{
+		TLSHandshakeStart: func() { tlsStart.Store(time.Now().UnixNano()) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			handshake.Store(time.Now().UnixNano() - tlsStart.Load())
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			res.RemoteAddr = info.Conn.RemoteAddr().String()
+			if info.Reused {
+				res.Reused = true
+			} else {
+				res.NewConn = true
+				res.ConnectTime = time.Since(sent)
+			}
+			if tc, ok := info.Conn.(*tls.Conn); ok {
+				cs := tc.ConnectionState()
+				res.TLS = &TLSInfo{Version: tls.VersionName(cs.Version), Cipher: tls.CipherSuiteName(cs.CipherSuite)}
+				if !info.Reused {
+					res.TLS.Resumed = cs.DidResume
+					res.TLS.Handshake = time.Duration(handshake.Load())
+				}
+			}
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	var resp *http.Response
+	var err error
+	if err = r.networkDelay(reqCtx); err == nil {
+		sent = time.Now()
+		resp, err = r.Client.Do(req)
+	}
+	if err != nil && reqCtx.Err() == context.DeadlineExceeded {
+		// Overall deadline, not one of the Transport timeouts
+		err = ErrRequestDeadline
+	}
+	res.Err = err
+	if err != nil {
+		return res
+	}
+
+	res.Status = resp.StatusCode
+	res.RecvHeaderBytes = int64(len(resp.Proto)+len(resp.Status)+3) + headBytes(resp.Header)
+	if r.validators != nil {
+		r.validators.store(cacheKey, resp)
+	}
+	if r.Cfg.HonorRetryAfter && (res.Status == http.StatusTooManyRequests || res.Status == http.StatusServiceUnavailable) {
+		res.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	}
+
+	// Count what was read: chunked and streamed bodies have no Content-Length
+	respReader := throttleResponse(reqCtx, resp.Body, downlink)
+	if resp.StatusCode >= 400 {
+		b, _ := io.ReadAll(io.LimitReader(respReader, maxResponseBody))
+		res.Body = string(b)
+		res.Bytes = int64(len(b))
+	}
+	n, _ := io.Copy(io.Discard, respReader)
+	res.Bytes += n
+	resp.Body.Close()
+	return res
+}
+
+// requestHeadBytes estimates the HTTP/1.1 request line and headers of req as
+// sent, including the Host, User-Agent, Content-Length and Accept-Encoding
+// lines the Transport adds. HTTP/2 compresses headers, so it sends less.
+func requestHeadBytes(req *http.Request) int64 {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	n := int64(len(req.Method)+len(req.URL.RequestURI())+len(" HTTP/1.1\r\n")+1) + int64(len("Host: \r\n")+len(host))
+	if req.Header.Get("User-Agent") == "" {
+		n += int64(len("User-Agent: Go-http-client/1.1\r\n"))
+	}
+	if req.ContentLength > 0 {
+		n += int64(len("Content-Length: \r\n") + len(strconv.FormatInt(req.ContentLength, 10)))
+	}
+	if req.Header.Get("Accept-Encoding") == "" && req.Method != http.MethodHead {
+		n += int64(len("Accept-Encoding: gzip\r\n"))
+	}
+	return n + headBytes(req.Header)
+}
+
+// headBytes is the size of h as header lines plus the blank line ending them
+func headBytes(h http.Header) int64 {
+	n := int64(len("\r\n"))
+	for k, vs := range h {
+		for _, v := range vs {
+			n += int64(len(k) + len(": \r\n") + len(v))
+		}
+	}
+	return n
+}
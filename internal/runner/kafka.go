@@ -0,0 +1,95 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// parseKafkaURL splits kafka://broker1:9092,broker2:9092/topic into brokers and topic
+func parseKafkaURL(raw string) ([]string, string, error) {
+	rest := raw
+	if idx := strings.Index(rest, "://"); idx != -1 {
+		rest = rest[idx+3:]
+	}
+	hosts, topic, _ := strings.Cut(rest, "/")
+	topic = strings.Trim(topic, "/")
+	if hosts == "" || topic == "" {
+		return nil, "", fmt.Errorf("kafka URL must look like kafka://broker:9092/topic")
+	}
+	return strings.Split(hosts, ","), topic, nil
+}
+
+// parseKafkaAcks maps "all"/"-1", "1"/"leader" and "0"/"none" to RequiredAcks
+func parseKafkaAcks(acks string) (kafka.RequiredAcks, error) {
+	switch strings.ToLower(strings.TrimSpace(acks)) {
+	case "", "all", "-1":
+		return kafka.RequireAll, nil
+	case "1", "leader":
+		return kafka.RequireOne, nil
+	case "0", "none":
+		return kafka.RequireNone, nil
+	}
+	return kafka.RequireAll, fmt.Errorf("invalid kafka acks %q (use all, 1 or 0)", acks)
+}
+
+// initKafka creates the producer. Messages from concurrent requests are still
+// batched by the writer, so the batch timeout is kept tiny to keep produce
+// latency honest.
+func (r *Runner) initKafka() error {
+	brokers, topic, err := parseKafkaURL(r.Cfg.URL)
+	if err != nil {
+		return err
+	}
+	acks, err := parseKafkaAcks(r.Cfg.KafkaAcks)
+	if err != nil {
+		return err
+	}
+
+	r.Kafka = &kafka.Writer{
+		Addr:                   kafka.TCP(brokers...),
+		Topic:                  topic,
+		Balancer:               &kafka.Hash{},
+		RequiredAcks:           acks,
+		BatchTimeout:           time.Millisecond,
+		WriteTimeout:           r.Cfg.GetRequestTimeout(),
+		ReadTimeout:            r.Cfg.GetRequestTimeout(),
+		AllowAutoTopicCreation: true,
+	}
+
+	if r.Cfg.KafkaKey != "" {
+		r.TmplKafkaKey, err = r.TmplEngine.Parse("kafka-key", r.Cfg.KafkaKey)
+		if err != nil {
+			return fmt.Errorf("error parsing kafka key template: %w", err)
+		}
+	}
+	return nil
+}
+
+// executeKafka produces one message built from the body (and key) templates.
+// Returns status (200 on success), bytes sent, failure body and error.
+func (r *Runner) executeKafka(ctx context.Context, userID, reqID string) (int, int64, string, error) {
+	if r.Kafka == nil {
+		return 0, 0, "", fmt.Errorf("kafka producer not initialized")
+	}
+
+	payload := r.Cfg.Body
+	if r.TmplBody != nil {
+		payload = r.applyTemplates(r.TmplBody, userID, reqID)
+	}
+	msg := kafka.Message{Value: []byte(payload)}
+	if r.TmplKafkaKey != nil {
+		msg.Key = []byte(r.applyTemplates(r.TmplKafkaKey, userID, reqID))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.Cfg.GetRequestTimeout())
+	defer cancel()
+
+	if err := r.Kafka.WriteMessages(ctx, msg); err != nil {
+		return 0, 0, err.Error(), err
+	}
+	return 200, int64(len(msg.Value) + len(msg.Key)), "", nil
+}
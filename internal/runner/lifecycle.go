@@ -0,0 +1,156 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// RunState is where a Runner is in its lifecycle
+type RunState int32
+
+const (
+	StateIdle     RunState = iota // Never run
+	StateRunning                  // Generating load
+	StateDraining                 // No new requests, waiting for in-flight ones
+	StateDone                     // Every request finished (reports may still be written)
+)
+
+func (s RunState) String() string {
+	switch s {
+	case StateRunning:
+		return "running"
+	case StateDraining:
+		return "draining"
+	case StateDone:
+		return "done"
+	}
+	return "idle"
+}
+
+// ErrAlreadyRunning is returned by Start while the previous run has not returned
+var ErrAlreadyRunning = errors.New("runner is already running")
+
+// RunStatus is a point-in-time view of a run
+type RunStatus struct {
+	State       RunState
+	RunID       string
+	StartedAt   time.Time
+	Elapsed     time.Duration // Zero unless running or draining
+	Inflight    int64
+	Requests    uint64
+	AbortReason string
+}
+
+// lifecycle tracks the state of the current run
+type lifecycle struct {
+	state    atomic.Int32
+	cancel   context.CancelFunc // Stops the run started by Start
+	finished chan struct{}      // Closed when that run returns
+}
+
+// State is the runner's current lifecycle state
+func (r *Runner) State() RunState {
+	return RunState(r.life.state.Load())
+}
+
+// setState moves to next, only from one of the given states when any are given
+func (r *Runner) setState(next RunState, from ...RunState) {
+	if len(from) == 0 {
+		r.life.state.Store(int32(next))
+		return
+	}
+	for _, f := range from {
+		if r.life.state.CompareAndSwap(int32(f), int32(next)) {
+			return
+		}
+	}
+}
+
+// Start launches a run of Cfg in the background. Cancelling ctx stops it like
+// Stop. Fails while the previous run is still going.
+func (r *Runner) Start(ctx context.Context) error {
+	r.mu.Lock()
+	if r.life.finished != nil {
+		select {
+		case <-r.life.finished:
+		default:
+			r.mu.Unlock()
+			return ErrAlreadyRunning
+		}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	finished := make(chan struct{})
+	r.life.cancel, r.life.finished = cancel, finished
+	r.mu.Unlock()
+
+	r.setState(StateRunning)
+	go func() {
+		defer close(finished)
+		defer cancel()
+		r.Run(ctx)
+	}()
+	return nil
+}
+
+// Stop ends load generation; in-flight requests finish (draining) and the run
+// completes as usual. Use Abort to cut them off too. No-op when not running.
+func (r *Runner) Stop() {
+	r.mu.Lock()
+	cancel, stop := r.life.cancel, r.stopRun
+	r.mu.Unlock()
+	r.setState(StateDraining, StateRunning)
+	for _, f := range []context.CancelFunc{cancel, stop} {
+		if f != nil {
+			f()
+		}
+	}
+}
+
+// Done is closed once the run launched by Start has returned, sinks included;
+// it is already closed when Start was never called
+func (r *Runner) Done() <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.life.finished == nil {
+		closed := make(chan struct{})
+		close(closed)
+		return closed
+	}
+	return r.life.finished
+}
+
+// Status reports the run's state and progress
+func (r *Runner) Status() RunStatus {
+	r.mu.Lock()
+	meta := r.Meta
+	r.mu.Unlock()
+	st := RunStatus{
+		State:       r.State(),
+		RunID:       meta.RunID,
+		StartedAt:   meta.StartedAt,
+		Inflight:    atomic.LoadInt64(&r.Inflight),
+		Requests:    atomic.LoadUint64(&r.Stats.Requests),
+		AbortReason: r.AbortReason(),
+	}
+	if (st.State == StateRunning || st.State == StateDraining) && !meta.StartedAt.IsZero() {
+		st.Elapsed = time.Since(meta.StartedAt)
+	}
+	return st
+}
+
+// watchDrain marks the run draining once load generation ends: ctx cancelled
+// (Stop, abort condition, caller) or the configured duration over
+func (r *Runner) watchDrain(ctx context.Context, generated <-chan struct{}) {
+	total := time.Duration(r.Cfg.RampUp+r.Cfg.SteadyDur+r.Cfg.RampDown) * time.Second
+	timer := time.NewTimer(total)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	case <-generated:
+		return
+	}
+	r.setState(StateDraining, StateRunning)
+}
@@ -0,0 +1,94 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// RunMetadata records where and with what a run was produced, so results can
+// be traced back to a code version and machine later
+type RunMetadata struct {
+	RunID     string            `json:"run_id"` // ULID, names the run's directory under Config.RunsDir
+	StartedAt time.Time         `json:"started_at"`
+	Hostname  string            `json:"hostname"`
+	OS        string            `json:"os"`
+	Arch      string            `json:"arch"`
+	NumCPU    int               `json:"num_cpu"`
+	GoVersion string            `json:"go_version"`
+	Version   string            `json:"steadyq_version"`      // Module version or VCS revision of this binary
+	GitSHA    string            `json:"git_sha,omitempty"`    // HEAD of the working directory's repo, "-dirty" if modified
+	GitBranch string            `json:"git_branch,omitempty"` // Branch of the working directory's repo
+	Mode      string            `json:"mode"`                 // rps, users or script
+	Pacing    string            `json:"pacing,omitempty"`     // Open-loop pacing strategy
+	Seed      int64             `json:"seed"`                 // Random seed, rerun with --seed to repeat
+	Metadata  map[string]string `json:"user,omitempty"`       // Config.Metadata
+}
+
+// gitTimeout bounds each git call so a slow filesystem never delays a run
+const gitTimeout = 2 * time.Second
+
+// CollectMetadata gathers the generator's host, build and repo details for cfg
+func CollectMetadata(cfg Config) RunMetadata {
+	now := time.Now()
+	meta := RunMetadata{
+		RunID:     NewRunID(now),
+		StartedAt: now,
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		NumCPU:    runtime.NumCPU(),
+		GoVersion: runtime.Version(),
+		Version:   buildVersion(),
+		Mode:      cfg.Mode,
+		Metadata:  cfg.Metadata,
+	}
+	if meta.Mode == "" {
+		meta.Mode = "rps"
+	}
+	if meta.Mode == "rps" {
+		meta.Pacing = cfg.GetPacing()
+	}
+	meta.Hostname, _ = os.Hostname()
+
+	if sha := git("rev-parse", "HEAD"); sha != "" {
+		if git("status", "--porcelain", "--untracked-files=no") != "" {
+			sha += "-dirty"
+		}
+		meta.GitSHA = sha
+		meta.GitBranch = git("rev-parse", "--abbrev-ref", "HEAD")
+	}
+	return meta
+}
+
+// buildVersion returns the module version, or the VCS revision for source builds
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	version := info.Main.Version
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" && (version == "" || version == "(devel)") {
+			version = s.Value
+		}
+	}
+	if version == "" {
+		return "(devel)"
+	}
+	return version
+}
+
+// git runs a git command in the working directory, "" on any failure
+func git(args ...string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,152 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// mirror sends a copy of every HTTP request to a second base URL
+// (Config.Mirror) at the same time as the original, for canary comparisons.
+// Mirrored results stay out of the run's stats, breaker, abort conditions
+// and sinks; read them with MirrorResults. Like the run's own, only the
+// latest Config.GetMaxResults are kept.
+type mirror struct {
+	base   string
+	client *http.Client
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	results resultRing
+	dropped uint64
+}
+
+// ParseMirror validates a Config.Mirror base URL
+func ParseMirror(base string) (*url.URL, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%q is not an http(s) base URL", base)
+	}
+	return u, nil
+}
+
+func newMirror(cfg Config) (*mirror, error) {
+	if _, err := ParseMirror(cfg.Mirror); err != nil {
+		return nil, err
+	}
+	// Routing overrides target the primary, not the mirror
+	cfg.ConnectTo, cfg.ServerName = "", ""
+	return &mirror{base: cfg.Mirror, client: newHTTPClient(cfg, nil), results: newResultRing(cfg.GetMaxResults())}, nil
+}
+
+// RebaseURL moves rawURL to the scheme and host of base, under its path:
+// https://api/search?q=1 on http://canary:8080/v2 is
+// http://canary:8080/v2/search?q=1. Templates in rawURL are kept as they are.
+func RebaseURL(rawURL, base string) string {
+	rest := rawURL
+	if i := strings.Index(rawURL, "://"); i != -1 {
+		rest = rawURL[i+3:]
+		if j := strings.IndexAny(rest, "/?#"); j != -1 {
+			rest = rest[j:]
+		} else {
+			rest = ""
+		}
+	}
+	if rest != "" && rest[0] != '/' {
+		rest = "/" + rest
+	}
+	return strings.TrimSuffix(base, "/") + rest
+}
+
+// send fires the mirrored copy of rr in the background. It runs under the
+// run's abort context with its own request timeout, not the original's
+// context: an iteration deadline (Config.IterationTimeout) ends with the
+// original request and would cancel a slower mirror.
+func (m *mirror) send(r *Runner, userID string, rr renderedRequest) {
+	rr.Header = rr.Header.Clone() // The original's may still gain validators
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		res := ExperimentResult{TimeStamp: time.Now(), UserID: userID, Method: rr.Method, Label: r.Cfg.GetLabel(), Tags: r.Cfg.Tags, Attempt: 1}
+		res.URL = RebaseURL(rr.URL, m.base)
+		m.do(r, r.abortCtx, rr, &res)
+		res.Latency = time.Since(res.TimeStamp)
+		res.ServiceTime = res.Latency
+		r.redactor.result(&res)
+		m.mu.Lock()
+		if m.results.push(res) {
+			m.dropped++
+		}
+		m.mu.Unlock()
+	}()
+}
+
+func (m *mirror) do(r *Runner, ctx context.Context, rr renderedRequest, res *ExperimentResult) {
+	ctx, cancel := context.WithTimeout(ctx, r.Cfg.GetRequestTimeout())
+	defer cancel()
+	var body io.Reader
+	if rr.HasBody {
+		body = strings.NewReader(rr.Body)
+		res.SentBytes = int64(len(rr.Body))
+	}
+	req, err := http.NewRequestWithContext(ctx, rr.Method, res.URL, body)
+	if err != nil {
+		res.Err = err
+		return
+	}
+	req.Header = rr.Header
+	if rr.Host != "" {
+		req.Host = rr.Host
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = ErrRequestDeadline
+		}
+		res.Err = err
+		return
+	}
+	n, _ := io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	res.Status, res.Bytes = resp.StatusCode, n
+	res.Success = r.successCodes.Contains(resp.StatusCode)
+}
+
+// wait blocks until every mirrored request has finished
+func (m *mirror) wait() {
+	m.wg.Wait()
+}
+
+// MirrorResults copies the mirrored requests' results, nil without a mirror
+func (r *Runner) MirrorResults() []ExperimentResult {
+	r.mu.Lock()
+	m := r.mirror
+	r.mu.Unlock()
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.results.snapshot()
+}
+
+// MirrorDropped is the number of mirrored results the full buffer dropped
+func (r *Runner) MirrorDropped() uint64 {
+	r.mu.Lock()
+	m := r.mirror
+	r.mu.Unlock()
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.dropped
+}
@@ -0,0 +1,180 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NetworkProfile emulates a client's link: each request waits Latency ± Jitter
+// before it is sent, and its body upload and response read are throttled to the
+// link's rates. Every virtual user (every request in rps mode) gets its own link.
+type NetworkProfile struct {
+	Latency  time.Duration
+	Jitter   time.Duration
+	Downlink int64 // Bytes per second, 0 = unlimited
+	Uplink   int64 // Bytes per second, 0 = unlimited
+}
+
+// NetworkProfiles are the built-in client links for Config.NetworkProfile
+var NetworkProfiles = map[string]NetworkProfile{
+	"2g":    {Latency: 650 * time.Millisecond, Jitter: 150 * time.Millisecond, Downlink: 250_000 / 8, Uplink: 50_000 / 8},
+	"3g":    {Latency: 300 * time.Millisecond, Jitter: 100 * time.Millisecond, Downlink: 1_600_000 / 8, Uplink: 768_000 / 8},
+	"4g":    {Latency: 70 * time.Millisecond, Jitter: 25 * time.Millisecond, Downlink: 12_000_000 / 8, Uplink: 4_000_000 / 8},
+	"adsl":  {Latency: 40 * time.Millisecond, Jitter: 10 * time.Millisecond, Downlink: 8_000_000 / 8, Uplink: 1_000_000 / 8},
+	"cable": {Latency: 20 * time.Millisecond, Jitter: 5 * time.Millisecond, Downlink: 100_000_000 / 8, Uplink: 10_000_000 / 8},
+}
+
+// NetworkProfileNames lists the built-in profiles, sorted
+func NetworkProfileNames() []string {
+	names := make([]string, 0, len(NetworkProfiles))
+	for name := range NetworkProfiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ParseNetworkProfile resolves a built-in profile name, or a custom link like
+// "latency=200ms,jitter=50ms,down=2Mbit,up=512kbit". A name may be followed by
+// overrides ("3g,latency=500ms"). "" means no emulation and returns nil.
+func ParseNetworkProfile(s string) (*NetworkProfile, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	var p NetworkProfile
+	for i, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			base, known := NetworkProfiles[strings.ToLower(part)]
+			if i > 0 || !known {
+				return nil, fmt.Errorf("unknown network profile %q (use %s, or latency=,jitter=,down=,up=)", part, strings.Join(NetworkProfileNames(), ", "))
+			}
+			p = base
+			continue
+		}
+		var err error
+		switch strings.TrimSpace(key) {
+		case "latency":
+			p.Latency, err = time.ParseDuration(val)
+		case "jitter":
+			p.Jitter, err = time.ParseDuration(val)
+		case "down":
+			p.Downlink, err = ParseBandwidth(val)
+		case "up":
+			p.Uplink, err = ParseBandwidth(val)
+		default:
+			return nil, fmt.Errorf("unknown network profile setting %q (use latency, jitter, down, up)", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("network profile %s: %v", key, err)
+		}
+		if p.Latency < 0 || p.Jitter < 0 {
+			return nil, fmt.Errorf("network profile %s: must not be negative", key)
+		}
+	}
+	return &p, nil
+}
+
+// String describes the link for run headers
+func (p NetworkProfile) String() string {
+	rate := func(bps int64) string {
+		if bps == 0 {
+			return "unlimited"
+		}
+		return fmt.Sprintf("%.0fkbit/s", float64(bps)*8/1000)
+	}
+	return fmt.Sprintf("%v±%v, down %s, up %s", p.Latency, p.Jitter, rate(p.Downlink), rate(p.Uplink))
+}
+
+// networkDelay waits out the emulated link latency before a request is sent
+func (r *Runner) networkDelay(ctx context.Context) error {
+	p := r.network
+	if p == nil || p.Latency+p.Jitter == 0 {
+		return nil
+	}
+	d := p.Latency
+	if p.Jitter > 0 {
+		d += time.Duration(r.rand.Int63n(int64(2*p.Jitter))) - p.Jitter
+	}
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// link is the uplink and downlink buckets of one emulated client link, nil
+// where the profile sets no rate
+type link struct {
+	up, down *bandwidthLimiter
+}
+
+func newLink(p *NetworkProfile) link {
+	var l link
+	if p.Uplink > 0 {
+		l.up = newLinkLimiter(p.Uplink)
+	}
+	if p.Downlink > 0 {
+		l.down = newLinkLimiter(p.Downlink)
+	}
+	return l
+}
+
+// newLinkLimiter is a bucket with neither tokens nor burst: every byte waits
+// for the link rate, as on a real slow link, and idle time buys no credit
+func newLinkLimiter(bytesPerSec int64) *bandwidthLimiter {
+	return &bandwidthLimiter{rate: float64(bytesPerSec), last: time.Now()}
+}
+
+// linkCache keeps one link per virtual user in users mode, shared by the
+// user's requests (fan-out included)
+type linkCache struct {
+	mu sync.Mutex
+	m  map[string]link
+}
+
+func newLinkCache() *linkCache {
+	return &linkCache{m: make(map[string]link)}
+}
+
+// linkLimiters returns the uplink and downlink buckets of userID's emulated
+// link: the user's own in users mode, a new one per request in rps mode
+func (r *Runner) linkLimiters(userID string) (up, down *bandwidthLimiter) {
+	if r.network == nil {
+		return nil, nil
+	}
+	if r.links == nil {
+		l := newLink(r.network)
+		return l.up, l.down
+	}
+	r.links.mu.Lock()
+	defer r.links.mu.Unlock()
+	l, ok := r.links.m[userID]
+	if !ok {
+		l = newLink(r.network)
+		r.links.m[userID] = l
+	}
+	return l.up, l.down
+}
+
+// throttleResponse wraps a response body so it is read no faster than the
+// emulated downlink. Returns body unchanged when down is nil.
+func throttleResponse(ctx context.Context, body io.Reader, down *bandwidthLimiter) io.Reader {
+	if down == nil {
+		return body
+	}
+	return &throttledReader{ctx: ctx, r: body, limiters: []*bandwidthLimiter{down}}
+}
@@ -0,0 +1,162 @@
+package runner
+
+import (
+	"runtime"
+	"time"
+)
+
+// Pacing strategies for open-loop (rps) mode.
+//
+//   - constant: evenly spaced requests. Catches up on up to 1s of backlog, then skips.
+//   - poisson: exponentially distributed gaps with the same mean rate, modelling
+//     independent arrivals. Bursts and lulls exercise queues more realistically,
+//     at the cost of noisier per-second throughput.
+//   - batched: at the start of every PacingTick, send all requests due in that tick
+//     at once. Cheapest on the generator, but the target sees synchronized bursts.
+//   - token-bucket: tokens refill at the target rate up to PacingBurst; after a
+//     stall (GC, saturation) at most PacingBurst requests are sent as catch-up,
+//     the rest of the backlog is skipped. Keeps bursts bounded on a slow generator.
+const (
+	PacingConstant    = "constant"
+	PacingPoisson     = "poisson"
+	PacingBatched     = "batched"
+	PacingTokenBucket = "token-bucket"
+)
+
+// PacingStrategies lists the valid Config.Pacing values
+var PacingStrategies = []string{PacingConstant, PacingPoisson, PacingBatched, PacingTokenBucket}
+
+// Pacing thresholds for the open-loop scheduler.
+//
+// time.Sleep rounds up to the OS timer granularity (~50µs on Linux, ~1ms or
+// worse elsewhere), so sleeping once per request caps the achievable rate and
+// makes it jittery. Above batchPeriod the scheduler wakes once per pacerTick
+// and sends every request that came due (K = tick / period per wake-up). Below
+// it, a coarse sleep is followed by a short spin so each request leaves close
+// to its scheduled time.
+const (
+	pacerTick   = 250 * time.Microsecond // Wake-up interval when batching
+	batchPeriod = 50 * time.Microsecond
+	spinWindow  = 100 * time.Microsecond // Final stretch spent yielding instead of sleeping
+	spinPeriod  = 5 * time.Millisecond   // Only spin when requests are this close together
+)
+
+// maxBacklog is how far the constant/poisson schedulers may fall behind before skipping
+const maxBacklog = 1 * time.Second
+
+// GetPacing returns the pacing strategy, constant by default
+func (c Config) GetPacing() string {
+	if c.Pacing == "" {
+		return PacingConstant
+	}
+	return c.Pacing
+}
+
+// GetPacingBurst returns the token bucket size (default 10)
+func (c Config) GetPacingBurst() int {
+	if c.PacingBurst > 0 {
+		return c.PacingBurst
+	}
+	return 10
+}
+
+// GetPacingTick returns the batched strategy interval (default 100ms)
+func (c Config) GetPacingTick() time.Duration {
+	if c.PacingTick > 0 {
+		return c.PacingTick
+	}
+	return 100 * time.Millisecond
+}
+
+// pacer decides when each open-loop request is scheduled
+type pacer struct {
+	strategy string
+	burst    int
+	tick     time.Duration
+	start    time.Time
+	rand     *lockedRand
+}
+
+func newPacer(cfg Config, start time.Time, rng *lockedRand) *pacer {
+	return &pacer{
+		strategy: cfg.GetPacing(),
+		burst:    cfg.GetPacingBurst(),
+		tick:     cfg.GetPacingTick(),
+		start:    start,
+		rand:     rng,
+	}
+}
+
+// gap returns the time between this request and the next one
+func (p *pacer) gap(period time.Duration) time.Duration {
+	if p.strategy == PacingPoisson {
+		return time.Duration(p.rand.ExpFloat64() * float64(period))
+	}
+	return period
+}
+
+// maxBehind is the backlog the scheduler may send as a catch-up burst
+func (p *pacer) maxBehind(period time.Duration) time.Duration {
+	if p.strategy == PacingTokenBucket {
+		return time.Duration(p.burst) * period
+	}
+	return maxBacklog
+}
+
+// keep is the backlog retained when skipping ahead: a full bucket for
+// token-bucket, nothing for the others
+func (p *pacer) keep(period time.Duration) time.Duration {
+	if p.strategy == PacingTokenBucket {
+		return p.maxBehind(period)
+	}
+	return 0
+}
+
+// horizon is the latest scheduled time sent now: the end of the current tick
+// when batching, otherwise now
+func (p *pacer) horizon(now time.Time) time.Time {
+	if p.strategy == PacingBatched {
+		return p.tickStart(now).Add(p.tick - 1)
+	}
+	return now
+}
+
+// tickStart returns the start of the batched tick containing t
+func (p *pacer) tickStart(t time.Time) time.Time {
+	return p.start.Add(t.Sub(p.start) / p.tick * p.tick)
+}
+
+// wait blocks until the next request is due
+func (p *pacer) wait(next time.Time, period time.Duration) {
+	if p.strategy == PacingBatched {
+		// Wake at the start of the tick holding the next request, then send the whole tick
+		time.Sleep(time.Until(p.tickStart(next)))
+		return
+	}
+	pace(next, period)
+}
+
+// pace blocks until the next request is due. period is the current interval
+// between requests; at high rates it returns after one tick with several due.
+func pace(next time.Time, period time.Duration) {
+	wait := time.Until(next)
+	if wait <= 0 {
+		return
+	}
+	switch {
+	case period <= batchPeriod:
+		// Batched: one wake-up per tick, the caller sends everything due
+		time.Sleep(pacerTick)
+	case period > spinPeriod:
+		// Low rates: timer granularity is negligible next to the period
+		time.Sleep(wait)
+	default:
+		// Hybrid: coarse sleep, then yield until the deadline
+		if wait > spinWindow {
+			time.Sleep(wait - spinWindow)
+		}
+		for time.Now().Before(next) {
+			runtime.Gosched()
+		}
+	}
+}
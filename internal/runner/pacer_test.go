@@ -0,0 +1,172 @@
+package runner
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingExecutor records when each request actually reaches the executor
+type countingExecutor struct {
+	sent []int64 // Unix nanoseconds, filled up to n
+	n    atomic.Int64
+}
+
+func newCountingExecutor(capacity int) *countingExecutor {
+	return &countingExecutor{sent: make([]int64, capacity)}
+}
+
+func (c *countingExecutor) Execute(ctx context.Context, userID, reqID string) Response {
+	now := time.Now().UnixNano()
+	if i := c.n.Add(1) - 1; i < int64(len(c.sent)) {
+		c.sent[i] = now
+	}
+	return Response{Status: 200}
+}
+
+// times returns the recorded send times in order
+func (c *countingExecutor) times() []int64 {
+	out := slices.Clone(c.sent[:min(c.n.Load(), int64(len(c.sent)))])
+	slices.Sort(out)
+	return out
+}
+
+// runPaced drives runRPS for secs seconds at rps against a counting executor
+func runPaced(t *testing.T, rps, secs int) []int64 {
+	t.Helper()
+	r := NewRunner(Config{Mode: "rps", TargetRPS: rps, SteadyDur: secs, MaxResults: 1}, nil)
+	cleanup := r.setup()
+	defer cleanup()
+	exec := newCountingExecutor(2 * rps * secs)
+	r.executor = exec
+	r.runRPS(context.Background())
+	return exec.times()
+}
+
+// windowCounts buckets send times into windows of w, dropping the first and
+// last (partial) ones
+func windowCounts(sent []int64, w time.Duration) []int {
+	if len(sent) == 0 {
+		return nil
+	}
+	start := sent[0]
+	counts := make([]int, (sent[len(sent)-1]-start)/int64(w)+1)
+	for _, ts := range sent {
+		counts[(ts-start)/int64(w)]++
+	}
+	if len(counts) <= 2 {
+		return nil
+	}
+	return counts[1 : len(counts)-1]
+}
+
+// TestRunRPSAccuracy checks the achieved rate and how evenly requests are
+// spread. Below 20k rps each request leaves on its own, so inter-arrival gaps
+// are compared with the period; from there on requests leave in bursts of one
+// pacerTick, so only 10ms windows are compared.
+func TestRunRPSAccuracy(t *testing.T) {
+	if testing.Short() {
+		t.Skip("timing test")
+	}
+	const window = 10 * time.Millisecond
+	cases := []struct {
+		rps int
+		// Requests sent in the 1s run, relative to the target
+		rateTolerance float64
+		// Median distance of an inter-arrival gap from the period, relative
+		// to the period (unbatched rates only)
+		jitterTolerance float64
+		// Windows whose count is off the expected one by more than
+		// windowTolerance (relative) may make up at most windowOutliers
+		windowTolerance float64
+		windowOutliers  float64
+	}{
+		{rps: 1_000, rateTolerance: 0.02, jitterTolerance: 0.2, windowTolerance: 0.3, windowOutliers: 0.05},
+		{rps: 10_000, rateTolerance: 0.03, jitterTolerance: 0.2, windowTolerance: 0.2, windowOutliers: 0.05},
+		{rps: 50_000, rateTolerance: 0.05, windowTolerance: 0.2, windowOutliers: 0.1},
+	}
+	for _, tc := range cases {
+		// Batched rates need the pacer and the senders on separate cores, at
+		// full speed
+		period := time.Second / time.Duration(tc.rps)
+		if period <= batchPeriod && (raceEnabled || runtime.NumCPU() < 2) {
+			t.Logf("%d rps: skipped under the race detector or on one CPU", tc.rps)
+			continue
+		}
+		sent := runPaced(t, tc.rps, 1)
+		got := len(sent)
+		if diff := math.Abs(float64(got-tc.rps)) / float64(tc.rps); diff > tc.rateTolerance {
+			t.Errorf("%d rps: sent %d requests in 1s, want within %.0f%%", tc.rps, got, tc.rateTolerance*100)
+		}
+
+		if period > batchPeriod {
+			if jitter := medianJitter(sent, period); float64(jitter) > tc.jitterTolerance*float64(period) {
+				t.Errorf("%d rps: median inter-arrival jitter %v, want at most %.0f%% of the %v period",
+					tc.rps, jitter, tc.jitterTolerance*100, period)
+			}
+		}
+
+		want := float64(tc.rps) * window.Seconds()
+		counts := windowCounts(sent, window)
+		outliers := 0
+		for _, n := range counts {
+			if math.Abs(float64(n)-want)/want > tc.windowTolerance {
+				outliers++
+			}
+		}
+		if float64(outliers) > tc.windowOutliers*float64(len(counts)) {
+			t.Errorf("%d rps: %d of %d %v windows off the expected %.0f requests by more than %.0f%%",
+				tc.rps, outliers, len(counts), window, want, tc.windowTolerance*100)
+		}
+	}
+}
+
+// TestPaceBatching checks the switch between per-request and batched pacing:
+// from 20k rps (a period of batchPeriod) pace wakes once per pacerTick however
+// far off the next request is, below it it waits for the request
+func TestPaceBatching(t *testing.T) {
+	if p := time.Second / 20_000; p != batchPeriod {
+		t.Fatalf("20k rps period %v, want batchPeriod %v", p, batchPeriod)
+	}
+
+	elapsed := func(next time.Duration, period time.Duration) time.Duration {
+		start := time.Now()
+		pace(start.Add(next), period)
+		return time.Since(start)
+	}
+
+	// Batched: one tick, not the 20ms to the next request
+	if d := elapsed(20*time.Millisecond, batchPeriod); d < pacerTick || d >= 10*time.Millisecond {
+		t.Errorf("period %v: pace took %v, want one %v tick", batchPeriod, d, pacerTick)
+	}
+	// Just below 20k rps: spin up to the request
+	if d := elapsed(2*time.Millisecond, batchPeriod+time.Microsecond); d < 2*time.Millisecond {
+		t.Errorf("period %v: pace returned after %v, before the request was due", batchPeriod+time.Microsecond, d)
+	}
+	// Low rate: a plain sleep to the request
+	if d := elapsed(10*time.Millisecond, 2*spinPeriod); d < 10*time.Millisecond {
+		t.Errorf("period %v: pace returned after %v, before the request was due", 2*spinPeriod, d)
+	}
+	// Already due: no wait at all
+	if d := elapsed(-time.Millisecond, batchPeriod); d >= pacerTick {
+		t.Errorf("overdue request: pace took %v, want no wait", d)
+	}
+}
+
+// medianJitter is the median distance of the inter-arrival gaps from period
+func medianJitter(sent []int64, period time.Duration) time.Duration {
+	if len(sent) < 2 {
+		return 0
+	}
+	dev := make([]int64, 0, len(sent)-1)
+	for i := 1; i < len(sent); i++ {
+		d := sent[i] - sent[i-1] - int64(period)
+		dev = append(dev, max(d, -d))
+	}
+	slices.Sort(dev)
+	return time.Duration(dev[len(dev)/2])
+}
@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"fmt"
+	"time"
+)
+
+// WantsPreflight reports whether a preflight probe is configured
+func (c Config) WantsPreflight() bool {
+	return c.Preflight || c.PreflightURL != ""
+}
+
+// Preflight sends one request before a run so a dead target fails fast instead
+// of burning a full run. With cfg.PreflightURL set, a plain GET to that URL
+// (e.g. a health endpoint) is the probe instead of the configured request.
+func Preflight(cfg Config) (ExperimentResult, error) {
+	probe := cfg
+	if cfg.PreflightURL != "" {
+		probe = Config{
+			URL:                   cfg.PreflightURL,
+			Method:                "GET",
+			Headers:               cfg.Headers,
+			HeaderPresets:         cfg.HeaderPresets,
+			ConnectTimeout:        cfg.ConnectTimeout,
+			TLSHandshakeTimeout:   cfg.TLSHandshakeTimeout,
+			ResponseHeaderTimeout: cfg.ResponseHeaderTimeout,
+			RequestTimeout:        cfg.RequestTimeout,
+			ConnectTo:             cfg.ConnectTo,
+			ServerName:            cfg.ServerName,
+			CAFile:                cfg.CAFile,
+			Insecure:              cfg.Insecure,
+		}
+	}
+	// The probe must reach the target, not be shed client-side
+	probe.BreakerErrorRate = 0
+	probe.HonorRetryAfter = false
+
+	r := NewRunner(probe, nil)
+	cleanup := r.setup()
+	defer cleanup()
+
+	res := r.executeRequest(time.Now(), "preflight")
+	if res.Success {
+		return res, nil
+	}
+
+	target := probe.URL
+	if probe.Command != "" {
+		target = "command"
+	}
+	if res.Err != nil {
+		return res, fmt.Errorf("preflight to %s failed: %s", target, cleanError(res.Err))
+	}
+	return res, fmt.Errorf("preflight to %s returned status %d", target, res.Status)
+}
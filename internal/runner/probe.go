@@ -0,0 +1,147 @@
+package runner
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"time"
+)
+
+// ProbeTiming breaks one HTTP request down by phase. Phases that did not
+// happen (no DNS for an IP, no TLS for http://) are zero.
+type ProbeTiming struct {
+	DNS      time.Duration
+	Connect  time.Duration
+	TLS      time.Duration
+	Wait     time.Duration // Request written to first response byte (server time)
+	Download time.Duration // First byte to end of body
+	Total    time.Duration
+}
+
+// ProbeResult is a single request with everything about its response
+type ProbeResult struct {
+	Request DryRequest
+	Result  ExperimentResult // Status, error and service time, as a run would record them
+
+	// HTTP only
+	Proto      string
+	Header     http.Header
+	Body       []byte
+	RemoteAddr string
+	TLSVersion string
+	Timing     ProbeTiming
+}
+
+// Probe sends exactly one configured request and keeps the full response.
+// Non-HTTP protocols go through the regular executor (status, body, time).
+func Probe(cfg Config) ProbeResult {
+	cfg.BreakerErrorRate = 0
+	cfg.HonorRetryAfter = false
+
+	r := NewRunner(cfg, nil)
+	cleanup := r.setup()
+	defer cleanup()
+
+	userID, reqID := "probe", newRequestID()
+	if cfg.Command != "" || cfg.GetProtocol() != "http" {
+		var p ProbeResult
+		p.Request = DryRequest{Protocol: cfg.GetProtocol(), UserID: userID, URL: cfg.URL, Command: cfg.Command}
+		p.Result = r.executeRequest(time.Now(), userID)
+		p.Body = []byte(p.Result.ResponseBody)
+		return p
+	}
+	p := r.probeHTTP(userID, reqID)
+	r.redactor.result(&p.Result)
+	p.Request.URL, p.Request.Header = r.redactor.String(p.Request.URL), r.redactor.Header(p.Request.Header)
+	p.Body = []byte(r.redactor.String(string(p.Body)))
+	return p
+}
+
+func (r *Runner) probeHTTP(userID, reqID string) ProbeResult {
+	rr := r.renderHTTP(userID, reqID)
+	p := ProbeResult{Request: DryRequest{
+		Protocol: "http", UserID: userID, Method: rr.Method, URL: rr.URL, Host: rr.Host,
+		Header: rr.Header, Body: rr.Body, BodyFile: rr.BodyFile,
+	}}
+	p.Result = ExperimentResult{TimeStamp: time.Now(), UserID: userID, URL: rr.URL, Method: rr.Method}
+
+	var body io.Reader
+	if rr.HasBody {
+		body = strings.NewReader(rr.Body)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), r.Cfg.GetRequestTimeout())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, rr.Method, rr.URL, body)
+	if err != nil {
+		p.Result.Err = err
+		return p
+	}
+	req.Header = rr.Header
+	if rr.Host != "" {
+		req.Host = rr.Host
+	}
+
+	var dnsStart, connStart, tlsStart, wrote, firstByte time.Time
+	t := &p.Timing
+	trace := &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone:           func(httptrace.DNSDoneInfo) { t.DNS = time.Since(dnsStart) },
+		ConnectStart:      func(string, string) { connStart = time.Now() },
+		ConnectDone:       func(string, string, error) { t.Connect = time.Since(connStart) },
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone: func(cs tls.ConnectionState, _ error) {
+			t.TLS = time.Since(tlsStart)
+			p.TLSVersion = tls.VersionName(cs.Version)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			p.RemoteAddr = info.Conn.RemoteAddr().String()
+		},
+		WroteRequest:         func(httptrace.WroteRequestInfo) { wrote = time.Now() },
+		GotFirstResponseByte: func() { firstByte = time.Now() },
+	}
+	req = req.WithContext(httptrace.WithClientTrace(ctx, trace))
+
+	start := time.Now()
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		t.Total = time.Since(start)
+		p.Result.Err = err
+		p.Result.ServiceTime = t.Total
+		return p
+	}
+	p.Body, err = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	end := time.Now()
+
+	t.Total = end.Sub(start)
+	if !wrote.IsZero() && !firstByte.IsZero() {
+		t.Wait = firstByte.Sub(wrote)
+		t.Download = end.Sub(firstByte)
+	}
+	p.Proto = resp.Proto
+	p.Header = resp.Header
+	p.Result.Status = resp.StatusCode
+	p.Result.Bytes = int64(len(p.Body))
+	p.Result.ServiceTime = t.Total
+	p.Result.RemoteAddr = p.RemoteAddr
+	p.Result.Err = err
+	if err == nil {
+		p.Result.Success = r.successCodes.Contains(resp.StatusCode)
+	}
+	return p
+}
+
+// String summarizes the probe outcome on one line
+func (p ProbeResult) String() string {
+	if verify := tlsVerifyError(p.Result.Err); verify != "" {
+		return verify
+	}
+	if p.Result.Err != nil {
+		return "error: " + cleanError(p.Result.Err)
+	}
+	return fmt.Sprintf("status %d in %s", p.Result.Status, p.Result.ServiceTime.Round(time.Microsecond))
+}
@@ -0,0 +1,7 @@
+//go:build race
+
+package runner
+
+// raceEnabled reports whether the tests run under the race detector, which
+// slows the hot path too much for the highest rates
+const raceEnabled = true
@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// lockedRand is the run's single random source, shared by the template
+// functions, the Redis command mix, Poisson pacing and generated IDs. Seeding
+// it (Config.Seed) makes the sequence of random values reproducible; with
+// many concurrent workers the order they draw in still varies.
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func newLockedRand(seed int64) *lockedRand {
+	return &lockedRand{r: rand.New(rand.NewSource(seed))}
+}
+
+// randomSeed picks a seed for runs without Config.Seed, reported so they can be repeated
+func randomSeed() int64 {
+	return time.Now().UnixNano()
+}
+
+func (l *lockedRand) Intn(n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Intn(n)
+}
+
+func (l *lockedRand) Int63n(n int64) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Int63n(n)
+}
+
+func (l *lockedRand) ExpFloat64() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.ExpFloat64()
+}
+
+// Read fills p with random bytes (io.Reader, for UUID generation)
+func (l *lockedRand) Read(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Read(p)
+}
+
+// UUID returns a version 4 UUID drawn from this source
+func (l *lockedRand) UUID() string {
+	id, err := uuid.NewRandomFromReader(l)
+	if err != nil {
+		return uuid.New().String()
+	}
+	return id.String()
+}
+
+// newRequestID returns the ID of one request ({{requestID}}, RequestIDHeader).
+// It comes from crypto/rand, not the seeded source: runs repeated with the same
+// seed must still send IDs that are unique in the server's logs.
+func newRequestID() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,240 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRedisCommand is used when no command mix is configured
+const DefaultRedisCommand = "GET steadyq:{{randomInt 1 1000}}"
+
+// redisCommand is one entry of the weighted command mix, one template per
+// argument so a value that renders to spaces stays a single argument
+type redisCommand struct {
+	Weight int
+	Args   []*template.Template
+}
+
+// parseRedisCommand splits "[weight:]COMMAND args..." into weight and command text.
+// Example: "80:GET user:{{randomInt 1 100}}"
+func parseRedisCommand(line string) (int, string) {
+	line = strings.TrimSpace(line)
+	if idx := strings.Index(line, ":"); idx != -1 {
+		if w, err := strconv.Atoi(strings.TrimSpace(line[:idx])); err == nil {
+			return w, strings.TrimSpace(line[idx+1:])
+		}
+	}
+	return 1, line
+}
+
+// splitRedisArgs splits a command into arguments the way redis-cli does:
+// whitespace separates them, "double quotes" take \n \r \t \b \a and \xHH
+// escapes (any other escaped character is itself), 'single quotes' only \'.
+// A closing quote must end the argument. Template actions ({{...}}) are kept
+// whole, spaces and quotes included.
+func splitRedisArgs(line string) ([]string, error) {
+	var args []string
+	i := 0
+	for {
+		for i < len(line) && isRedisSpace(line[i]) {
+			i++
+		}
+		if i == len(line) {
+			return args, nil
+		}
+
+		var arg strings.Builder
+		var quote byte // Open quote, 0 outside quotes
+	scan:
+		for {
+			if i == len(line) {
+				if quote != 0 {
+					return nil, fmt.Errorf("unbalanced %c quote", quote)
+				}
+				break
+			}
+			c := line[i]
+			switch {
+			case strings.HasPrefix(line[i:], "{{"):
+				end := strings.Index(line[i:], "}}")
+				if end == -1 {
+					return nil, fmt.Errorf("unclosed template action %q", line[i:])
+				}
+				arg.WriteString(line[i : i+end+2])
+				i += end + 2
+				continue
+			case quote == 0 && isRedisSpace(c):
+				break scan
+			case quote == 0 && (c == '"' || c == '\''):
+				quote = c
+			case c == quote:
+				if i+1 < len(line) && !isRedisSpace(line[i+1]) {
+					return nil, fmt.Errorf("closing quote must be followed by a space")
+				}
+				i++
+				break scan
+			case quote == '"' && c == '\\' && i+1 < len(line):
+				i++
+				switch e := line[i]; e {
+				case 'n':
+					arg.WriteByte('\n')
+				case 'r':
+					arg.WriteByte('\r')
+				case 't':
+					arg.WriteByte('\t')
+				case 'b':
+					arg.WriteByte('\b')
+				case 'a':
+					arg.WriteByte('\a')
+				case 'x':
+					if b, err := strconv.ParseUint(line[i+1:min(i+3, len(line))], 16, 8); err == nil && i+3 <= len(line) {
+						arg.WriteByte(byte(b))
+						i += 2
+					} else {
+						arg.WriteByte(e)
+					}
+				default:
+					arg.WriteByte(e)
+				}
+			case quote == '\'' && c == '\\' && i+1 < len(line) && line[i+1] == '\'':
+				i++
+				arg.WriteByte('\'')
+			default:
+				arg.WriteByte(c)
+			}
+			i++
+		}
+		args = append(args, arg.String())
+	}
+}
+
+func isRedisSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// redisCommandLine renders args back into one line, quoting the ones that
+// would not survive splitRedisArgs bare
+func redisCommandLine(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\r\n\"'\\") {
+			a = strconv.Quote(a)
+		}
+		quoted[i] = a
+	}
+	return strings.Join(quoted, " ")
+}
+
+// renderRedisArgs executes the argument templates of c
+func (r *Runner) renderRedisArgs(c redisCommand, userID, reqID string) []string {
+	args := make([]string, len(c.Args))
+	for i, t := range c.Args {
+		args[i] = r.applyTemplates(t, userID, reqID)
+	}
+	return args
+}
+
+// initRedis creates the client and parses the command mix
+func (r *Runner) initRedis() error {
+	opts, err := redis.ParseURL(r.Cfg.URL)
+	if err != nil {
+		return fmt.Errorf("invalid redis URL: %w", err)
+	}
+	opts.DialTimeout = r.Cfg.GetConnectTimeout()
+	opts.ReadTimeout = r.Cfg.GetRequestTimeout()
+	opts.WriteTimeout = r.Cfg.GetRequestTimeout()
+	opts.PoolSize = 2000
+	r.Redis = redis.NewClient(opts)
+
+	lines := r.Cfg.RedisCommands
+	if len(lines) == 0 {
+		lines = []string{DefaultRedisCommand}
+	}
+
+	r.redisCmds = nil
+	r.redisWeight = 0
+	for i, l := range lines {
+		weight, text := parseRedisCommand(l)
+		if text == "" || weight <= 0 {
+			continue
+		}
+		fields, err := splitRedisArgs(text)
+		if err != nil {
+			return fmt.Errorf("error parsing redis command %q: %w", text, err)
+		}
+		cmd := redisCommand{Weight: weight}
+		for j, f := range fields {
+			t, err := r.TmplEngine.Parse(fmt.Sprintf("redis-%d-%d", i, j), f)
+			if err != nil {
+				return fmt.Errorf("error parsing redis command %q: %w", text, err)
+			}
+			cmd.Args = append(cmd.Args, t)
+		}
+		r.redisCmds = append(r.redisCmds, cmd)
+		r.redisWeight += weight
+	}
+	if len(r.redisCmds) == 0 {
+		return fmt.Errorf("no valid redis commands configured")
+	}
+	return nil
+}
+
+// pickRedisCommand selects a command from the weighted mix
+func (r *Runner) pickRedisCommand() redisCommand {
+	pick := r.rand.Intn(r.redisWeight)
+	for _, c := range r.redisCmds {
+		if pick < c.Weight {
+			return c
+		}
+		pick -= c.Weight
+	}
+	return r.redisCmds[len(r.redisCmds)-1]
+}
+
+// executeRedis picks a command from the weighted mix and runs it.
+// Returns status (200 on success), bytes, failure body, command name and error.
+// A cache miss (redis.Nil) is a valid outcome and counts as success.
+func (r *Runner) executeRedis(ctx context.Context, userID, reqID string) (int, int64, string, string, error) {
+	if r.Redis == nil || len(r.redisCmds) == 0 {
+		return 0, 0, "", "redis", fmt.Errorf("redis client not initialized")
+	}
+
+	fields := r.renderRedisArgs(r.pickRedisCommand(), userID, reqID)
+	if fields[0] == "" {
+		return 0, 0, "", "redis", fmt.Errorf("empty redis command")
+	}
+	name := strings.ToUpper(fields[0])
+	args := make([]interface{}, len(fields))
+	for i, f := range fields {
+		args[i] = f
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.Cfg.GetRequestTimeout())
+	defer cancel()
+
+	res, err := r.Redis.Do(ctx, args...).Result()
+	if err == redis.Nil {
+		return 200, 0, "", name, nil
+	}
+	if err != nil {
+		return 0, 0, err.Error(), name, err
+	}
+
+	var n int64
+	switch v := res.(type) {
+	case string:
+		n = int64(len(v))
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				n += int64(len(s))
+			}
+		}
+	}
+	return 200, n, "", name, nil
+}
@@ -0,0 +1,54 @@
+package runner
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitRedisArgs(t *testing.T) {
+	cases := []struct {
+		line string
+		want []string
+	}{
+		{`GET k`, []string{"GET", "k"}},
+		{`  SET   k  v  `, []string{"SET", "k", "v"}},
+		{`SET k "hello world"`, []string{"SET", "k", "hello world"}},
+		{`SET k 'it\'s'`, []string{"SET", "k", "it's"}},
+		{`SET k "a\"b\n\x41"`, []string{"SET", "k", "a\"b\nA"}},
+		{`SET k ''`, []string{"SET", "k", ""}},
+		{`SET k{{randomInt 1 10}} {{uuid}}`, []string{"SET", "k{{randomInt 1 10}}", "{{uuid}}"}},
+		{`SET k "v {{readFile "a b"}}"`, []string{"SET", "k", `v {{readFile "a b"}}`}},
+	}
+	for _, tc := range cases {
+		got, err := splitRedisArgs(tc.line)
+		if err != nil || !slices.Equal(got, tc.want) {
+			t.Errorf("splitRedisArgs(%q) = %q, %v; want %q", tc.line, got, err, tc.want)
+		}
+	}
+
+	for _, line := range []string{`SET k "open`, `SET k 'open`, `SET k "a"b`, `SET k {{uuid`} {
+		if got, err := splitRedisArgs(line); err == nil {
+			t.Errorf("splitRedisArgs(%q) = %q, want an error", line, got)
+		}
+	}
+}
+
+// A templated value that renders to spaces stays one argument
+func TestRenderRedisArgs(t *testing.T) {
+	r := NewRunner(Config{URL: "redis://localhost:6379/0", RedisCommands: []string{`SET k:{{.UserID}} "{{.UserID}} {{.UserID}}"`}}, nil)
+	r.TmplEngine = NewTemplateEngine(1)
+	r.rand = r.TmplEngine.rand
+	if err := r.initRedis(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Redis.Close()
+
+	got := r.renderRedisArgs(r.pickRedisCommand(), "a b", "")
+	want := []string{"SET", "k:a b", "a b a b"}
+	if !slices.Equal(got, want) {
+		t.Errorf("rendered %q, want %q", got, want)
+	}
+	if line := redisCommandLine(got); line != `SET "k:a b" "a b a b"` {
+		t.Errorf("command line %q", line)
+	}
+}
@@ -0,0 +1,144 @@
+package runner
+
+import (
+	"cmp"
+	"net/http"
+	"strings"
+)
+
+// renderedRequest is an HTTP request after templating, before it is sent
+type renderedRequest struct {
+	Method   string
+	URL      string
+	Header   http.Header
+	Host     string // Host header override, "" for the URL's host
+	Body     string
+	HasBody  bool
+	BodyFile string // Config.BodyDir payload the body came from
+}
+
+// renderHTTP builds the next HTTP request: URL, headers and body templated,
+// a body directory payload picked
+func (r *Runner) renderHTTP(userID, reqID string) renderedRequest {
+	rr := renderedRequest{Method: r.Cfg.Method, URL: r.Cfg.URL, Header: make(http.Header)}
+	if rr.Method == "" {
+		rr.Method = "GET"
+	}
+	if r.TmplURL != nil {
+		rr.URL = r.applyTemplates(r.TmplURL, userID, reqID)
+	}
+
+	var bodyType string
+	if len(r.bodyFiles) > 0 {
+		f := r.pickBody()
+		rr.Body = f.Raw
+		if f.Tmpl != nil {
+			rr.Body = r.applyTemplates(f.Tmpl, userID, reqID)
+		}
+		rr.HasBody = true
+		rr.BodyFile = f.Name
+		bodyType = f.ContentType
+	} else if r.Cfg.Body != "" && r.TmplBody != nil {
+		rr.Body = r.applyTemplates(r.TmplBody, userID, reqID)
+		rr.HasBody = true
+	} else if r.Cfg.Body != "" {
+		rr.Body = r.Cfg.Body
+		rr.HasBody = true
+	}
+
+	// Set Headers with templating
+	hasContentType := false
+	for k, v := range r.headers {
+		val := v
+		if t, ok := r.TmplHeader[k]; ok {
+			val = r.applyTemplates(t, userID, reqID)
+		}
+		rr.Header.Set(k, val)
+		if strings.ToLower(k) == "content-type" {
+			hasContentType = true
+		}
+	}
+	if name := r.Cfg.RequestIDHeader; name != "" && rr.Header.Get(name) == "" {
+		rr.Header.Set(name, reqID)
+	}
+	r.applyFingerprint(rr.Header, userID)
+	if host := rr.Header.Get("Host"); host != "" {
+		// net/http ignores a Host header entry, the override goes on the request
+		rr.Host = host
+		rr.Header.Del("Host")
+	}
+	if !hasContentType && rr.HasBody {
+		rr.Header.Set("Content-Type", cmp.Or(bodyType, "application/json"))
+	}
+	return rr
+}
+
+// DryRequest is one request as it would be sent, rendered by DryRun
+type DryRequest struct {
+	Protocol string
+	UserID   string
+	Method   string
+	URL      string
+	Host     string
+	Header   http.Header
+	Body     string
+	BodyFile string
+	Command  string   // Script: shell command; Redis: command line
+	Key      string   // Kafka message key
+	Args     []string // SQL arguments
+}
+
+// DryRun renders the first n requests of cfg (templates, data files, body
+// directory, headers) without sending anything. Protocol clients connect
+// lazily, so none is contacted.
+func DryRun(cfg Config, n int) []DryRequest {
+	r := NewRunner(cfg, nil)
+	cleanup := r.setup()
+	defer cleanup()
+
+	proto := cfg.GetProtocol()
+	if cfg.Command != "" {
+		proto = "script"
+	}
+	var users []string
+	if cfg.Mode == "users" {
+		for range min(n, max(cfg.NumUsers, 1)) {
+			users = append(users, r.rand.UUID())
+		}
+	}
+
+	out := make([]DryRequest, 0, n)
+	for i := range n {
+		// Users keep their ID across iterations, open-loop requests get a fresh one
+		userID := ""
+		if len(users) > 0 {
+			userID = users[i%len(users)]
+		} else {
+			userID = r.rand.UUID()
+		}
+		reqID := newRequestID()
+
+		d := DryRequest{Protocol: proto, UserID: userID, URL: cfg.URL}
+		switch proto {
+		case "script":
+			d.URL = ""
+			d.Command = r.applyTemplates(r.TmplCmd, userID, reqID)
+		case "redis":
+			if len(r.redisCmds) > 0 {
+				d.Command = redisCommandLine(r.renderRedisArgs(r.pickRedisCommand(), userID, reqID))
+			}
+		case "kafka", "sql", "tcp", "udp":
+			d.Body = r.applyTemplates(r.TmplBody, userID, reqID)
+			d.Key = r.applyTemplates(r.TmplKafkaKey, userID, reqID)
+			for _, t := range r.TmplSQLArgs {
+				d.Args = append(d.Args, r.applyTemplates(t, userID, reqID))
+			}
+		default:
+			rr := r.renderHTTP(userID, reqID)
+			d.Method, d.URL, d.Host, d.Header = rr.Method, r.redactor.String(rr.URL), rr.Host, r.redactor.Header(rr.Header)
+			d.Body, d.BodyFile = rr.Body, rr.BodyFile
+		}
+		out = append(out, d)
+	}
+	return out
+}
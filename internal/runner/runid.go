@@ -0,0 +1,28 @@
+package runner
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+)
+
+// crockford is the ULID alphabet (no I, L, O, U)
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewRunID returns a ULID for a run started at t: a 48-bit millisecond
+// timestamp and 80 random bits in 26 Crockford base32 characters, so IDs
+// sort by start time
+func NewRunID(t time.Time) string {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(t.UnixMilli())<<16)
+	rand.Read(b[6:])
+
+	hi, lo := binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])
+	out := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out)
+}
@@ -1,32 +1,77 @@
 package runner
 
 import (
-	"bytes"
 	"context"
-	"crypto/tls"
+	"database/sql"
+	"errors"
 	"fmt"
-	"io"
+	"hash/fnv"
+	"log/slog"
+	"net"
 	"net/http"
-	"os/exec"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"text/template"
 	"time"
 
+	"steadyq/internal/monitor"
 	"steadyq/internal/stats"
 
-	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
 )
 
 // StatsSnapshot is sent over the channel
 type StatsSnapshot struct {
+	State    RunState
 	Requests uint64
 	Success  uint64
 	Fail     uint64
 	Bytes    uint64
 	Inflight int64
 
+	// Bytes on the wire so far (request heads and bodies sent, response heads
+	// and bodies received) and per second over the last minute
+	SentBytes uint64
+	RecvBytes uint64
+	SentRate  []uint64
+	RecvRate  []uint64
+
+	// Requests in flight: the run's sampled average and per-second averages
+	// over the last minute, for the Little's Law panel
+	InflightAvg     float64
+	InflightHistory []float64
+
+	// Circuit Breaker
+	ShortCircuited uint64
+	BreakerState   string
+
+	// Retry-After Backoff
+	RetryAfterShed     uint64
+	RetryAfterPauseSec float64
+
+	// Open-loop requests never sent because the scheduler fell >1s behind
+	SchedulerSkipped uint64
+
+	// Local resource exhaustion (generator side, not server errors)
+	PortsExhausted uint64
+	FDsExhausted   uint64
+
+	// Requests rejected by TLS certificate verification
+	TLSVerifyFailed uint64
+
+	// Requests cancelled by an abort or when the users mode graceful stop ran out
+	ForceCancelled uint64
+
+	// Cache revalidation (Config.Conditional): conditional requests and their 304s
+	Conditional uint64
+	NotModified uint64
+
+	// Why an abort condition stopped the run ("" while it has not)
+	AbortReason string
+
 	// Pre-calculated percentiles for the UI (cheap copy)
 	P50ServiceMs  float64
 	P90ServiceMs  float64
@@ -35,27 +80,93 @@ type StatsSnapshot struct {
 	MaxServiceMs  int64
 	MeanServiceMs float64
 
+	// Dispersion of service times: fastest, standard deviation and the mean
+	// without the top and bottom stats.TrimPercent
+	MinServiceMs         float64
+	StdDevServiceMs      float64
+	TrimmedMeanServiceMs float64
+
+	// Service time at each of Config.GetPercentiles, in the same order
+	ServicePercentiles []float64
+
 	AvgQueueWaitMs float64
 
+	// Users mode: completed iterations (request + think time)
+	Iterations      uint64
+	P50IterationMs  float64
+	P99IterationMs  float64
+	MeanIterationMs float64
+	// Fan-out groups (Config.FanOut > 1): first request sent to last response
+	P50GroupMs float64
+	P99GroupMs float64
+	// Users stuck on a request longer than Config.GetStuckAfter: now, at
+	// most and iterations that were; requests cut by Config.IterationTimeout
+	StuckUsers         int64
+	StuckPeak          int64
+	StuckIterations    uint64
+	IterationDeadlines uint64
+
 	StatusCodes     map[int]int
 	ErrorCounts     map[string]int
 	ResponseSamples map[int]string
+
+	// Service time heatmap, one column per second (rows: stats.HeatmapBoundsMs)
+	Heatmap [][]int64
+
+	// Failures grouped by status + normalized message, most frequent first
+	Failures []stats.FailureSummary
+
+	// Target CPU/memory readings (Config.Monitor), oldest first
+	Target []monitor.Sample
+
+	// Load generator's own health, one reading per second, oldest first
+	Generator []monitor.SelfSample
+
+	// Results held for the summary and exports, and those the full buffer
+	// dropped (see Config.MaxResults)
+	ResultsKept    int
+	ResultsDropped uint64
 }
 
+// heatmapSnapshotCols is how much heatmap history each snapshot carries
+const heatmapSnapshotCols = 300
+
+// targetSnapshotSamples is how much target and generator monitor history each snapshot carries
+const targetSnapshotSamples = 120
+
+// ErrRequestDeadline is reported when the overall per-request deadline expires
+var ErrRequestDeadline = errors.New("request deadline exceeded")
+
+// ErrIterationDeadline is reported for users mode requests cut off by
+// Config.IterationTimeout before their own request deadline
+var ErrIterationDeadline = errors.New("iteration deadline exceeded")
+
+// ErrForceCancelled is reported for requests still in flight when the run was
+// aborted or the users mode graceful stop period ran out
+var ErrForceCancelled = errors.New("force-cancelled")
+
 // StatsUpdateChan is the channel type
 type StatsUpdateChan chan StatsSnapshot
 
 type Runner struct {
-	Cfg     Config
-	Stats   *stats.Stats
-	Client  *http.Client
-	Results []ExperimentResult
+	Cfg    Config
+	Stats  *stats.Stats
+	Client *http.Client
+	// The run's latest Config.GetMaxResults results, pushed under mu; read
+	// them with SnapshotResults
+	results resultRing
 	mu      sync.Mutex
 
 	Inflight int64
 
-	// Event Channel
-	Updates StatsUpdateChan
+	// Outputs: results, live snapshots and completion (see ResultSink)
+	sinks []ResultSink
+
+	// Sends one request of the configured protocol (see Executor)
+	executor Executor
+
+	// Idle, running, draining or done (see Start, Stop, Status)
+	life lifecycle
 
 	// Template Engine
 	TmplEngine *TemplateEngine
@@ -63,57 +174,201 @@ type Runner struct {
 	TmplBody   *template.Template
 	TmplCmd    *template.Template
 	TmplHeader map[string]*template.Template
+
+	// Headers sent (Config.ResolveHeaders) and the masking of the secret
+	// values resolved into them and the URL (nil = none)
+	headers  map[string]string
+	redactor *redactor
+
+	// Live pause / rate adjustments
+	control runControl
+
+	// Egress cap on request bodies (nil = unlimited)
+	bandwidth *bandwidthLimiter
+
+	// Emulated client link (Config.NetworkProfile, nil = none) and, in users
+	// mode, each virtual user's link
+	network *NetworkProfile
+	links   *linkCache
+
+	// Parent of every request context, cancelled by Abort or when the users
+	// mode graceful stop period runs out
+	abortCtx context.Context
+	abort    context.CancelFunc
+
+	// Early-stop conditions (nil when none are set), stopRun cancels the
+	// current Run context and abortReason records why
+	guard       *abortGuard
+	stopRun     context.CancelFunc
+	abortReason string
+
+	// Body directory payloads (Config.BodyDir)
+	bodyFiles  []bodyFile
+	bodyWeight int
+
+	// Client identities rotated across users (Config.Fingerprints)
+	fingerprints []Fingerprint
+
+	// Validators of earlier responses (Config.Conditional, nil when off)
+	validators *validatorCache
+
+	// Redis Executor
+	Redis       *redis.Client
+	redisCmds   []redisCommand
+	redisWeight int
+
+	// Kafka Executor
+	Kafka        *kafka.Writer
+	TmplKafkaKey *template.Template
+
+	// SQL Executor
+	DB          *sql.DB
+	TmplSQLArgs []*template.Template
+
+	// Client-side Circuit Breaker (nil when disabled)
+	Breaker *CircuitBreaker
+
+	// Status codes counted as success (parsed Config.SuccessCodes)
+	successCodes StatusSet
+
+	// Open-loop backoff deadline (UnixNano) set by Retry-After responses
+	backoffUntil int64
+
+	// Shadow copies of HTTP requests (Config.Mirror, nil when off)
+	mirror *mirror
+
+	// Users mode: when each user started waiting on its requests, under mu
+	// (nil in other modes)
+	users userSlots
+
+	// Pinned DNS answers (Config.PinDNS, nil when off)
+	DNS *DNSPin
+
+	// Target resource monitor (nil when Config.Monitor is empty)
+	Monitor *monitor.Monitor
+
+	// Host, build and repo details of the last run
+	Meta RunMetadata
+
+	// Seed of the run's random source (Config.Seed, or a random one reported for reuse)
+	Seed int64
+	rand *lockedRand
+
+	// Load generator self-monitoring, sampled every second while running
+	Self *monitor.SelfMonitor
 }
 
+// NewRunner creates a runner that keeps the run's results (see
+// SnapshotResults) and, when updates is not nil, sends live snapshots to it
+// (see ChannelSink)
 func NewRunner(cfg Config, updates StatsUpdateChan) *Runner {
-	t := http.DefaultTransport.(*http.Transport).Clone()
-	t.MaxIdleConns = 2000
-	t.MaxConnsPerHost = 2000
-	t.MaxIdleConnsPerHost = 2000
-	t.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	r := &Runner{
+		Cfg:     cfg,
+		Stats:   stats.NewStats(),
+		Client:  newHTTPClient(cfg, nil),
+		results: newResultRing(cfg.GetMaxResults()),
+	}
+	r.sinks = []ResultSink{resultStore{r}}
+	if updates != nil {
+		r.sinks = append(r.sinks, ChannelSink(updates))
+	}
+	return r
+}
 
-	timeout := time.Duration(cfg.TimeoutSec) * time.Second
-	if cfg.TimeoutSec == 0 {
-		timeout = 30 * time.Second
+// newHTTPClient builds a pooled client with each timeout wired to the Transport.
+// The overall request deadline is applied per request via context instead of Client.Timeout.
+func newHTTPClient(cfg Config, pin *DNSPin) *http.Client {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = defaultMaxConns
+	t.MaxConnsPerHost = defaultMaxConns
+	t.MaxIdleConnsPerHost = defaultMaxConns
+	if cfg.MaxConns > 0 {
+		t.MaxIdleConns = cfg.MaxConns
+		t.MaxConnsPerHost = cfg.MaxConns
+		t.MaxIdleConnsPerHost = cfg.MaxConns
+	}
+	t.TLSClientConfig = tlsConfig(cfg)
+	t.DialContext = dialContext(cfg, pin)
+	if cfg.TLSHandshakeTimeout > 0 {
+		t.TLSHandshakeTimeout = cfg.TLSHandshakeTimeout
+	}
+	if cfg.ResponseHeaderTimeout > 0 {
+		t.ResponseHeaderTimeout = cfg.ResponseHeaderTimeout
 	}
 
-	client := &http.Client{
-		Timeout:   timeout,
+	return &http.Client{
 		Transport: t,
 	}
+}
 
-	if updates == nil {
-		// Avoid nil panics if not provided
-		updates = make(StatsUpdateChan, 10)
+// dialContext opens the client's connections: connect timeout, pinned DNS
+// answers and the ConnectTo override applied
+func dialContext(cfg Config, pin *DNSPin) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout:   cfg.GetConnectTimeout(),
+		KeepAlive: 30 * time.Second,
 	}
-
-	return &Runner{
-		Cfg:     cfg,
-		Stats:   stats.NewStats(),
-		Client:  client,
-		Updates: updates,
+	dial := dialer.DialContext
+	if pin != nil {
+		dial = pin.dialer(dial)
+	}
+	if cfg.ConnectTo == "" {
+		return dial
+	}
+	// Keep the URL (and Host/SNI) but send every connection to ConnectTo
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return dial(ctx, network, connectAddr(cfg.ConnectTo, addr))
 	}
 }
 
-// StartTickLoop starts a goroutine that pushes stats updates until stop channel is closed
-func (r *Runner) StartTickLoop(stop chan struct{}, interval time.Duration) {
+// inflightSampleEvery is how often the tick loop samples the in-flight count
+const inflightSampleEvery = 100 * time.Millisecond
+
+// StartTickLoop starts a goroutine that pushes stats updates until stop channel is closed.
+// The returned channel is closed once the final update has been sent.
+func (r *Runner) StartTickLoop(stop chan struct{}, interval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
 	go func() {
+		defer close(done)
 		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
+		heatmapTicker := time.NewTicker(time.Second)
+		defer heatmapTicker.Stop()
+		inflightTicker := time.NewTicker(inflightSampleEvery)
+		defer inflightTicker.Stop()
 		for {
 			select {
 			case <-stop:
+				r.Stats.RotateInterval()
+				r.Stats.FlushSnapshot()
 				r.sendUpdate() // One final update
 				return
+			case <-heatmapTicker.C:
+				r.checkAbort(r.Stats.RotateInterval())
+				if r.Self != nil {
+					r.Self.Sample()
+				}
+			case <-inflightTicker.C:
+				r.Stats.SampleInflight(atomic.LoadInt64(&r.Inflight))
+				r.sampleStuck()
 			case <-ticker.C:
 				r.sendUpdate()
 			}
 		}
 	}()
+	return done
 }
 
 func (r *Runner) sendUpdate() {
-	// Create snapshot
+	s := r.Snapshot()
+	for _, sink := range r.sinks {
+		sink.OnInterval(s)
+	}
+}
+
+// Snapshot reads the live counters into a StatsSnapshot; safe to call while
+// a run is active
+func (r *Runner) Snapshot() StatsSnapshot {
 	s := StatsSnapshot{
 		Requests:        atomic.LoadUint64(&r.Stats.Requests),
 		Success:         atomic.LoadUint64(&r.Stats.Success),
@@ -131,24 +386,131 @@ func (r *Runner) sendUpdate() {
 		ErrorCounts:     r.Stats.GetErrorCounts(),
 		ResponseSamples: r.Stats.GetResponseSamples(),
 	}
+	s.SentBytes = atomic.LoadUint64(&r.Stats.SentBytes)
+	s.RecvBytes = atomic.LoadUint64(&r.Stats.RecvBytes)
+	s.SentRate, s.RecvRate = r.Stats.GetTransferRates(60)
+	s.InflightAvg, _ = r.Stats.InflightAvg()
+	s.InflightHistory = r.Stats.GetConcurrency(60)
+	s.MinServiceMs = float64(r.Stats.ServiceTime.Min()) / 1000
+	s.StdDevServiceMs = r.Stats.ServiceTime.StdDev() / 1000
+	s.TrimmedMeanServiceMs = r.Stats.ServiceTime.TrimmedMean() / 1000
+	for _, p := range r.Cfg.GetPercentiles() {
+		s.ServicePercentiles = append(s.ServicePercentiles, float64(r.Stats.ServiceTime.ValueAtQuantile(p))/1000)
+	}
 
-	// Non-blocking send
-	select {
-	case r.Updates <- s:
-	default:
-		// Drop update if channel full, UI acts as backpressure
+	// Load shed by the client (breaker, Retry-After)
+	s.ShortCircuited = atomic.LoadUint64(&r.Stats.ShortCircuited)
+	s.RetryAfterShed = atomic.LoadUint64(&r.Stats.RetryAfterShed)
+	s.RetryAfterPauseSec = float64(atomic.LoadInt64(&r.Stats.RetryAfterPauseMicro)) / 1e6
+	s.SchedulerSkipped = atomic.LoadUint64(&r.Stats.SchedulerSkipped)
+	s.PortsExhausted = atomic.LoadUint64(&r.Stats.PortsExhausted)
+	s.FDsExhausted = atomic.LoadUint64(&r.Stats.FDsExhausted)
+	s.TLSVerifyFailed = atomic.LoadUint64(&r.Stats.TLSVerifyFailed)
+	s.ForceCancelled = atomic.LoadUint64(&r.Stats.ForceCancelled)
+	s.Conditional = atomic.LoadUint64(&r.Stats.Conditional)
+	s.NotModified = atomic.LoadUint64(&r.Stats.NotModified)
+	s.AbortReason = r.AbortReason()
+	s.State = r.State()
+	if r.Cfg.Mode == "users" {
+		s.Iterations = atomic.LoadUint64(&r.Stats.Iterations)
+		s.P50IterationMs = float64(r.Stats.IterationTime.ValueAtQuantile(50)) / 1000
+		s.P99IterationMs = float64(r.Stats.IterationTime.ValueAtQuantile(99)) / 1000
+		s.MeanIterationMs = r.Stats.IterationTime.Mean() / 1000
+		if r.Cfg.GetFanOut() > 1 {
+			s.P50GroupMs = float64(r.Stats.GroupTime.ValueAtQuantile(50)) / 1000
+			s.P99GroupMs = float64(r.Stats.GroupTime.ValueAtQuantile(99)) / 1000
+		}
+		s.StuckUsers = atomic.LoadInt64(&r.Stats.StuckUsers)
+		s.StuckPeak = atomic.LoadInt64(&r.Stats.StuckPeak)
+		s.StuckIterations = atomic.LoadUint64(&r.Stats.StuckIterations)
+		s.IterationDeadlines = atomic.LoadUint64(&r.Stats.IterationDeadlines)
+	}
+	s.ResultsKept = r.ResultCount()
+	s.ResultsDropped = atomic.LoadUint64(&r.Stats.ResultsDropped)
+	s.Heatmap = r.Stats.GetHeatmap(heatmapSnapshotCols)
+	s.Failures = r.Stats.GetFailures()
+	if r.Breaker != nil {
+		s.BreakerState = r.Breaker.State()
+	}
+	if r.Monitor != nil {
+		s.Target = r.Monitor.Samples(targetSnapshotSamples)
+	}
+	if r.Self != nil {
+		s.Generator = r.Self.Samples(targetSnapshotSamples)
 	}
+	return s
 }
 
-func (r *Runner) Run(ctx context.Context) {
+// setup parses templates and opens protocol clients for the current Cfg.
+// The returned func releases the clients.
+func (r *Runner) setup() (cleanup func()) {
+	cleanup = func() {}
+
+	// Rebuild client, Cfg may have changed since NewRunner (TUI)
+	r.DNS = nil
+	if r.Cfg.PinsDNS() {
+		r.DNS = newDNSPin(r.Cfg.DNSTTL)
+		target := r.Cfg.URL
+		if r.Cfg.ConnectTo != "" {
+			target = r.Cfg.ConnectTo
+		}
+		r.DNS.resolve(context.Background(), target)
+	}
+	r.Client = newHTTPClient(r.Cfg, r.DNS)
+
+	var m *mirror
+	if r.Cfg.Mirror != "" && r.Cfg.GetProtocol() == "http" {
+		var err error
+		if m, err = newMirror(r.Cfg); err != nil {
+			setupError("setting up the mirror", err)
+		}
+	}
+	r.mu.Lock()
+	r.mirror = m
+	r.mu.Unlock()
+
+	atomic.StoreInt64(&r.backoffUntil, 0)
+	r.Breaker = nil
+	if r.Cfg.BreakerErrorRate > 0 {
+		r.Breaker = NewCircuitBreaker(r.Cfg)
+	}
+
+	r.successCodes = nil
+	if codes, err := ParseSuccessCodes(r.Cfg.SuccessCodes); err != nil {
+		setupError("parsing success codes", err)
+	} else {
+		r.successCodes = codes
+	}
+
+	r.Stats.SetSnapshotInterval(r.Cfg.SnapshotInterval)
+	r.Self = monitor.NewSelf()
+	r.Monitor = nil
+	if r.Cfg.Monitor != "" {
+		mon, err := monitor.New(r.Cfg.Monitor, r.Cfg.MonitorInterval)
+		if err != nil {
+			setupError("initializing monitor", err)
+		} else {
+			r.Monitor = mon
+		}
+	}
+
 	// Initialize Template Engine
-	r.TmplEngine = NewTemplateEngine()
+	r.Seed = r.Cfg.Seed
+	if r.Seed == 0 {
+		r.Seed = randomSeed()
+	}
+	r.TmplEngine = NewTemplateEngine(r.Seed)
+	r.rand = r.TmplEngine.rand
 	var err error
 
 	// Parse URL
-	r.TmplURL, err = r.TmplEngine.Parse("url", r.Cfg.URL)
+	urlText, secrets, err := resolveSecrets(r.Cfg.URL)
+	if err != nil {
+		setupError("resolving URL secrets", err)
+	}
+	r.TmplURL, err = r.TmplEngine.Parse("url", urlText)
 	if err != nil {
-		fmt.Printf("Error parsing URL template: %v\n", err) // Should probably log better
+		setupError("parsing URL template", err)
 	}
 
 	// Parse Body
@@ -160,39 +522,198 @@ func (r *Runner) Run(ctx context.Context) {
 		}
 		r.TmplBody, err = r.TmplEngine.Parse("body", bodyText)
 		if err != nil {
-			fmt.Printf("Error parsing Body template: %v\n", err)
+			setupError("parsing Body template", err)
 		}
 	}
 
+	r.control.reset()
+
+	r.mu.Lock()
+	r.abortCtx, r.abort = context.WithCancel(context.Background())
+	r.mu.Unlock()
+
+	r.bandwidth = nil
+	if bps, err := ParseBandwidth(r.Cfg.Bandwidth); err != nil {
+		setupError("parsing bandwidth", err)
+	} else if bps > 0 {
+		r.bandwidth = newBandwidthLimiter(bps)
+	}
+	if p, err := ParseNetworkProfile(r.Cfg.NetworkProfile); err != nil {
+		setupError("parsing network profile", err)
+		r.network = nil
+	} else {
+		r.network = p
+	}
+	r.links = nil
+	if r.network != nil && r.Cfg.Mode == "users" {
+		r.links = newLinkCache()
+	}
+
+	// Load Body Directory
+	if err := r.initBodyDir(); err != nil {
+		setupError("loading body directory", err)
+	}
+
+	r.validators = nil
+	if r.Cfg.Conditional {
+		r.validators = newValidatorCache()
+	}
+
+	if fps, err := LoadFingerprints(r.Cfg.Fingerprints); err != nil {
+		setupError("loading fingerprints", err)
+		r.fingerprints = nil
+	} else {
+		r.fingerprints = fps
+	}
+
 	// Parse Command
 	if r.Cfg.Command != "" {
 		r.TmplCmd, err = r.TmplEngine.Parse("cmd", r.Cfg.Command)
 		if err != nil {
-			fmt.Printf("Error parsing Command template: %v\n", err)
+			setupError("parsing Command template", err)
 		}
 	}
 
 	// Parse Headers
+	r.headers, err = r.Cfg.ResolveHeaders()
+	if err != nil {
+		setupError("loading header presets", err)
+		r.headers = r.Cfg.Headers
+	}
 	r.TmplHeader = make(map[string]*template.Template)
-	for k, v := range r.Cfg.Headers {
-		t, err := r.TmplEngine.Parse("header-"+k, v)
+	for k, v := range r.headers {
+		text, values, err := resolveSecrets(v)
 		if err != nil {
-			fmt.Printf("Error parsing Header '%s' template: %v\n", k, err)
+			setupError(fmt.Sprintf("resolving Header '%s' secrets", k), err)
+		}
+		secrets = append(secrets, values...)
+		t, err := r.TmplEngine.Parse("header-"+k, text)
+		if err != nil {
+			setupError(fmt.Sprintf("parsing Header '%s' template", k), err)
 		} else {
 			r.TmplHeader[k] = t
 		}
 	}
+	r.redactor = newRedactor(secrets)
+
+	// Setup Protocol Clients
+	switch r.Cfg.GetProtocol() {
+	case "redis":
+		if err := r.initRedis(); err != nil {
+			setupError("initializing redis", err)
+		}
+		cleanup = func() {
+			if r.Redis != nil {
+				r.Redis.Close()
+			}
+		}
+	case "kafka":
+		if err := r.initKafka(); err != nil {
+			setupError("initializing kafka", err)
+		}
+		cleanup = func() {
+			if r.Kafka != nil {
+				r.Kafka.Close()
+			}
+		}
+	case "sql":
+		if err := r.initSQL(); err != nil {
+			setupError("initializing sql", err)
+		}
+		cleanup = func() {
+			if r.DB != nil {
+				r.DB.Close()
+			}
+		}
+	}
+
+	// Protocol executor, after the clients it uses
+	r.executor = r.newExecutor()
+
+	return cleanup
+}
+
+// setupError reports a setup problem on the console and in the log; the run
+// goes ahead without the broken part
+func setupError(step string, err error) {
+	fmt.Printf("Error %s: %v\n", step, err)
+	slog.Error("run setup failed", "step", step, "err", err)
+}
+
+// Run runs Cfg to completion in the calling goroutine; Start runs it in the
+// background with lifecycle control
+func (r *Runner) Run(ctx context.Context) {
+	r.setState(StateRunning)
+	cleanup := r.setup()
+	defer cleanup()
+	meta := CollectMetadata(r.Cfg)
+	meta.Seed = r.Seed
+	r.mu.Lock()
+	r.Meta = meta
+	r.mu.Unlock()
+
+	log := slog.With("run_id", r.Meta.RunID)
+	log.Info("run started", "mode", r.Meta.Mode, "protocol", r.Cfg.GetProtocol(), "url", r.Cfg.URL,
+		"rps", r.Cfg.TargetRPS, "users", r.Cfg.NumUsers, "duration_s", r.Cfg.RampUp+r.Cfg.SteadyDur+r.Cfg.RampDown, "seed", r.Seed)
+	defer func() {
+		// Registered first so it runs after the final interval is flushed
+		if r.mirror != nil {
+			r.mirror.wait()
+		}
+		log.Info("run finished", "elapsed", time.Since(r.Meta.StartedAt).Round(time.Millisecond),
+			"requests", atomic.LoadUint64(&r.Stats.Requests), "fail", atomic.LoadUint64(&r.Stats.Fail),
+			"force_cancelled", atomic.LoadUint64(&r.Stats.ForceCancelled), "abort_reason", r.AbortReason())
+		for _, sink := range r.sinks {
+			sink.OnComplete(r)
+		}
+	}()
+
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+	r.mu.Lock()
+	r.stopRun, r.abortReason, r.guard = stop, "", nil
+	r.results = newResultRing(r.Cfg.GetMaxResults())
+	if r.Cfg.WantsAbortGuard() {
+		r.guard = newAbortGuard(r.Cfg, time.Now())
+	}
+	r.mu.Unlock()
 
 	// Start Tick Loop for UI
 	stopTicker := make(chan struct{})
-	r.StartTickLoop(stopTicker, 100*time.Millisecond)
-	defer close(stopTicker)
+	tickerDone := r.StartTickLoop(stopTicker, 100*time.Millisecond)
+	defer func() {
+		// Wait for the last interval to be flushed before callers export it
+		close(stopTicker)
+		<-tickerDone
+	}()
 
+	if r.Monitor != nil {
+		monCtx, stopMonitor := context.WithCancel(context.Background())
+		defer stopMonitor()
+		go r.Monitor.Run(monCtx)
+	}
+
+	generated := make(chan struct{})
+	go r.watchDrain(ctx, generated)
 	if r.Cfg.Mode == "users" {
 		r.runUsers(ctx)
 	} else {
 		r.runRPS(ctx)
 	}
+	close(generated)
+	// Before the final snapshot, so it reports the run as done
+	r.setState(StateDone)
+}
+
+// Abort cancels every request still in flight; they are counted as
+// force-cancelled. Cancel the Run context first so no new ones start.
+func (r *Runner) Abort() {
+	slog.Warn("cancelling in-flight requests", "run_id", r.Meta.RunID, "inflight", atomic.LoadInt64(&r.Inflight))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.abort != nil {
+		r.abort()
+	}
 }
 
 // applyTemplates executes the pre-parsed templates
@@ -211,215 +732,47 @@ func (r *Runner) applyTemplates(t *template.Template, userID, requestUUID string
 	return out
 }
 
-// ... rest of the runUsers/runRPS logic ...
-// (We reuse the existing logic, but I need to include it here to compile)
-
-func (r *Runner) runUsers(ctx context.Context) {
-	var wg sync.WaitGroup
-	start := time.Now()
-	totalDur := time.Duration(r.Cfg.RampUp+r.Cfg.SteadyDur+r.Cfg.RampDown) * time.Second
-
-	// Calculate spawn interval for RampUp
-	// If RampUp is 0, we spawn all immediately (interval 0)
-	var spawnInterval time.Duration
-	if r.Cfg.RampUp > 0 && r.Cfg.NumUsers > 1 {
-		// e.g. 10 users over 10s = 1 user per 1s
-		spawnInterval = time.Duration(float64(r.Cfg.RampUp) / float64(r.Cfg.NumUsers) * float64(time.Second))
-	}
-
-	for i := 0; i < r.Cfg.NumUsers; i++ {
-		// Wait before spawning next user if RampUp is active
-		if i > 0 && spawnInterval > 0 {
-			select {
-			case <-ctx.Done():
-				return
-			case <-time.After(spawnInterval):
-			}
-		}
-
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			// Generate STABLE userID for this virtual user
-			vUser := uuid.New().String()
-			for {
-				select {
-				case <-ctx.Done():
-					return
-				default:
-					if time.Since(start) > totalDur {
-						return
-					}
-					r.executeRequest(time.Now(), vUser)
-					if r.Cfg.ThinkTime > 0 {
-						time.Sleep(r.Cfg.ThinkTime)
-					}
-				}
-			}
-		}()
-	}
-	wg.Wait()
-}
-
-func (r *Runner) runRPS(ctx context.Context) {
-	start := time.Now()
-	totalDur := time.Duration(r.Cfg.RampUp+r.Cfg.SteadyDur+r.Cfg.RampDown) * time.Second
-
-	var wg sync.WaitGroup
-	nextRequestTime := start
-
-	for {
-		select {
-		case <-ctx.Done():
-			wg.Wait()
-			return
-		default:
-			now := time.Now()
-			elapsed := now.Sub(start).Seconds()
-
-			if elapsed >= totalDur.Seconds() {
-				wg.Wait()
-				return
-			}
-
-			targetRPS := r.getCurrentRPS(elapsed)
-			if targetRPS <= 0.001 {
-				time.Sleep(100 * time.Millisecond)
-				nextRequestTime = time.Now()
-				continue
-			}
-
-			period := time.Duration(float64(time.Second) / targetRPS)
-
-			// If we are way behind (more than 1s), reset nextRequestTime to avoid a massive burst
-			// But if we are only slightly behind, spawn immediately to catch up.
-			if now.Sub(nextRequestTime) > 1*time.Second {
-				nextRequestTime = now
-			}
-
-			// While we are behind the schedule, spawn requests
-			for nextRequestTime.Before(now) || nextRequestTime.Equal(now) {
-				wg.Add(1)
-				scheduledTime := nextRequestTime
-				go func() {
-					defer wg.Done()
-					// RPS mode = independent events, fresh userID by default
-					r.executeRequest(scheduledTime, uuid.New().String())
-				}()
-				nextRequestTime = nextRequestTime.Add(period)
-			}
-
-			// If next one is in the future, sleep until then
-			if nextRequestTime.After(now) {
-				time.Sleep(nextRequestTime.Sub(now))
-			}
-		}
-	}
+// executeRequest runs one scheduled request through the protocol executor and
+// records it: breaker, inflight, timing, success, Retry-After, stats and sinks
+func (r *Runner) executeRequest(scheduledTime time.Time, userID string) ExperimentResult {
+	return r.executeRequestCtx(r.abortCtx, scheduledTime, userID)
 }
 
-func (r *Runner) executeRequest(scheduledTime time.Time, userID string) {
+// executeRequestCtx is executeRequest under ctx, the run's abort context or
+// an iteration's (see iterationContext)
+func (r *Runner) executeRequestCtx(ctx context.Context, scheduledTime time.Time, userID string) ExperimentResult {
 	actualStart := time.Now()
 	queueWait := actualStart.Sub(scheduledTime)
 	if queueWait < 0 {
 		queueWait = 0
 	}
 
-	atomic.AddInt64(&r.Inflight, 1)
-	defer atomic.AddInt64(&r.Inflight, -1)
-
-	reqID := uuid.New().String()
-
-	var err error
-	var status int
-	var bytesLen int64
-	var respBody string
-
-	if r.Cfg.Command != "" {
-		// Custom Script Execution
-		// We use TmplCmd if available, otherwise fallback to raw string (shouldn't happen if parsed)
-		cmdStr := ""
-		if r.TmplCmd != nil {
-			cmdStr = r.applyTemplates(r.TmplCmd, userID, reqID)
-		} else {
-			cmdStr = r.Cfg.Command
-		}
-
-		// Execute shell
-		// Using sh -c to allow complex commands
-		cmd := exec.Command("sh", "-c", cmdStr)
-		var out bytes.Buffer
-		var stderr bytes.Buffer
-		cmd.Stdout = &out
-		cmd.Stderr = &stderr
-
-		err = cmd.Run()
-
-		if err == nil {
-			status = 200 // Success assumption for zero exit code
-			bytesLen = int64(out.Len())
-			respBody = out.String()
-		} else {
-			status = 500
-			if exitErr, ok := err.(*exec.ExitError); ok {
-				status = exitErr.ExitCode()
-			}
-			respBody = stderr.String()
-		}
-
-	} else {
-		// Standard HTTP Request
-		method := r.Cfg.Method
-		if method == "" {
-			method = "GET"
-		}
-
-		url := ""
-		if r.TmplURL != nil {
-			url = r.applyTemplates(r.TmplURL, userID, reqID)
-		} else {
-			url = r.Cfg.URL
-		}
-
-		var body io.Reader
-		if r.Cfg.Body != "" && r.TmplBody != nil {
-			bodyStr := r.applyTemplates(r.TmplBody, userID, reqID)
-			body = strings.NewReader(bodyStr)
-		} else if r.Cfg.Body != "" {
-			body = strings.NewReader(r.Cfg.Body)
-		}
-
-		req, _ := http.NewRequest(method, url, body)
-
-		// Set Headers with templating
-		hasContentType := false
-		for k, v := range r.Cfg.Headers {
-			val := v
-			if t, ok := r.TmplHeader[k]; ok {
-				val = r.applyTemplates(t, userID, reqID)
-			}
-			req.Header.Set(k, val)
-			if strings.ToLower(k) == "content-type" {
-				hasContentType = true
-			}
-		}
-		if !hasContentType && r.Cfg.Body != "" {
-			req.Header.Set("Content-Type", "application/json")
+	// Client-side breaker sheds the request before it reaches the wire
+	probe := false
+	if r.Breaker != nil {
+		var ok bool
+		if ok, probe = r.Breaker.Allow(); !ok {
+			r.Stats.AddShortCircuit()
+			return ExperimentResult{TimeStamp: scheduledTime, UserID: userID, Err: ErrCircuitOpen}
 		}
+	}
 
-		var resp *http.Response
-		resp, err = r.Client.Do(req)
+	atomic.AddInt64(&r.Inflight, 1)
+	defer atomic.AddInt64(&r.Inflight, -1)
 
-		if err == nil {
-			status = resp.StatusCode
-			bytesLen = resp.ContentLength
+	reqID := newRequestID()
+	resp := r.executor.Execute(ctx, userID, reqID)
+	err, status := resp.Err, resp.Status
 
-			if resp.StatusCode >= 400 {
-				b, _ := io.ReadAll(resp.Body)
-				respBody = string(b)
-			}
-			io.Copy(io.Discard, resp.Body)
-			resp.Body.Close()
-		}
+	if err != nil && r.abortCtx.Err() != nil {
+		// Cut off by the end of the graceful stop period, not a server failure
+		r.Stats.AddForceCancelled()
+		return ExperimentResult{TimeStamp: scheduledTime, UserID: userID, URL: r.redactor.String(resp.URL), Err: ErrForceCancelled}
+	}
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		// The iteration's deadline passed before the request's own
+		r.Stats.AddIterationDeadline()
+		err = ErrIterationDeadline
 	}
 
 	endTime := time.Now()
@@ -430,25 +783,76 @@ func (r *Runner) executeRequest(scheduledTime time.Time, userID string) {
 		TimeStamp:    scheduledTime,
 		Latency:      totalLatency,
 		ServiceTime:  serviceTime,
+		ConnectTime:  resp.ConnectTime,
 		QueueWait:    queueWait,
 		Err:          err,
 		UserID:       userID,
-		Query:        "custom",
+		Query:        resp.Query,
 		Status:       status,
-		Bytes:        bytesLen,
-		ResponseBody: respBody,
+		Bytes:        resp.Bytes,
+		ResponseBody: resp.Body[:min(len(resp.Body), maxResponseBody)],
+		RetryAfter:   resp.RetryAfter,
+		URL:          resp.URL,
+		SentBytes:    resp.SentBytes,
+		Method:       resp.Method,
+		HeadersHash:  resp.HeadersHash,
+		Attempt:      1,
+		RemoteAddr:   resp.RemoteAddr,
+		Label:        r.Cfg.GetLabel(),
+		Tags:         r.Cfg.Tags,
+		Conditional:  resp.Conditional,
+		NewConn:      resp.NewConn,
+		Reused:       resp.Reused,
+		TLS:          resp.TLS,
+		RequestID:    resp.RequestID,
+
+		SentHeaderBytes: resp.SentHeaderBytes,
+		RecvHeaderBytes: resp.RecvHeaderBytes,
 	}
+	r.redactor.result(&res)
+	err = res.Err
 
-	if err == nil {
-		if status >= 200 && status < 300 {
-			res.Success = true
+	if res.RetryAfter > 0 && r.Cfg.Mode != "users" {
+		// Open loop: stop sending until the server-requested deadline
+		until := time.Now().Add(res.RetryAfter).UnixNano()
+		for {
+			cur := atomic.LoadInt64(&r.backoffUntil)
+			if until <= cur || atomic.CompareAndSwapInt64(&r.backoffUntil, cur, until) {
+				break
+			}
 		}
 	}
 
+	if err == nil {
+		// A 304 is the cache hit a conditional request asked for
+		res.Success = r.successCodes.Contains(status) || (res.Conditional && status == http.StatusNotModified)
+	}
+	if res.Conditional {
+		r.Stats.AddConditional(err == nil && status == http.StatusNotModified)
+	}
+
+	// Port/descriptor exhaustion is a generator problem: count it apart and keep it out of the breaker
+	exhausted := exhaustionKind(err)
+	if exhausted != "" {
+		r.Stats.AddExhausted(exhausted == ExhaustedPorts)
+	}
+
+	if r.Breaker != nil && exhausted == "" {
+		r.Breaker.Record(res.Success, probe)
+	}
+
 	errStr := ""
-	if err != nil {
+	if exhausted != "" {
+		errStr = exhaustionError(exhausted)
+	} else if verify := tlsVerifyError(err); verify != "" {
+		r.Stats.AddTLSVerifyFailed()
+		errStr = verify
+	} else if err != nil {
 		errStr = cleanError(err)
 	}
+	if !res.Success {
+		slog.Debug("request failed", "user", userID, "url", res.URL, "status", res.Status, "err", errStr)
+	}
 
 	r.Stats.Add(
 		res.Success,
@@ -458,41 +862,40 @@ func (r *Runner) executeRequest(scheduledTime time.Time, userID string) {
 		res.Latency,
 		res.Status,
 		errStr,
-		respBody,
+		res.ResponseBody,
 	)
 
-	r.mu.Lock()
-	r.Results = append(r.Results, res)
-	r.mu.Unlock()
-}
+	r.Stats.AddTransfer(uint64(res.SentHeaderBytes+res.SentBytes), uint64(res.RecvHeaderBytes+res.Bytes))
 
-func (r *Runner) getCurrentRPS(elapsedSec float64) float64 {
-	cfg := r.Cfg
-	if elapsedSec < float64(cfg.RampUp) {
-		if cfg.RampUp == 0 {
-			return float64(cfg.TargetRPS)
-		}
-		return float64(cfg.TargetRPS) * (elapsedSec / float64(cfg.RampUp))
-	}
-	steadyEnd := float64(cfg.RampUp + cfg.SteadyDur)
-	if elapsedSec < steadyEnd {
-		return float64(cfg.TargetRPS)
-	}
-	totalDur := float64(cfg.RampUp + cfg.SteadyDur + cfg.RampDown)
-	if elapsedSec < totalDur {
-		if cfg.RampDown == 0 {
-			return 0
-		}
-		remaining := totalDur - elapsedSec
-		return float64(cfg.TargetRPS) * (remaining / float64(cfg.RampDown))
+	for _, sink := range r.sinks {
+		sink.OnResult(res)
 	}
-	return 0
+
+	return res
 }
 
 func (r *Runner) GetInflight() int64 {
 	return atomic.LoadInt64(&r.Inflight)
 }
 
+// headersHash fingerprints the sent headers (FNV-1a over sorted "Key: value" lines)
+// so exported results can be grouped by what was sent without storing secrets
+func headersHash(h http.Header) string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	f := fnv.New64a()
+	for _, k := range keys {
+		for _, v := range h[k] {
+			fmt.Fprintf(f, "%s: %s\n", k, v)
+		}
+	}
+	return fmt.Sprintf("%016x", f.Sum64())
+}
+
 func cleanError(err error) string {
 	if err == nil {
 		return ""
@@ -0,0 +1,256 @@
+package runner
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The schedulers decide when requests start and hand each one to
+// executeRequest: runUsers for closed-loop virtual users, runRPS for the
+// open-loop pacer (see pacer.go for the strategies).
+
+func (r *Runner) runUsers(ctx context.Context) {
+	var wg sync.WaitGroup
+	start := time.Now()
+	totalDur := time.Duration(r.Cfg.RampUp+r.Cfg.SteadyDur+r.Cfg.RampDown) * time.Second
+
+	// Users stop at iteration boundaries; whatever is still in flight once the
+	// graceful stop period after the end (or a stop) runs out is cancelled
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-time.After(totalDur):
+		case <-finished:
+			return
+		}
+		grace := time.NewTimer(r.Cfg.GetGracefulStop())
+		defer grace.Stop()
+		select {
+		case <-grace.C:
+			r.Abort()
+		case <-finished:
+		}
+	}()
+
+	// Calculate spawn interval for RampUp
+	// If RampUp is 0, we spawn all immediately (interval 0)
+	var spawnInterval time.Duration
+	if r.Cfg.RampUp > 0 && r.Cfg.NumUsers > 1 {
+		// e.g. 10 users over 10s = 1 user per 1s
+		spawnInterval = time.Duration(float64(r.Cfg.RampUp) / float64(r.Cfg.NumUsers) * float64(time.Second))
+	}
+
+	users := r.trackUsers(r.Cfg.NumUsers)
+	defer r.untrackUsers()
+	stuckAfter := r.Cfg.GetStuckAfter()
+	for i := 0; i < r.Cfg.NumUsers; i++ {
+		// Wait before spawning next user if RampUp is active
+		if i > 0 && spawnInterval > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(spawnInterval):
+			}
+		}
+
+		// Ramp-down retires users last-spawned first, each after its current iteration
+		retireAt := start.Add(totalDur)
+		if r.Cfg.RampDown > 0 {
+			rampDown := time.Duration(r.Cfg.RampDown) * time.Second
+			retireAt = start.Add(totalDur - rampDown + rampDown*time.Duration(r.Cfg.NumUsers-i)/time.Duration(r.Cfg.NumUsers))
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Generate STABLE userID for this virtual user
+			vUser := r.rand.UUID()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+					if !r.waitWhilePaused(ctx) || !time.Now().Before(retireAt) || !r.takeRequest() {
+						return
+					}
+					iterStart := time.Now()
+					users.begin(i, iterStart)
+					res := r.executeGroup(iterStart, vUser)
+					users.end(i)
+					if time.Since(iterStart) > stuckAfter {
+						r.Stats.AddStuckIteration()
+					}
+					if res.RetryAfter > 0 {
+						// Server asked this user to back off, the iteration is not counted
+						pauseStart := time.Now()
+						select {
+						case <-ctx.Done():
+						case <-time.After(res.RetryAfter):
+						}
+						r.Stats.AddRetryAfterPause(time.Since(pauseStart))
+						continue
+					}
+					if res.Err == ErrForceCancelled {
+						return
+					}
+					if think := min(r.Cfg.ThinkTime, time.Until(retireAt)); think > 0 {
+						// Retiring or stopping cuts the think time short, the request is done
+						select {
+						case <-ctx.Done():
+						case <-time.After(think):
+						}
+					}
+					r.Stats.AddIteration(time.Since(iterStart))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// executeGroup sends one users-mode iteration: a single request, or with
+// Config.FanOut the requests of a fan-out group concurrently, joined. The
+// group's result carries its longest Retry-After and a force-cancel if any
+// request had one, so the iteration reacts as it would for one request.
+// Config.IterationTimeout bounds the whole group.
+func (r *Runner) executeGroup(iterStart time.Time, vUser string) ExperimentResult {
+	ctx, cancel := r.iterationContext(iterStart)
+	defer cancel()
+	n := r.Cfg.GetFanOut()
+	if n == 1 {
+		return r.executeRequestCtx(ctx, iterStart, vUser)
+	}
+	results := make([]ExperimentResult, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		// The first request already holds the iteration's slot
+		if i > 0 && !r.takeRequest() {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.executeRequestCtx(ctx, iterStart, vUser)
+		}()
+	}
+	wg.Wait()
+	r.Stats.AddGroup(time.Since(iterStart))
+
+	var group ExperimentResult
+	for _, res := range results {
+		group.RetryAfter = max(group.RetryAfter, res.RetryAfter)
+		if res.Err == ErrForceCancelled {
+			group.Err = ErrForceCancelled
+		}
+	}
+	return group
+}
+
+func (r *Runner) runRPS(ctx context.Context) {
+	start := time.Now()
+	totalDur := time.Duration(r.Cfg.RampUp+r.Cfg.SteadyDur+r.Cfg.RampDown) * time.Second
+
+	var wg sync.WaitGroup
+	nextRequestTime := start
+	var period time.Duration
+	p := newPacer(r.Cfg, start, r.rand)
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		default:
+			now := time.Now()
+			elapsed := now.Sub(start).Seconds()
+
+			if elapsed >= totalDur.Seconds() {
+				// Backlog still due at the end was never sent either
+				if behind := start.Add(totalDur).Sub(nextRequestTime); period > 0 && behind > period {
+					r.Stats.AddSchedulerSkipped(uint64(behind / period))
+				}
+				wg.Wait()
+				return
+			}
+
+			targetRPS := r.getCurrentRPS(elapsed)
+			if targetRPS <= 0.001 || r.Paused() {
+				// Nothing to send (or paused): the schedule restarts from now
+				time.Sleep(100 * time.Millisecond)
+				nextRequestTime = time.Now()
+				continue
+			}
+
+			period = time.Duration(float64(time.Second) / targetRPS)
+
+			// If we are way behind (more than maxBehind), skip ahead to avoid a massive burst
+			// But if we are only slightly behind, spawn immediately to catch up.
+			// The requests skipped are load the generator failed to send.
+			if behind := now.Sub(nextRequestTime); behind > p.maxBehind(period) {
+				keep := p.keep(period)
+				r.Stats.AddSchedulerSkipped(uint64((behind - keep) / period))
+				nextRequestTime = now.Add(-keep)
+			}
+
+			// While we are behind the schedule, spawn requests
+			backoff := time.Unix(0, atomic.LoadInt64(&r.backoffUntil))
+			horizon := p.horizon(now)
+			for !nextRequestTime.After(horizon) {
+				if nextRequestTime.Before(backoff) {
+					// Honoring Retry-After: shed instead of sending
+					r.Stats.AddRetryAfterShed()
+					nextRequestTime = nextRequestTime.Add(p.gap(period))
+					continue
+				}
+				if !r.takeRequest() {
+					// Request limit reached, the run ends early
+					wg.Wait()
+					return
+				}
+				wg.Add(1)
+				scheduledTime := nextRequestTime
+				if p.strategy == PacingBatched {
+					// Sending the whole tick at once is the intended schedule, not lag
+					scheduledTime = now
+				}
+				go func() {
+					defer wg.Done()
+					// RPS mode = independent events, fresh userID by default
+					r.executeRequest(scheduledTime, r.rand.UUID())
+				}()
+				nextRequestTime = nextRequestTime.Add(p.gap(period))
+			}
+
+			// Wait for the next one (per strategy, see pacer)
+			p.wait(nextRequestTime, period)
+		}
+	}
+}
+
+func (r *Runner) getCurrentRPS(elapsedSec float64) float64 {
+	cfg := r.Cfg
+	cfg.TargetRPS = r.TargetRate()
+	if elapsedSec < float64(cfg.RampUp) {
+		if cfg.RampUp == 0 {
+			return float64(cfg.TargetRPS)
+		}
+		return float64(cfg.TargetRPS) * (elapsedSec / float64(cfg.RampUp))
+	}
+	steadyEnd := float64(cfg.RampUp + cfg.SteadyDur)
+	if elapsedSec < steadyEnd {
+		return float64(cfg.TargetRPS)
+	}
+	totalDur := float64(cfg.RampUp + cfg.SteadyDur + cfg.RampDown)
+	if elapsedSec < totalDur {
+		if cfg.RampDown == 0 {
+			return 0
+		}
+		remaining := totalDur - elapsedSec
+		return float64(cfg.TargetRPS) * (remaining / float64(cfg.RampDown))
+	}
+	return 0
+}
@@ -0,0 +1,325 @@
+package runner
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Secret references in header values and HTTP URLs: ${env:NAME} is the
+// environment variable NAME, ${secret:name} is looked up by LookupSecret.
+// They are resolved when a run starts, so plans, bundles and run directories
+// keep the reference, never the value; resolved values are masked in results,
+// exports, the dashboard, dry runs and probes (see redactor).
+var secretRef = regexp.MustCompile(`\$\{(env|secret):([A-Za-z0-9_./-]+)\}`)
+
+// Redacted replaces secret values and sensitive header values in output
+const Redacted = "***"
+
+// keychainService is the service name secrets are stored under in the OS keychain
+const keychainService = "steadyq"
+
+// SecretsFile returns the secrets file: $STEADYQ_SECRETS_FILE, else
+// $HOME/.steadyq/secrets
+func SecretsFile() string {
+	if f := os.Getenv("STEADYQ_SECRETS_FILE"); f != "" {
+		return f
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "secrets"
+	}
+	return filepath.Join(home, ".steadyq", "secrets")
+}
+
+// LookupSecret finds a named secret, first in the STEADYQ_SECRET_<NAME>
+// environment variable (name upper-cased, other characters as _), then in
+// SecretsFile ("name=value" lines, must not be readable by group or others),
+// then in the OS keychain under the "steadyq" service (macOS security,
+// Linux secret-tool)
+func LookupSecret(name string) (string, error) {
+	if v, ok := os.LookupEnv(secretEnvVar(name)); ok {
+		return v, nil
+	}
+	if v, ok, err := secretFromFile(SecretsFile(), name); err != nil || ok {
+		return v, err
+	}
+	if v, ok := secretFromKeychain(name); ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("secret %q not found (set %s, add it to %s or the OS keychain)", name, secretEnvVar(name), SecretsFile())
+}
+
+func secretEnvVar(name string) string {
+	return "STEADYQ_SECRET_" + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func secretFromFile(path, name string) (string, bool, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return "", false, fmt.Errorf("%s is readable by others, chmod 600 it", path)
+	}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if k, v, ok := strings.Cut(line, "="); ok && strings.TrimSpace(k) == name {
+			return strings.TrimSpace(v), true, nil
+		}
+	}
+	return "", false, sc.Err()
+}
+
+func secretFromKeychain(name string) (string, bool) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", keychainService, "-a", name, "-w")
+	case "linux":
+		cmd = exec.Command("secret-tool", "lookup", "service", keychainService, "account", name)
+	default:
+		return "", false
+	}
+	if _, err := exec.LookPath(cmd.Path); err != nil {
+		return "", false
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimRight(string(out), "\r\n"), true
+}
+
+// resolveSecrets replaces the secret references in a template text by
+// template string literals of their values, so a value is sent as it is
+// even if it looks like a template, and returns the values
+func resolveSecrets(text string) (string, []string, error) {
+	var values []string
+	var firstErr error
+	out := secretRef.ReplaceAllStringFunc(text, func(ref string) string {
+		m := secretRef.FindStringSubmatch(ref)
+		var v string
+		var err error
+		if m[1] == "env" {
+			var ok bool
+			if v, ok = os.LookupEnv(m[2]); !ok {
+				err = fmt.Errorf("environment variable %s is not set", m[2])
+			}
+		} else {
+			v, err = LookupSecret(m[2])
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return ref
+		}
+		values = append(values, v)
+		return "{{" + strconv.Quote(v) + "}}"
+	})
+	return out, values, firstErr
+}
+
+// CheckSecretRefs resolves the secret references in text, failing on the
+// first that cannot be found
+func CheckSecretRefs(text string) error {
+	_, _, err := resolveSecrets(text)
+	return err
+}
+
+// CheckSecrets resolves the secret references of cfg's headers (header
+// presets included) and URL, failing on the first that cannot be found
+func CheckSecrets(cfg Config) error {
+	headers, err := cfg.ResolveHeaders()
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		if err := CheckSecretRefs(v); err != nil {
+			return fmt.Errorf("header %s: %v", k, err)
+		}
+	}
+	if err := CheckSecretRefs(cfg.URL); err != nil {
+		return fmt.Errorf("URL: %v", err)
+	}
+	return nil
+}
+
+// HeaderPresetsFile returns the file of named header presets,
+// $HOME/.steadyq/headers.json: {"name": {"Header": "value", ...}, ...}
+func HeaderPresetsFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "headers.json"
+	}
+	return filepath.Join(home, ".steadyq", "headers.json")
+}
+
+// LoadHeaderPresets reads HeaderPresetsFile, none when it does not exist
+func LoadHeaderPresets() (map[string]map[string]string, error) {
+	data, err := os.ReadFile(HeaderPresetsFile())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var presets map[string]map[string]string
+	if err := json.Unmarshal(data, &presets); err != nil {
+		return nil, fmt.Errorf("%s: %v", HeaderPresetsFile(), err)
+	}
+	return presets, nil
+}
+
+// ResolveHeaders returns the headers sent: those of HeaderPresets in order,
+// then Headers, later ones replacing earlier ones (case-insensitively)
+func (c Config) ResolveHeaders() (map[string]string, error) {
+	if len(c.HeaderPresets) == 0 {
+		return c.Headers, nil
+	}
+	presets, err := LoadHeaderPresets()
+	if err != nil {
+		return nil, err
+	}
+	headers := make(map[string]string)
+	set := func(k, v string) {
+		for old := range headers {
+			if strings.EqualFold(old, k) {
+				delete(headers, old)
+			}
+		}
+		headers[k] = v
+	}
+	for _, name := range c.HeaderPresets {
+		preset, ok := presets[name]
+		if !ok {
+			return nil, fmt.Errorf("header preset %q not found in %s", name, HeaderPresetsFile())
+		}
+		for k, v := range preset {
+			set(k, v)
+		}
+	}
+	for k, v := range c.Headers {
+		set(k, v)
+	}
+	return headers, nil
+}
+
+// sensitiveHeaders carry credentials: their values are masked in run
+// records even when written out literally instead of as a secret reference
+var sensitiveHeaders = []string{"authorization", "proxy-authorization", "cookie", "x-api-key", "api-key"}
+
+func isSensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	return slices.Contains(sensitiveHeaders, name) || strings.Contains(name, "token") || strings.Contains(name, "secret")
+}
+
+// RedactConfig masks the literal values of credential headers (see
+// sensitiveHeaders) for run records: run directories and bundles. Values
+// with a secret reference or a template ({{readFile "token"}}) are kept,
+// the credential is not in them.
+func RedactConfig(cfg Config) Config {
+	var headers map[string]string
+	for k, v := range cfg.Headers {
+		if !isSensitiveHeader(k) || secretRef.MatchString(v) || strings.Contains(v, "{{") {
+			continue
+		}
+		if headers == nil {
+			headers = make(map[string]string, len(cfg.Headers))
+			for k, v := range cfg.Headers {
+				headers[k] = v
+			}
+		}
+		headers[k] = Redacted
+	}
+	if headers != nil {
+		cfg.Headers = headers
+	}
+	return cfg
+}
+
+// redactor masks resolved secret values
+type redactor struct {
+	r *strings.Replacer
+}
+
+// minSecretLen keeps values too short to be credentials (and too likely to
+// occur by chance) unmasked
+const minSecretLen = 4
+
+func newRedactor(values []string) *redactor {
+	var pairs []string
+	for _, v := range values {
+		if len(v) >= minSecretLen {
+			pairs = append(pairs, v, Redacted)
+		}
+	}
+	if len(pairs) == 0 {
+		return nil
+	}
+	return &redactor{r: strings.NewReplacer(pairs...)}
+}
+
+// String masks the secrets in s; a nil redactor returns s
+func (x *redactor) String(s string) string {
+	if x == nil {
+		return s
+	}
+	return x.r.Replace(s)
+}
+
+// Header returns h with secrets and sensitive header values masked
+func (x *redactor) Header(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for k, vs := range h {
+		for _, v := range vs {
+			if isSensitiveHeader(k) {
+				v = Redacted
+			}
+			out.Add(k, x.String(v))
+		}
+	}
+	return out
+}
+
+// result masks the secrets in what a result records of the request and
+// response. Errors keep their identity unless their message holds a secret.
+func (x *redactor) result(res *ExperimentResult) {
+	if x == nil {
+		return
+	}
+	res.URL = x.String(res.URL)
+	res.ResponseBody = x.String(res.ResponseBody)
+	if res.Err != nil {
+		if msg := res.Err.Error(); x.String(msg) != msg {
+			res.Err = errors.New(x.String(msg))
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package runner
+
+// ResultSink receives a run's output as it is produced. Register sinks with
+// AddSink before Run; they are called in registration order.
+//
+//   - OnResult: every finished request, concurrently from the request goroutines
+//   - OnInterval: a live snapshot every tick (100ms), from the tick loop
+//   - OnComplete: once, after the final snapshot, when Run is about to return
+type ResultSink interface {
+	OnResult(res ExperimentResult)
+	OnInterval(s StatsSnapshot)
+	OnComplete(r *Runner)
+}
+
+// AddSink registers a sink for the next Run
+func (r *Runner) AddSink(s ResultSink) {
+	r.mu.Lock()
+	r.sinks = append(r.sinks, s)
+	r.mu.Unlock()
+}
+
+// SinkFuncs adapts plain functions to a ResultSink; nil fields are skipped
+type SinkFuncs struct {
+	Result   func(res ExperimentResult)
+	Interval func(s StatsSnapshot)
+	Complete func(r *Runner)
+}
+
+func (f SinkFuncs) OnResult(res ExperimentResult) {
+	if f.Result != nil {
+		f.Result(res)
+	}
+}
+
+func (f SinkFuncs) OnInterval(s StatsSnapshot) {
+	if f.Interval != nil {
+		f.Interval(s)
+	}
+}
+
+func (f SinkFuncs) OnComplete(r *Runner) {
+	if f.Complete != nil {
+		f.Complete(r)
+	}
+}
+
+// ChannelSink forwards snapshots to a channel without blocking; snapshots are
+// dropped while the reader is behind (the UI acts as backpressure)
+type ChannelSink StatsUpdateChan
+
+func (c ChannelSink) OnResult(ExperimentResult) {}
+
+func (c ChannelSink) OnInterval(s StatsSnapshot) {
+	select {
+	case c <- s:
+	default:
+	}
+}
+
+func (c ChannelSink) OnComplete(*Runner) {}
+
+// resultStore keeps the run's results for the summary and exports, the
+// oldest dropped once Config.GetMaxResults are held
+type resultStore struct{ r *Runner }
+
+func (s resultStore) OnResult(res ExperimentResult) {
+	s.r.mu.Lock()
+	dropped := s.r.results.push(res)
+	s.r.mu.Unlock()
+	if dropped {
+		s.r.Stats.AddResultDropped()
+	}
+}
+
+func (s resultStore) OnInterval(StatsSnapshot) {}
+
+func (s resultStore) OnComplete(*Runner) {}
+
+// SnapshotResults copies the results kept so far, oldest first; safe to call
+// while a run is active. Stats.ResultsDropped counts those no longer kept.
+func (r *Runner) SnapshotResults() []ExperimentResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.results.snapshot()
+}
+
+// ResultCount is the number of results kept, without copying them
+func (r *Runner) ResultCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.results.buf)
+}
+
+// resultRing holds the latest max results: it grows up to max, then each
+// push overwrites the oldest
+type resultRing struct {
+	buf  []ExperimentResult
+	next int // Oldest result, overwritten by the next push once full
+	max  int
+}
+
+func newResultRing(max int) resultRing {
+	return resultRing{max: max}
+}
+
+// push adds res, reporting whether the oldest result was dropped for it
+func (q *resultRing) push(res ExperimentResult) bool {
+	if len(q.buf) < q.max {
+		q.buf = append(q.buf, res)
+		return false
+	}
+	q.buf[q.next] = res
+	q.next = (q.next + 1) % len(q.buf)
+	return true
+}
+
+// snapshot copies the results, oldest first
+func (q *resultRing) snapshot() []ExperimentResult {
+	out := make([]ExperimentResult, 0, len(q.buf))
+	out = append(out, q.buf[q.next:]...)
+	return append(out, q.buf[:q.next]...)
+}
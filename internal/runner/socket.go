@@ -0,0 +1,95 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// executeSocket performs a raw TCP/UDP exchange: connect, send payload and
+// optionally wait for ReadBytes bytes or ReadDelim.
+// Returns connect time, status (200 on success), received bytes, failure body and error.
+func (r *Runner) executeSocket(ctx context.Context, network, userID, reqID string) (time.Duration, int, int64, string, error) {
+	addr := r.Cfg.URL
+	if idx := strings.Index(addr, "://"); idx != -1 {
+		addr = addr[idx+3:]
+	}
+
+	payload := r.Cfg.Body
+	if r.TmplBody != nil {
+		payload = r.applyTemplates(r.TmplBody, userID, reqID)
+	}
+	payload = unescape(payload)
+
+	dialStart := time.Now()
+	dialer := net.Dialer{Timeout: r.Cfg.GetConnectTimeout()}
+	dial := dialer.DialContext
+	if r.DNS != nil {
+		dial = r.DNS.dialer(dial)
+	}
+	conn, err := dial(ctx, network, addr)
+	connectTime := time.Since(dialStart)
+	if err != nil {
+		return connectTime, 0, 0, "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(dialStart.Add(r.Cfg.GetRequestTimeout()))
+
+	if payload != "" {
+		if _, err := io.WriteString(conn, payload); err != nil {
+			return connectTime, 0, 0, "", err
+		}
+	}
+
+	delim := unescape(r.Cfg.ReadDelim)
+	if r.Cfg.ReadBytes <= 0 && delim == "" {
+		return connectTime, 200, 0, "", nil
+	}
+
+	resp, err := readSocketResponse(conn, r.Cfg.ReadBytes, []byte(delim))
+	if err != nil {
+		return connectTime, 0, int64(len(resp)), string(resp), err
+	}
+	return connectTime, 200, int64(len(resp)), "", nil
+}
+
+// readSocketResponse reads until n bytes were received or delim was seen.
+func readSocketResponse(conn net.Conn, n int, delim []byte) ([]byte, error) {
+	if n > 0 {
+		buf := make([]byte, n)
+		read, err := io.ReadFull(conn, buf)
+		return buf[:read], err
+	}
+
+	var out []byte
+	chunk := make([]byte, 4096)
+	for {
+		read, err := conn.Read(chunk)
+		out = append(out, chunk[:read]...)
+		if bytes.Contains(out, delim) {
+			return out, nil
+		}
+		if err != nil {
+			if err == io.EOF {
+				err = fmt.Errorf("connection closed before delimiter")
+			}
+			return out, err
+		}
+	}
+}
+
+// unescape interprets Go escape sequences (\n, \r, \x00) typed on the command line
+func unescape(s string) string {
+	if s == "" {
+		return s
+	}
+	if out, err := strconv.Unquote(`"` + strings.ReplaceAll(s, `"`, `\"`) + `"`); err == nil {
+		return out
+	}
+	return s
+}
@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// userSlots holds when each virtual user started waiting on its current
+// iteration's requests (UnixNano, 0 = not waiting), so users held by a hung
+// request show as stuck long before the request deadline fails them
+type userSlots []atomic.Int64
+
+func (s userSlots) begin(user int, t time.Time) {
+	s[user].Store(t.UnixNano())
+}
+
+func (s userSlots) end(user int) {
+	s[user].Store(0)
+}
+
+// stuck counts the users waiting since before cutoff
+func (s userSlots) stuck(cutoff time.Time) int64 {
+	var n int64
+	for i := range s {
+		if since := s[i].Load(); since != 0 && since < cutoff.UnixNano() {
+			n++
+		}
+	}
+	return n
+}
+
+// trackUsers sets up the slots of n virtual users for sampleStuck
+func (r *Runner) trackUsers(n int) userSlots {
+	slots := make(userSlots, n)
+	r.mu.Lock()
+	r.users = slots
+	r.mu.Unlock()
+	return slots
+}
+
+// untrackUsers drops the slots once every user has returned
+func (r *Runner) untrackUsers() {
+	r.mu.Lock()
+	r.users = nil
+	r.mu.Unlock()
+	r.Stats.SetStuckUsers(0)
+}
+
+// sampleStuck records how many users have waited on their requests longer
+// than Config.GetStuckAfter; nothing outside users mode
+func (r *Runner) sampleStuck() {
+	r.mu.Lock()
+	slots := r.users
+	r.mu.Unlock()
+	if slots != nil {
+		r.Stats.SetStuckUsers(slots.stuck(time.Now().Add(-r.Cfg.GetStuckAfter())))
+	}
+}
+
+// iterationContext is what an iteration's requests run under: the run's
+// abort context, with Config.IterationTimeout from the iteration's start
+func (r *Runner) iterationContext(iterStart time.Time) (context.Context, context.CancelFunc) {
+	if r.Cfg.IterationTimeout <= 0 {
+		return r.abortCtx, func() {}
+	}
+	return context.WithDeadline(r.abortCtx, iterStart.Add(r.Cfg.IterationTimeout))
+}
@@ -0,0 +1,73 @@
+package runner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StatusSet is a parsed Config.SuccessCodes list of inclusive status ranges.
+// The zero value means the default rule: any 2xx.
+type StatusSet [][2]int
+
+// ParseSuccessCodes parses a comma separated list of codes ("200,202,404"),
+// ranges ("200-299") and classes ("2xx"). Empty means 2xx.
+func ParseSuccessCodes(s string) (StatusSet, error) {
+	var set StatusSet
+	for _, part := range strings.Split(s, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		var lo, hi int
+		var err error
+		switch {
+		case len(part) == 3 && strings.HasSuffix(part, "xx"):
+			lo, err = strconv.Atoi(part[:1])
+			lo *= 100
+			hi = lo + 99
+		case strings.Contains(part, "-"):
+			a, b, _ := strings.Cut(part, "-")
+			if lo, err = strconv.Atoi(strings.TrimSpace(a)); err == nil {
+				hi, err = strconv.Atoi(strings.TrimSpace(b))
+			}
+		default:
+			lo, err = strconv.Atoi(part)
+			hi = lo
+		}
+		if err != nil || lo < 100 || hi > 599 || lo > hi {
+			return nil, fmt.Errorf("invalid status code %q (use e.g. 200,202,404 or 200-299 or 2xx)", part)
+		}
+		set = append(set, [2]int{lo, hi})
+	}
+	return set, nil
+}
+
+// Contains reports whether status counts as success
+func (s StatusSet) Contains(status int) bool {
+	if len(s) == 0 {
+		return status >= 200 && status < 300
+	}
+	for _, r := range s {
+		if status >= r[0] && status <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders the set as accepted by ParseSuccessCodes
+func (s StatusSet) String() string {
+	if len(s) == 0 {
+		return "2xx"
+	}
+	parts := make([]string, len(s))
+	for i, r := range s {
+		if r[0] == r[1] {
+			parts[i] = strconv.Itoa(r[0])
+		} else {
+			parts[i] = fmt.Sprintf("%d-%d", r[0], r[1])
+		}
+	}
+	return strings.Join(parts, ",")
+}
@@ -4,13 +4,24 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
-	"math/rand"
 	"os"
 	"strings"
 	"sync"
 	"text/template"
+	"time"
+)
 
-	"github.com/google/uuid"
+// Sample data for randomName / randomEmail
+var (
+	firstNames = []string{
+		"James", "Mary", "Wei", "Fatima", "Olga", "Carlos", "Aiko", "Amara", "Liam", "Priya",
+		"Noah", "Sofia", "Mateo", "Chloe", "Yusuf", "Elena", "Kwame", "Hana", "Lucas", "Ines",
+	}
+	lastNames = []string{
+		"Smith", "Garcia", "Chen", "Khan", "Ivanova", "Silva", "Tanaka", "Okafor", "Murphy", "Patel",
+		"Weber", "Rossi", "Kim", "Nguyen", "Haddad", "Novak", "Mensah", "Larsen", "Dubois", "Costa",
+	}
+	emailDomains = []string{"example.com", "example.org", "example.net", "test.example"}
 )
 
 // TemplateEngine handles parsing and executing templates
@@ -19,6 +30,11 @@ type TemplateEngine struct {
 	rawCache  map[string]string // Cache for raw file contents
 	mu        sync.RWMutex
 	funcMap   template.FuncMap
+	rand      *lockedRand
+
+	// Named per-run sequences for {{sequence "name"}} and {{counter}}
+	seqMu     sync.Mutex
+	sequences map[string]uint64
 }
 
 // TemplateData is passed to the execution context
@@ -27,11 +43,14 @@ type TemplateData struct {
 	UUID   string
 }
 
-// NewTemplateEngine initializes the engine and its functions
-func NewTemplateEngine() *TemplateEngine {
+// NewTemplateEngine initializes the engine and its functions.
+// Random functions draw from a source seeded with seed.
+func NewTemplateEngine(seed int64) *TemplateEngine {
 	e := &TemplateEngine{
 		fileCache: make(map[string][]string),
 		rawCache:  make(map[string]string),
+		rand:      newLockedRand(seed),
+		sequences: make(map[string]uint64),
 	}
 
 	e.funcMap = template.FuncMap{
@@ -42,6 +61,11 @@ func NewTemplateEngine() *TemplateEngine {
 		"readFile":     e.readFile,
 		"printf":       fmt.Sprintf,
 		"uuid":         e.randomUUID, // Alias
+		"randomName":   e.randomName,
+		"randomEmail":  e.randomEmail,
+		"randomDate":   e.randomDate,
+		"sequence":     e.sequence,
+		"counter":      e.counter,
 	}
 
 	return e
@@ -77,18 +101,62 @@ func (e *TemplateEngine) Execute(t *template.Template, data TemplateData) (strin
 // --- Functions ---
 
 func (e *TemplateEngine) randomInt(min, max int) int {
-	return rand.Intn(max-min) + min
+	return e.rand.Intn(max-min) + min
 }
 
 func (e *TemplateEngine) randomUUID() string {
-	return uuid.New().String()
+	return e.rand.UUID()
 }
 
 func (e *TemplateEngine) randomChoice(choices ...string) string {
 	if len(choices) == 0 {
 		return ""
 	}
-	return choices[rand.Intn(len(choices))]
+	return choices[e.rand.Intn(len(choices))]
+}
+
+func (e *TemplateEngine) randomName() string {
+	return firstNames[e.rand.Intn(len(firstNames))] + " " + lastNames[e.rand.Intn(len(lastNames))]
+}
+
+func (e *TemplateEngine) randomEmail() string {
+	first := strings.ToLower(firstNames[e.rand.Intn(len(firstNames))])
+	last := strings.ToLower(lastNames[e.rand.Intn(len(lastNames))])
+	return fmt.Sprintf("%s.%s%d@%s", first, last, e.rand.Intn(1000), emailDomains[e.rand.Intn(len(emailDomains))])
+}
+
+// randomDate returns an RFC 3339 time within the last span (e.g. "2h", "720h"),
+// or within the next span when it is negative
+func (e *TemplateEngine) randomDate(span string) (string, error) {
+	d, err := time.ParseDuration(span)
+	if err != nil {
+		return "", fmt.Errorf("randomDate: %w", err)
+	}
+	if d == 0 {
+		return time.Now().UTC().Format(time.RFC3339), nil
+	}
+	future := d < 0
+	if future {
+		d = -d
+	}
+	offset := time.Duration(e.rand.Int63n(int64(d)))
+	if !future {
+		offset = -offset
+	}
+	return time.Now().Add(offset).UTC().Format(time.RFC3339), nil
+}
+
+// sequence returns the next value (from 1) of a named per-run counter
+func (e *TemplateEngine) sequence(name string) uint64 {
+	e.seqMu.Lock()
+	defer e.seqMu.Unlock()
+	e.sequences[name]++
+	return e.sequences[name]
+}
+
+// counter is the run's default sequence, incremented on every use
+func (e *TemplateEngine) counter() uint64 {
+	return e.sequence("")
 }
 
 func (e *TemplateEngine) randomLine(filename string) (string, error) {
@@ -100,7 +168,7 @@ func (e *TemplateEngine) randomLine(filename string) (string, error) {
 		if len(lines) == 0 {
 			return "", nil
 		}
-		return lines[rand.Intn(len(lines))], nil
+		return lines[e.rand.Intn(len(lines))], nil
 	}
 
 	// Load file (Lazy load)
@@ -112,7 +180,7 @@ func (e *TemplateEngine) randomLine(filename string) (string, error) {
 		if len(lines) == 0 {
 			return "", nil
 		}
-		return lines[rand.Intn(len(lines))], nil
+		return lines[e.rand.Intn(len(lines))], nil
 	}
 
 	content, err := os.ReadFile(filename)
@@ -134,7 +202,7 @@ func (e *TemplateEngine) randomLine(filename string) (string, error) {
 		return "", nil
 	}
 
-	return loaded[rand.Intn(len(loaded))], nil
+	return loaded[e.rand.Intn(len(loaded))], nil
 }
 
 func (e *TemplateEngine) readFile(filename string) (string, error) {
@@ -0,0 +1,149 @@
+package runner
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ThresholdMetrics are the metrics a Threshold can target. Latencies are the
+// service times of successful requests in ms, error_rate is a percent of all
+// requests and rps the achieved requests per second.
+var ThresholdMetrics = []string{"p50", "p90", "p95", "p99", "mean", "trimmed_mean", "stddev", "max", "error_rate", "rps"}
+
+// Threshold is a pass/fail target on a run metric, e.g. "p99<500ms",
+// "error_rate<1%" or "rps>=100". The dashboard evaluates it live, the CLI
+// once more against the final summary.
+type Threshold struct {
+	Metric string  // One of ThresholdMetrics
+	Op     string  // "<", "<=", ">" or ">="
+	Limit  float64 // ms for latencies, percent for error_rate, req/s for rps
+}
+
+// ParseThreshold reads "<metric><op><limit>". Latency limits are durations
+// ("500ms", "1.5s") or plain milliseconds; error_rate takes an optional %.
+func ParseThreshold(spec string) (Threshold, error) {
+	s := strings.ReplaceAll(spec, " ", "")
+	i := strings.IndexAny(s, "<>")
+	if i <= 0 {
+		return Threshold{}, fmt.Errorf("threshold %q: expected <metric><op><limit>, e.g. p99<500ms", spec)
+	}
+	t := Threshold{Metric: strings.ToLower(s[:i]), Op: s[i : i+1]}
+	rest := s[i+1:]
+	if strings.HasPrefix(rest, "=") {
+		t.Op += "="
+		rest = rest[1:]
+	}
+	if !slices.Contains(ThresholdMetrics, t.Metric) {
+		return Threshold{}, fmt.Errorf("threshold %q: unknown metric %q (expected %s)", spec, t.Metric, strings.Join(ThresholdMetrics, ", "))
+	}
+
+	var err error
+	switch t.Metric {
+	case "error_rate":
+		t.Limit, err = strconv.ParseFloat(strings.TrimSuffix(rest, "%"), 64)
+	case "rps":
+		t.Limit, err = strconv.ParseFloat(rest, 64)
+	default:
+		var d time.Duration
+		if d, err = time.ParseDuration(rest); err == nil {
+			t.Limit = float64(d.Microseconds()) / 1000
+		} else {
+			t.Limit, err = strconv.ParseFloat(rest, 64)
+		}
+	}
+	if err != nil || t.Limit < 0 {
+		return Threshold{}, fmt.Errorf("threshold %q: invalid limit %q", spec, rest)
+	}
+	return t, nil
+}
+
+// ParseThresholds parses every spec, failing on the first invalid one
+func ParseThresholds(specs []string) ([]Threshold, error) {
+	var ths []Threshold
+	for _, spec := range specs {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		t, err := ParseThreshold(spec)
+		if err != nil {
+			return nil, err
+		}
+		ths = append(ths, t)
+	}
+	return ths, nil
+}
+
+// GetThresholds returns the configured thresholds, skipping invalid ones
+// (the CLI and the run form reject them before a run starts)
+func (c Config) GetThresholds() []Threshold {
+	var ths []Threshold
+	for _, spec := range c.Thresholds {
+		if t, err := ParseThreshold(spec); err == nil {
+			ths = append(ths, t)
+		}
+	}
+	return ths
+}
+
+// Pass reports whether the observed value v meets the threshold
+func (t Threshold) Pass(v float64) bool {
+	switch t.Op {
+	case "<":
+		return v < t.Limit
+	case "<=":
+		return v <= t.Limit
+	case ">":
+		return v > t.Limit
+	}
+	return v >= t.Limit
+}
+
+// Observe returns the live value of the threshold's metric in a snapshot
+// taken elapsed into the run; false until there is anything to judge
+func (t Threshold) Observe(s StatsSnapshot, elapsed time.Duration) (float64, bool) {
+	if s.Requests == 0 {
+		return 0, false
+	}
+	switch t.Metric {
+	case "p50":
+		return s.P50ServiceMs, true
+	case "p90":
+		return s.P90ServiceMs, true
+	case "p95":
+		return s.P95ServiceMs, true
+	case "p99":
+		return s.P99ServiceMs, true
+	case "mean":
+		return s.MeanServiceMs, true
+	case "trimmed_mean":
+		return s.TrimmedMeanServiceMs, true
+	case "stddev":
+		return s.StdDevServiceMs, true
+	case "max":
+		return float64(s.MaxServiceMs), true
+	case "error_rate":
+		return float64(s.Fail) / float64(s.Requests) * 100, true
+	case "rps":
+		if elapsed <= 0 {
+			return 0, false
+		}
+		return float64(s.Requests) / elapsed.Seconds(), true
+	}
+	return 0, false
+}
+
+// String formats the threshold like its spec, e.g. "p99 < 500ms"
+func (t Threshold) String() string {
+	limit := strconv.FormatFloat(t.Limit, 'f', -1, 64)
+	switch t.Metric {
+	case "error_rate":
+		limit += "%"
+	case "rps":
+	default:
+		limit += "ms"
+	}
+	return fmt.Sprintf("%s %s %s", t.Metric, t.Op, limit)
+}
@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// tlsConfig builds the client TLS settings: certificates are verified against
+// the system roots plus Config.CAFile, unless Config.Insecure opts out
+func tlsConfig(cfg Config) *tls.Config {
+	tc := &tls.Config{
+		ServerName:         cfg.GetServerName(),
+		InsecureSkipVerify: cfg.Insecure,
+	}
+	if !cfg.NoTLSResume {
+		// New connections resume sessions like browsers do
+		tc.ClientSessionCache = tls.NewLRUClientSessionCache(0)
+	}
+	if cfg.CAFile != "" && !cfg.Insecure {
+		pool, err := LoadCAFile(cfg.CAFile)
+		if err != nil {
+			setupError("loading CA file", err)
+		} else {
+			tc.RootCAs = pool
+		}
+	}
+	return tc
+}
+
+// LoadCAFile returns the system roots plus the PEM certificates in filename
+func LoadCAFile(filename string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%s: no PEM certificates found", filename)
+	}
+	return pool, nil
+}
+
+// tlsVerifyError labels a certificate verification failure for the error
+// breakdown, "" for any other error
+func tlsVerifyError(err error) string {
+	if err == nil {
+		return ""
+	}
+	var unknownCA x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	switch {
+	case errors.As(err, &unknownCA):
+		return "tls verify: certificate signed by unknown authority (use --ca-file, or --insecure)"
+	case errors.As(err, &hostname):
+		return fmt.Sprintf("tls verify: certificate is not valid for %s (check --sni)", hostname.Host)
+	case errors.As(err, &invalid):
+		return "tls verify: " + invalid.Error()
+	}
+	var verify *tls.CertificateVerificationError
+	if errors.As(err, &verify) {
+		return "tls verify: " + verify.Err.Error()
+	}
+	return ""
+}
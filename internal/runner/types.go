@@ -1,19 +1,101 @@
 package runner
 
 import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
-	URL        string
-	Method     string // HTTP Method
-	Body       string // Request Body
-	Headers    map[string]string
-	TargetRPS  int
-	SteadyDur  int
-	RampUp     int
-	RampDown   int
-	TimeoutSec int
+	URL       string
+	Method    string // HTTP Method
+	Body      string // Request Body
+	Headers   map[string]string
+	TargetRPS int
+	SteadyDur int
+	RampUp    int
+	RampDown  int
+
+	// Body directory: each request sends one file of BodyDir (templated like Body),
+	// picked at random by BodyWeights (file name -> weight, default 1, 0 excludes)
+	BodyDir     string
+	BodyWeights map[string]int
+
+	// Named header sets from HeaderPresetsFile, applied in order under Headers.
+	// Header values and the HTTP URL may reference ${env:NAME} and
+	// ${secret:name} (see LookupSecret), resolved when the run starts.
+	HeaderPresets []string
+
+	// Labels for slicing results, carried into every ExperimentResult and export
+	Label string            // Request label (default "SteadyQ Request")
+	Tags  map[string]string // Arbitrary dimensions, e.g. env=staging, build=1.4.2
+
+	// Random seed for template functions, the Redis mix, Poisson pacing and user IDs (request IDs are never seeded).
+	// 0 = a new seed per run (reported in the summary so the run can be repeated).
+	Seed int64
+
+	// Free-form run metadata (e.g. ticket=PERF-12), recorded with host/git details in RunMetadata
+	Metadata map[string]string
+
+	// Timeouts (0 = default)
+	ConnectTimeout        time.Duration // TCP dial (default: RequestTimeout)
+	TLSHandshakeTimeout   time.Duration // TLS handshake (default 10s)
+	ResponseHeaderTimeout time.Duration // Wait for response headers once the request is written (default: none)
+	RequestTimeout        time.Duration // Overall per-request deadline (default 30s)
+
+	// Header carrying a unique ID per request (the {{requestID}} value), e.g.
+	// "X-Request-ID", kept in ExperimentResult.RequestID for log correlation
+	RequestIDHeader string
+
+	// Cache revalidation: replay ETag/Last-Modified of earlier responses as
+	// If-None-Match/If-Modified-Since; a 304 to such a request is a success
+	Conditional bool
+
+	// Client identity rotation: "builtin" or a file of User-Agents (see
+	// LoadFingerprints); each virtual user keeps one for the run
+	Fingerprints string
+
+	// Routing overrides, independent of the URL (shared ingress, blue/green cutovers).
+	// The Host header is overridden with a "Host" entry in Headers.
+	ConnectTo  string // Dial this host[:port] instead of the URL's host (port defaults to the URL's)
+	ServerName string // TLS SNI (default: the Host header if set, else the URL host)
+
+	// Shadow traffic: every HTTP request is also sent to this base URL, keeping
+	// path, query, headers and body, and compared in the summary (see mirror)
+	Mirror string
+
+	// DNS pinning (see DNSPin): resolve hosts at run start and dial those IPs
+	PinDNS bool
+	DNSTTL time.Duration // Re-resolve this often, logging changed answers (0 = never; implies PinDNS)
+
+	// TLS verification: on by default against the system roots
+	CAFile      string // Extra PEM CA bundle trusted in addition to the system roots
+	Insecure    bool   // Skip certificate verification entirely
+	NoTLSResume bool   // Full handshake on every new connection (no session cache)
+
+	// Open-Loop Pacing (see PacingStrategies)
+	Pacing      string        // "constant" (default), "poisson", "batched", "token-bucket"
+	PacingBurst int           // token-bucket: max catch-up burst (default 10)
+	PacingTick  time.Duration // batched: send interval (default 100ms)
+
+	// Concurrency Limits
+	MaxConns int // Max connections per host (default 2000)
+
+	// HTTP client library: "net/http" (default) or "fasthttp", for rates
+	// where net/http is the bottleneck; see FastHTTPUnsupported for what it
+	// cannot do
+	HTTPEngine string
+
+	// Egress cap on HTTP request bodies for this run, e.g. "50Mbit" or "2MB/s" (see ParseBandwidth).
+	// SetGlobalBandwidth caps all concurrent runs together.
+	Bandwidth string
+
+	// Emulated client link: a NetworkProfiles name ("3g", "adsl", ...) or
+	// "latency=200ms,jitter=50ms,down=2Mbit,up=512kbit" (see ParseNetworkProfile)
+	NetworkProfile string
 
 	// Open-Loop (RPS) vs Closed-Loop (Users)
 	// Open-Loop (RPS) vs Closed-Loop (Users)
@@ -21,17 +103,110 @@ type Config struct {
 	NumUsers  int           // For "users" mode
 	ThinkTime time.Duration // For "users" mode
 
+	// Users mode: requests each iteration sends concurrently and joins before
+	// the think time, like a screen that fans out several API calls (default 1)
+	FanOut int
+
+	// Stop after sending this many requests (0 = run for the full duration)
+	MaxRequests int
+
+	// Results kept in memory for the summary and exports (default
+	// DefaultMaxResults); once full the oldest are dropped and counted
+	MaxResults int
+
+	// Users mode: how long in-flight iterations may run past the end of the
+	// test (or a stop) before their requests are cancelled (default 30s)
+	GracefulStop time.Duration
+
+	// Users mode: deadline for each iteration's requests, so a hung request
+	// holds its user this long instead of the whole RequestTimeout (0 = off).
+	// Users waiting on a request longer than StuckAfter (default 5s) count as
+	// stuck, a health metric apart from errors.
+	IterationTimeout time.Duration
+	StuckAfter       time.Duration
+
+	// Early stop: end the run when the error rate (%) or P99 stays above these
+	// for AbortAfter (default 10s), or once MaxDuration has elapsed (0 = off)
+	AbortErrorRate float64
+	AbortP99       time.Duration
+	AbortAfter     time.Duration
+	MaxDuration    time.Duration
+
+	// Pass/fail targets, e.g. "p99<500ms" or "error_rate<1%" (see ParseThreshold);
+	// shown live on the dashboard and checked against the final summary
+	Thresholds []string
+
+	// Service time percentiles computed, shown and exported, e.g. 50, 99, 99.9
+	// (default DefaultPercentiles)
+	Percentiles []float64
+
 	// Custom Scripting
 	Command string // Shell command to execute per request (overrides URL/Method)
 
+	// Protocol selects how each request is executed: "http" (default), "tcp", "udp", "redis", "kafka", "sql".
+	// If empty, it is inferred from the URL scheme (e.g. tcp://host:9000).
+	Protocol string
+
+	// Raw Socket (TCP/UDP)
+	ReadBytes int    // Wait for N response bytes after sending (0 = don't wait)
+	ReadDelim string // Wait until this delimiter is received (e.g. "\n")
+
+	// Redis
+	RedisCommands []string // Weighted command mix, "[weight:]COMMAND args" (templated)
+
+	// Kafka (URL: kafka://broker1:9092,broker2:9092/topic, Body = message value)
+	KafkaAcks string // "all" (default), "1" or "0"
+	KafkaKey  string // Message key template (optional)
+
+	// SQL (URL: postgres://... or mysql://..., Body = query)
+	SQLArgs     []string // Query arguments bound to placeholders (templated)
+	SQLMaxConns int      // Max open connections (0 = unlimited)
+
+	// Preflight: one request before the run, abort if it fails
+	Preflight    bool
+	PreflightURL string // Optional probe URL (GET) instead of the configured request; implies Preflight
+
+	// Success Criteria: status codes counted as success, e.g. "200,202,404" or "200-299,409".
+	// Empty = any 2xx. Non-HTTP protocols report 200 on success.
+	SuccessCodes string
+
+	// Rate Limiting: on 429/503 with Retry-After, pause that user (users mode)
+	// or stop sending for that long (rps mode) and report the shed load
+	HonorRetryAfter bool
+
+	// Client-side Circuit Breaker (disabled when BreakerErrorRate is 0)
+	BreakerErrorRate   float64       // Error ratio (0-1) in the window that opens the circuit
+	BreakerMinRequests int           // Minimum requests in the window before it can trip (default 20)
+	BreakerWindow      time.Duration // Rolling window for the error rate (default 10s)
+	BreakerCooldown    time.Duration // Time spent open before half-open probes (default 5s)
+	BreakerProbes      int           // Successful probes required to close again (default 1)
+
+	// Target Monitoring: scrape the target's CPU/memory during the run
+	Monitor         string        // http(s)://host:9100/metrics (node_exporter) or ssh://user@host
+	MonitorInterval time.Duration // Sampling interval (default 2s)
+
 	// Reporting
-	OutPrefix string // Prefix for auto-report generation
+	Name      string // Run name, used by the {{name}} report template placeholder
+	OutDir    string // Directory for generated reports (default: working directory)
+	OutPrefix string // Prefix for auto-report generation (may use {{date}}, {{name}}, {{target}})
+	Bundle    bool   // Also write <OutPrefix>.zip with every artifact
+	UploadTo  string // s3://bucket/prefix or gs://bucket/prefix for generated reports
+	RunsDir   string // Parent of per-run directories <RunsDir>/<run ID>/ with config, log and reports
+	History   string // JSON Lines file the run is appended to, with a note and verdict asked for in a terminal
+
+	// Team-shared history the History entry is also pushed to: https://host/path
+	// (token in STEADYQ_HISTORY_TOKEN), s3://bucket/prefix or gs://bucket/prefix
+	HistoryRemote string
+
+	// Interval snapshots: histogram + counters every SnapshotInterval (default 1s), for replay
+	SnapshotInterval time.Duration
 }
 
 type ExperimentResult struct {
 	TimeStamp    time.Time
 	Latency      time.Duration // Total Time
 	ServiceTime  time.Duration // Network/Server Time
+	ConnectTime  time.Duration // Dial Time (socket modes, new HTTP connections)
 	QueueWait    time.Duration // Schedule Lag
 	Status       int
 	Success      bool
@@ -40,4 +215,204 @@ type ExperimentResult struct {
 	Query        string
 	Err          error
 	ResponseBody string
+	RetryAfter   time.Duration // Server-requested backoff (429/503 Retry-After)
+	URL          string        // Request URL after templating
+	SentBytes    int64         // Request body size
+	Method       string        // HTTP method sent
+	HeadersHash  string        // Fingerprint of the sent headers (see headersHash)
+	Attempt      int           // 1 for the first try of a request
+	RemoteAddr   string        // Peer address the request was sent to
+	Label        string        // Config.Label
+	Tags         map[string]string
+	RequestID    string   `json:",omitempty"` // Value of Config.RequestIDHeader sent
+	Conditional  bool     `json:",omitempty"` // Revalidation with If-None-Match/If-Modified-Since (Config.Conditional)
+	NewConn      bool     `json:",omitempty"` // HTTP: a connection was opened for this request
+	Reused       bool     `json:",omitempty"` // HTTP: sent on a pooled keep-alive connection
+	TLS          *TLSInfo `json:",omitempty"` // HTTPS: the connection's TLS session
+
+	// HTTP: request line and headers sent, status line and headers received
+	SentHeaderBytes int64 `json:",omitempty"`
+	RecvHeaderBytes int64 `json:",omitempty"`
+}
+
+// TLSInfo is the TLS session a request went out on. Resumed and Handshake
+// are only set for the request that opened the connection.
+type TLSInfo struct {
+	Version   string
+	Cipher    string
+	Resumed   bool          `json:",omitempty"` // Abbreviated handshake from a cached session
+	Handshake time.Duration `json:",omitempty"`
+}
+
+// GetProtocol returns the configured protocol, falling back to the URL scheme.
+func (c Config) GetProtocol() string {
+	if c.Protocol != "" {
+		return c.Protocol
+	}
+	if idx := strings.Index(c.URL, "://"); idx != -1 {
+		switch scheme := strings.ToLower(c.URL[:idx]); scheme {
+		case "tcp", "udp":
+			return scheme
+		case "redis", "rediss":
+			return "redis"
+		case "kafka":
+			return "kafka"
+		case "postgres", "postgresql", "mysql":
+			return "sql"
+		}
+	}
+	return "http"
+}
+
+// GetServerName returns the TLS SNI override: ServerName, else the Host
+// header if set, else "" (the URL host)
+func (c Config) GetServerName() string {
+	if c.ServerName != "" {
+		return c.ServerName
+	}
+	for k, v := range c.Headers {
+		if strings.EqualFold(k, "Host") && !strings.Contains(v, "{{") {
+			if host, _, err := net.SplitHostPort(v); err == nil {
+				return host
+			}
+			return v
+		}
+	}
+	return ""
+}
+
+// connectAddr applies a ConnectTo override to a dial address, keeping its
+// port when the override has none
+func connectAddr(connectTo, addr string) string {
+	if _, _, err := net.SplitHostPort(connectTo); err == nil {
+		return connectTo
+	}
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return connectTo
+	}
+	return net.JoinHostPort(strings.Trim(connectTo, "[]"), port)
+}
+
+// PinsDNS reports whether hosts are resolved once and pinned (see DNSPin)
+func (c Config) PinsDNS() bool {
+	return c.PinDNS || c.DNSTTL > 0
+}
+
+// DefaultLabel is the request label when Config.Label is empty
+const DefaultLabel = "SteadyQ Request"
+
+// GetLabel returns the request label, DefaultLabel when unset
+func (c Config) GetLabel() string {
+	if c.Label != "" {
+		return c.Label
+	}
+	return DefaultLabel
+}
+
+// GetRequestTimeout returns the overall per-request deadline
+func (c Config) GetRequestTimeout() time.Duration {
+	if c.RequestTimeout > 0 {
+		return c.RequestTimeout
+	}
+	return 30 * time.Second
+}
+
+// GetAbortAfter returns how long an abort condition must hold before the run is stopped
+func (c Config) GetAbortAfter() time.Duration {
+	if c.AbortAfter >= time.Second {
+		return c.AbortAfter
+	}
+	return 10 * time.Second
+}
+
+// GetFanOut returns the requests sent per users-mode iteration
+func (c Config) GetFanOut() int {
+	if c.FanOut > 1 {
+		return c.FanOut
+	}
+	return 1
+}
+
+// DefaultPercentiles are the percentiles reported when Config.Percentiles is empty
+var DefaultPercentiles = []float64{50, 90, 95, 99}
+
+// GetPercentiles returns the configured percentiles, DefaultPercentiles when unset
+func (c Config) GetPercentiles() []float64 {
+	if len(c.Percentiles) > 0 {
+		return c.Percentiles
+	}
+	return DefaultPercentiles
+}
+
+// CheckPercentiles rejects percentiles outside (0, 100]
+func CheckPercentiles(ps []float64) error {
+	for _, p := range ps {
+		if p <= 0 || p > 100 {
+			return fmt.Errorf("percentile %g is not in (0, 100]", p)
+		}
+	}
+	return nil
+}
+
+// GetGracefulStop returns how long users mode waits for in-flight iterations at the end
+func (c Config) GetGracefulStop() time.Duration {
+	if c.GracefulStop > 0 {
+		return c.GracefulStop
+	}
+	return 30 * time.Second
+}
+
+// GetHTTPEngine returns the HTTP client library used (EngineNetHTTP by default)
+func (c Config) GetHTTPEngine() string {
+	if c.HTTPEngine != "" {
+		return c.HTTPEngine
+	}
+	return EngineNetHTTP
+}
+
+// DefaultMaxResults bounds the results of a run kept in memory, a few
+// hundred MB at most
+const DefaultMaxResults = 1_000_000
+
+// GetMaxResults returns how many results a run keeps in memory
+func (c Config) GetMaxResults() int {
+	if c.MaxResults > 0 {
+		return c.MaxResults
+	}
+	return DefaultMaxResults
+}
+
+// GetStuckAfter returns how long a user may wait on a request before it counts as stuck
+func (c Config) GetStuckAfter() time.Duration {
+	if c.StuckAfter > 0 {
+		return c.StuckAfter
+	}
+	return 5 * time.Second
+}
+
+// GetConnectTimeout returns the dial timeout, never longer than the request deadline
+func (c Config) GetConnectTimeout() time.Duration {
+	if c.ConnectTimeout > 0 {
+		return c.ConnectTimeout
+	}
+	return c.GetRequestTimeout()
+}
+
+// parseRetryAfter reads a Retry-After header (delay-seconds or HTTP-date)
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
 }
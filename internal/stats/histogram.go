@@ -1,6 +1,9 @@
 package stats
 
 import (
+	"fmt"
+	"io"
+	"math"
 	"sync"
 	"time"
 
@@ -38,6 +41,48 @@ func (h *SafeHistogram) Mean() float64 {
 	return h.hist.Mean()
 }
 
+func (h *SafeHistogram) StdDev() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.hist.StdDev()
+}
+
+func (h *SafeHistogram) Min() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.hist.Min()
+}
+
+// TrimPercent is the share (%) of the fastest and of the slowest values a
+// trimmed mean leaves out
+const TrimPercent = 1.0
+
+// TrimmedMean is the mean of the values left after dropping the lowest and
+// the highest TrimPercent, so a few outliers cannot drag it
+func (h *SafeHistogram) TrimmedMean() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	total := h.hist.TotalCount()
+	drop := int64(float64(total) * TrimPercent / 100)
+	lo, hi := drop, total-drop // Keep ranks [lo, hi)
+	if hi <= lo {
+		return h.hist.Mean()
+	}
+	var rank int64
+	sum := 0.0
+	for _, bar := range h.hist.Distribution() {
+		if bar.Count == 0 {
+			continue
+		}
+		n := min(rank+bar.Count, hi) - max(rank, lo)
+		if n > 0 {
+			sum += float64(n) * float64(bar.From+bar.To) / 2
+		}
+		rank += bar.Count
+	}
+	return sum / float64(hi-lo)
+}
+
 func (h *SafeHistogram) Max() int64 {
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -49,3 +94,92 @@ func (h *SafeHistogram) TotalCount() int64 {
 	defer h.mu.Unlock()
 	return h.hist.TotalCount()
 }
+
+// Buckets counts recorded values into ranges split at bounds (ascending, microseconds).
+// The result has len(bounds)+1 entries, the last one holding values >= the final bound.
+func (h *SafeHistogram) Buckets(bounds []int64) []int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make([]int64, len(bounds)+1)
+	for _, bar := range h.hist.Distribution() {
+		if bar.Count == 0 {
+			continue
+		}
+		i := 0
+		for i < len(bounds) && bar.From >= bounds[i] {
+			i++
+		}
+		out[i] += bar.Count
+	}
+	return out
+}
+
+// Counts returns the non-empty buckets in ascending order: the highest value
+// each holds (microseconds, what ValueAtQuantile reports) and its count
+func (h *SafeHistogram) Counts() (values, counts []int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, bar := range h.hist.Distribution() {
+		if bar.Count > 0 {
+			values = append(values, bar.To)
+			counts = append(counts, bar.Count)
+		}
+	}
+	return values, counts
+}
+
+// WritePercentiles writes the distribution in the HdrHistogram .hgrm text format (values in ms),
+// readable by the standard HdrHistogram plotter.
+func (h *SafeHistogram) WritePercentiles(w io.Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, err := fmt.Fprintf(w, "%12s %14s %10s %14s\n\n", "Value", "Percentile", "TotalCount", "1/(1-Percentile)"); err != nil {
+		return err
+	}
+	for _, b := range h.hist.CumulativeDistributionWithTicks(5) {
+		q := b.Quantile / 100
+		inv := math.Inf(1)
+		if q < 1 {
+			inv = 1 / (1 - q)
+		}
+		if _, err := fmt.Fprintf(w, "%12.3f %2.12f %10d %14.2f\n", float64(b.ValueAt)/1000, q, b.Count, inv); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "#[Mean    = %12.3f, StdDeviation   = %12.3f]\n#[Max     = %12.3f, Total count    = %12d]\n",
+		h.hist.Mean()/1000, h.hist.StdDev()/1000, float64(h.hist.Max())/1000, h.hist.TotalCount())
+	return err
+}
+
+// Merge adds every value recorded in other to h
+func (h *SafeHistogram) Merge(other *SafeHistogram) {
+	other.mu.Lock()
+	snap := other.hist.Export()
+	other.mu.Unlock()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hist.Merge(hdrhistogram.Import(snap))
+}
+
+// Encode returns the histogram in the HdrHistogram V2 compressed format, base64 encoded
+// (the payload of a .hlog line), so it can be decoded by any HdrHistogram implementation.
+func (h *SafeHistogram) Encode() (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	buf, err := h.hist.Encode(hdrhistogram.V2CompressedEncodingCookieBase)
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// DecodeHistogram parses a histogram produced by Encode
+func DecodeHistogram(encoded string) (*SafeHistogram, error) {
+	hist, err := hdrhistogram.Decode([]byte(encoded))
+	if err != nil {
+		return nil, err
+	}
+	return &SafeHistogram{hist: hist}, nil
+}
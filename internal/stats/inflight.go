@@ -0,0 +1,65 @@
+package stats
+
+// inflightSamples accumulates samples of the in-flight request count
+type inflightSamples struct {
+	sum, n, max int64
+}
+
+func (s *inflightSamples) add(v int64) {
+	s.sum += v
+	s.n++
+	s.max = max(s.max, v)
+}
+
+func (s *inflightSamples) merge(o inflightSamples) {
+	s.sum += o.sum
+	s.n += o.n
+	s.max = max(s.max, o.max)
+}
+
+func (s inflightSamples) avg() float64 {
+	if s.n == 0 {
+		return 0
+	}
+	return float64(s.sum) / float64(s.n)
+}
+
+// SampleInflight records the number of requests in flight right now. Call it
+// at a steady pace (the runner's tick loop samples every 100ms); averages
+// are taken per second (Concurrency), per snapshot interval and over the run.
+func (s *Stats) SampleInflight(n int64) {
+	s.muInflight.Lock()
+	defer s.muInflight.Unlock()
+	s.inflightSec.add(n)
+	s.inflightRun.add(n)
+}
+
+// InflightAvg returns the average and peak in-flight count sampled so far
+func (s *Stats) InflightAvg() (avg float64, peak int64) {
+	s.muInflight.Lock()
+	defer s.muInflight.Unlock()
+	return s.inflightRun.avg(), s.inflightRun.max
+}
+
+// GetConcurrency returns up to the last n per-second in-flight averages,
+// oldest first
+func (s *Stats) GetConcurrency(n int) []float64 {
+	s.muInflight.Lock()
+	defer s.muInflight.Unlock()
+	start := max(0, len(s.concurrency)-n)
+	return append([]float64(nil), s.concurrency[start:]...)
+}
+
+// rotateInflight closes the second's samples, appending their average to the
+// concurrency history, and returns them
+func (s *Stats) rotateInflight() inflightSamples {
+	s.muInflight.Lock()
+	defer s.muInflight.Unlock()
+	sec := s.inflightSec
+	s.inflightSec = inflightSamples{}
+	s.concurrency = append(s.concurrency, sec.avg())
+	if len(s.concurrency) > heatmapMaxCols {
+		s.concurrency = s.concurrency[len(s.concurrency)-heatmapMaxCols:]
+	}
+	return sec
+}
@@ -0,0 +1,136 @@
+package stats
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// IntervalSnapshot is the state of one snapshot interval. A run's list of
+// snapshots is enough to redraw its dashboard after the fact: counters for
+// throughput sparklines, percentiles for latency over time, and the full
+// histogram for anything else.
+type IntervalSnapshot struct {
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Requests uint64    `json:"requests"`
+	Success  uint64    `json:"success"`
+	Fail     uint64    `json:"fail"`
+	Bytes    uint64    `json:"bytes"` // Response bodies
+	P50Ms    float64   `json:"p50_ms"`
+	P90Ms    float64   `json:"p90_ms"`
+	P99Ms    float64   `json:"p99_ms"`
+	MaxMs    float64   `json:"max_ms"`
+
+	// Bytes on the wire: request heads and bodies sent, response heads and
+	// bodies received
+	SentBytes uint64 `json:"sent_bytes"`
+	RecvBytes uint64 `json:"recv_bytes"`
+
+	// Requests in flight, sampled every 100ms: average and peak
+	InflightAvg float64 `json:"inflight_avg"`
+	InflightMax int64   `json:"inflight_max"`
+
+	// Service time histogram (µs), HdrHistogram V2 compressed + base64, see DecodeHistogram
+	Histogram string `json:"histogram"`
+}
+
+// intervalMaxSnapshots caps the retained snapshot history
+const intervalMaxSnapshots = 86400
+
+// intervalWindow accumulates the per-second histograms of the current snapshot interval
+type intervalWindow struct {
+	hist   *SafeHistogram
+	start  time.Time
+	secs   int
+	counts [6]uint64 // Requests, Success, Fail, Bytes, SentBytes, RecvBytes at window start
+
+	inflight inflightSamples
+}
+
+// SetSnapshotInterval sets how many seconds each IntervalSnapshot covers (default 1)
+func (s *Stats) SetSnapshotInterval(d time.Duration) {
+	s.muSnap.Lock()
+	defer s.muSnap.Unlock()
+	s.snapEvery = max(1, int(d/time.Second))
+}
+
+// addToWindow folds one second of service times and in-flight samples into
+// the open snapshot interval
+func (s *Stats) addToWindow(sec *SafeHistogram, inflight inflightSamples) {
+	s.muSnap.Lock()
+	defer s.muSnap.Unlock()
+	if s.window == nil {
+		s.openWindow(time.Now().Add(-time.Second))
+	}
+	s.window.hist.Merge(sec)
+	s.window.inflight.merge(inflight)
+	s.window.secs++
+	if s.window.secs >= max(1, s.snapEvery) {
+		s.closeWindow()
+	}
+}
+
+// FlushSnapshot closes the open interval early, e.g. when the run ends
+func (s *Stats) FlushSnapshot() {
+	s.muSnap.Lock()
+	defer s.muSnap.Unlock()
+	if s.window != nil && s.window.secs > 0 {
+		s.closeWindow()
+	}
+}
+
+// GetSnapshots returns a copy of the recorded interval snapshots
+func (s *Stats) GetSnapshots() []IntervalSnapshot {
+	s.muSnap.Lock()
+	defer s.muSnap.Unlock()
+	return append([]IntervalSnapshot(nil), s.Intervals...)
+}
+
+func (s *Stats) counts() [6]uint64 {
+	return [6]uint64{
+		atomic.LoadUint64(&s.Requests),
+		atomic.LoadUint64(&s.Success),
+		atomic.LoadUint64(&s.Fail),
+		atomic.LoadUint64(&s.Bytes),
+		atomic.LoadUint64(&s.SentBytes),
+		atomic.LoadUint64(&s.RecvBytes),
+	}
+}
+
+// openWindow starts a new interval whose counters continue from the last one; muSnap must be held
+func (s *Stats) openWindow(start time.Time) {
+	s.window = &intervalWindow{hist: NewSafeHistogram(), start: start, counts: s.lastCounts}
+}
+
+// closeWindow records the open interval and starts the next; muSnap must be held
+func (s *Stats) closeWindow() {
+	now := time.Now()
+	cur := s.counts()
+	w := s.window
+	snap := IntervalSnapshot{
+		Start:    w.start,
+		End:      now,
+		Requests: cur[0] - w.counts[0],
+		Success:  cur[1] - w.counts[1],
+		Fail:     cur[2] - w.counts[2],
+		Bytes:    cur[3] - w.counts[3],
+		P50Ms:    float64(w.hist.ValueAtQuantile(50)) / 1000,
+		P90Ms:    float64(w.hist.ValueAtQuantile(90)) / 1000,
+		P99Ms:    float64(w.hist.ValueAtQuantile(99)) / 1000,
+		MaxMs:    float64(w.hist.Max()) / 1000,
+
+		SentBytes: cur[4] - w.counts[4],
+		RecvBytes: cur[5] - w.counts[5],
+
+		InflightAvg: w.inflight.avg(),
+		InflightMax: w.inflight.max,
+	}
+	snap.Histogram, _ = w.hist.Encode()
+
+	s.Intervals = append(s.Intervals, snap)
+	if len(s.Intervals) > intervalMaxSnapshots {
+		s.Intervals = s.Intervals[len(s.Intervals)-intervalMaxSnapshots:]
+	}
+	s.lastCounts = cur
+	s.openWindow(now)
+}
@@ -0,0 +1,91 @@
+package stats
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxSignatureBody caps the stored response body per failure signature
+const maxSignatureBody = 4096
+
+// maxSignatures bounds memory when errors are highly variable
+const maxSignatures = 200
+
+// FailureSignature groups failures by status and normalized message
+type FailureSignature struct {
+	Status  int
+	Message string
+}
+
+// FailureSummary is one signature with its count and most recent response body
+type FailureSummary struct {
+	FailureSignature
+	Count    int
+	LastBody string
+	LastSeen time.Time
+}
+
+var (
+	reUUID   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	reHex    = regexp.MustCompile(`0x[0-9a-fA-F]+`)
+	reNumber = regexp.MustCompile(`\d+`)
+)
+
+// NormalizeError collapses variable parts (ids, ports, addresses) so similar errors group together
+func NormalizeError(status int, errStr string) string {
+	if errStr == "" {
+		return fmt.Sprintf("HTTP %d", status)
+	}
+	// Same bucket as public/loadtester.go
+	if strings.Contains(errStr, "Timeout") || strings.Contains(errStr, "timeout") || strings.Contains(errStr, "deadline exceeded") {
+		return "Client Timeout"
+	}
+	s := reUUID.ReplaceAllString(errStr, "<uuid>")
+	s = reHex.ReplaceAllString(s, "<hex>")
+	s = reNumber.ReplaceAllString(s, "N")
+	return s
+}
+
+// addFailure records a failed request; caller holds muCodes
+func (s *Stats) addFailure(code int, errStr, respBody string) {
+	sig := FailureSignature{Status: code, Message: NormalizeError(code, errStr)}
+	f, ok := s.Failures[sig]
+	if !ok {
+		if len(s.Failures) >= maxSignatures {
+			sig = FailureSignature{Status: code, Message: "(other)"}
+			f, ok = s.Failures[sig]
+		}
+		if !ok {
+			f = &FailureSummary{FailureSignature: sig}
+			s.Failures[sig] = f
+		}
+	}
+	f.Count++
+	f.LastSeen = time.Now()
+	if respBody != "" {
+		if len(respBody) > maxSignatureBody {
+			respBody = respBody[:maxSignatureBody]
+		}
+		f.LastBody = respBody
+	}
+}
+
+// GetFailures returns failure signatures, most frequent first
+func (s *Stats) GetFailures() []FailureSummary {
+	s.muCodes.Lock()
+	defer s.muCodes.Unlock()
+	out := make([]FailureSummary, 0, len(s.Failures))
+	for _, f := range s.Failures {
+		out = append(out, *f)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Message < out[j].Message
+	})
+	return out
+}
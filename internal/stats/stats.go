@@ -12,13 +12,85 @@ type Stats struct {
 	Fail     uint64
 	Bytes    uint64
 
-	// Lags
+	// Bytes on the wire per request: request head and body sent, response
+	// head and body received (Bytes counts response bodies only)
+	SentBytes uint64
+	RecvBytes uint64
+
+	// Requests shed by the client-side circuit breaker (never sent)
+	ShortCircuited uint64
+
+	// Load shed because the server asked us to back off (Retry-After)
+	RetryAfterShed       uint64 // Open-loop: intended requests not sent
+	RetryAfterPauseMicro int64  // Closed-loop: total time users spent paused
+
+	// Open-loop requests skipped when the scheduler fell too far behind (generator saturation)
+	SchedulerSkipped uint64
+
+	// Requests that failed because the generator ran out of local resources
+	PortsExhausted uint64 // EADDRNOTAVAIL
+	FDsExhausted   uint64 // EMFILE/ENFILE
+
+	// Requests that failed TLS certificate verification (unknown CA, wrong host, expired)
+	TLSVerifyFailed uint64
+
+	// Cache revalidation: requests sent with validators and the 304s they got
+	Conditional uint64
+	NotModified uint64
+
+	// Requests still in flight when the run was aborted or the users mode graceful stop ran out
+	ForceCancelled uint64
+
+	// Lags: scheduled start to actual send, per request
 	TotalQueueWaitMicro int64
+	QueueWait           *SafeHistogram
+
+	// Closed-loop user iterations: one request plus think time
+	Iterations    uint64
+	IterationTime *SafeHistogram
+	GroupTime     *SafeHistogram // Fan-out groups: first request sent to last response
+
+	// Users mode health: requests cut off by the iteration deadline,
+	// iterations that waited on their requests longer than the stuck
+	// threshold, and users waiting that long right now and at most
+	IterationDeadlines uint64
+	StuckIterations    uint64
+	StuckUsers         int64
+	StuckPeak          int64
+
+	// Results the runner's bounded buffer dropped (oldest first) to keep
+	// memory flat; the summary and exports do not cover them
+	ResultsDropped uint64
 
 	// Histograms
 	ServiceTime *SafeHistogram
 	TotalTime   *SafeHistogram
 
+	// Interval histogram, rotated into Heatmap columns every second
+	interval  atomic.Pointer[SafeHistogram]
+	muHeatmap sync.Mutex
+	Heatmap   [][]int64
+
+	// In-flight samples of the current second and the whole run, and the
+	// per-second averages (see inflight.go)
+	muInflight  sync.Mutex
+	inflightSec inflightSamples
+	inflightRun inflightSamples
+	concurrency []float64
+
+	// Per-second bytes sent and received (see transfer.go)
+	muTransfer   sync.Mutex
+	lastTransfer [2]uint64
+	sentRate     []uint64
+	recvRate     []uint64
+
+	// Interval snapshots, one per snapEvery seconds (see intervals.go)
+	muSnap     sync.Mutex
+	snapEvery  int
+	window     *intervalWindow
+	lastCounts [6]uint64
+	Intervals  []IntervalSnapshot
+
 	// Status Codes (Protected by Mutex for map, or simple Atomic counters)
 	// For high throughput, atomic counters for common codes is better,
 	// or a sharded map. For TUI app, a Mutex map is probably fine if infrequent updates,
@@ -30,16 +102,29 @@ type Stats struct {
 	StatusCodes     map[int]int
 	ErrorCounts     map[string]int
 	ResponseSamples map[int]string
+	Failures        map[FailureSignature]*FailureSummary
 }
 
+// HeatmapBoundsMs splits service time into heatmap rows (the last row is >= 5s)
+var HeatmapBoundsMs = []int64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000}
+
+// heatmapMaxCols caps the retained heatmap history (seconds)
+const heatmapMaxCols = 3600
+
 func NewStats() *Stats {
-	return &Stats{
+	s := &Stats{
 		ServiceTime:     NewSafeHistogram(),
 		TotalTime:       NewSafeHistogram(),
+		QueueWait:       NewSafeHistogram(),
+		IterationTime:   NewSafeHistogram(),
+		GroupTime:       NewSafeHistogram(),
 		StatusCodes:     make(map[int]int),
 		ErrorCounts:     make(map[string]int),
 		ResponseSamples: make(map[int]string),
+		Failures:        make(map[FailureSignature]*FailureSummary),
 	}
+	s.interval.Store(NewSafeHistogram())
+	return s
 }
 
 func (s *Stats) Reset() {
@@ -47,15 +132,56 @@ func (s *Stats) Reset() {
 	atomic.StoreUint64(&s.Success, 0)
 	atomic.StoreUint64(&s.Fail, 0)
 	atomic.StoreUint64(&s.Bytes, 0)
+	atomic.StoreUint64(&s.SentBytes, 0)
+	atomic.StoreUint64(&s.RecvBytes, 0)
+	atomic.StoreUint64(&s.ShortCircuited, 0)
+	atomic.StoreUint64(&s.Conditional, 0)
+	atomic.StoreUint64(&s.NotModified, 0)
+	atomic.StoreUint64(&s.RetryAfterShed, 0)
+	atomic.StoreInt64(&s.RetryAfterPauseMicro, 0)
+	atomic.StoreUint64(&s.SchedulerSkipped, 0)
+	atomic.StoreUint64(&s.PortsExhausted, 0)
+	atomic.StoreUint64(&s.FDsExhausted, 0)
+	atomic.StoreUint64(&s.TLSVerifyFailed, 0)
+	atomic.StoreUint64(&s.ForceCancelled, 0)
 	atomic.StoreInt64(&s.TotalQueueWaitMicro, 0)
+	atomic.StoreUint64(&s.Iterations, 0)
+	atomic.StoreUint64(&s.IterationDeadlines, 0)
+	atomic.StoreUint64(&s.StuckIterations, 0)
+	atomic.StoreInt64(&s.StuckUsers, 0)
+	atomic.StoreInt64(&s.StuckPeak, 0)
+	atomic.StoreUint64(&s.ResultsDropped, 0)
 
 	s.ServiceTime = NewSafeHistogram()
 	s.TotalTime = NewSafeHistogram()
+	s.QueueWait = NewSafeHistogram()
+	s.IterationTime = NewSafeHistogram()
+	s.GroupTime = NewSafeHistogram()
+	s.interval.Store(NewSafeHistogram())
+
+	s.muHeatmap.Lock()
+	s.Heatmap = nil
+	s.muHeatmap.Unlock()
+
+	s.muInflight.Lock()
+	s.inflightSec, s.inflightRun, s.concurrency = inflightSamples{}, inflightSamples{}, nil
+	s.muInflight.Unlock()
+
+	s.muTransfer.Lock()
+	s.lastTransfer, s.sentRate, s.recvRate = [2]uint64{}, nil, nil
+	s.muTransfer.Unlock()
+
+	s.muSnap.Lock()
+	s.window = nil
+	s.lastCounts = [6]uint64{}
+	s.Intervals = nil
+	s.muSnap.Unlock()
 
 	s.muCodes.Lock()
 	s.StatusCodes = make(map[int]int)
 	s.ErrorCounts = make(map[string]int)
 	s.ResponseSamples = make(map[int]string)
+	s.Failures = make(map[FailureSignature]*FailureSummary)
 	s.muCodes.Unlock()
 }
 
@@ -72,6 +198,8 @@ func (s *Stats) Add(res bool, bytes uint64, service, queue, total time.Duration,
 
 	s.ServiceTime.RecordValue(service.Microseconds())
 	s.TotalTime.RecordValue(total.Microseconds())
+	s.QueueWait.RecordValue(queue.Microseconds())
+	s.interval.Load().RecordValue(service.Microseconds())
 
 	// Update Codes
 	s.muCodes.Lock()
@@ -85,9 +213,134 @@ func (s *Stats) Add(res bool, bytes uint64, service, queue, total time.Duration,
 			s.ResponseSamples[code] = respBody
 		}
 	}
+	if !res {
+		s.addFailure(code, errStr, respBody)
+	}
 	s.muCodes.Unlock()
 }
 
+// AddForceCancelled counts a request cancelled by an abort or after the graceful stop period
+func (s *Stats) AddForceCancelled() {
+	atomic.AddUint64(&s.ForceCancelled, 1)
+}
+
+// AddConditional counts a revalidation request, and whether it got a 304
+func (s *Stats) AddConditional(notModified bool) {
+	atomic.AddUint64(&s.Conditional, 1)
+	if notModified {
+		atomic.AddUint64(&s.NotModified, 1)
+	}
+}
+
+// AddShortCircuit counts a request refused by the circuit breaker
+func (s *Stats) AddShortCircuit() {
+	atomic.AddUint64(&s.ShortCircuited, 1)
+}
+
+// AddRetryAfterShed counts an open-loop request skipped due to Retry-After
+func (s *Stats) AddRetryAfterShed() {
+	atomic.AddUint64(&s.RetryAfterShed, 1)
+}
+
+// AddRetryAfterPause records time a virtual user spent honoring Retry-After
+func (s *Stats) AddRetryAfterPause(d time.Duration) {
+	atomic.AddInt64(&s.RetryAfterPauseMicro, d.Microseconds())
+}
+
+// AddSchedulerSkipped counts intended open-loop requests dropped by a scheduler reset
+func (s *Stats) AddSchedulerSkipped(n uint64) {
+	atomic.AddUint64(&s.SchedulerSkipped, n)
+}
+
+// AddExhausted counts a request lost to local port (ports=true) or descriptor exhaustion
+func (s *Stats) AddExhausted(ports bool) {
+	if ports {
+		atomic.AddUint64(&s.PortsExhausted, 1)
+	} else {
+		atomic.AddUint64(&s.FDsExhausted, 1)
+	}
+}
+
+// AddTLSVerifyFailed counts a request rejected by certificate verification
+func (s *Stats) AddTLSVerifyFailed() {
+	atomic.AddUint64(&s.TLSVerifyFailed, 1)
+}
+
+// AddGroup records how long a fan-out group took to complete
+func (s *Stats) AddGroup(d time.Duration) {
+	s.GroupTime.RecordValue(d.Microseconds())
+}
+
+// AddIteration records one completed virtual user loop
+func (s *Stats) AddIteration(d time.Duration) {
+	atomic.AddUint64(&s.Iterations, 1)
+	s.IterationTime.RecordValue(d.Microseconds())
+}
+
+// AddIterationDeadline counts a request cut off by the iteration deadline
+func (s *Stats) AddIterationDeadline() {
+	atomic.AddUint64(&s.IterationDeadlines, 1)
+}
+
+// AddResultDropped counts a result pushed out of the full result buffer
+func (s *Stats) AddResultDropped() {
+	atomic.AddUint64(&s.ResultsDropped, 1)
+}
+
+// AddStuckIteration counts an iteration whose requests outlasted the stuck threshold
+func (s *Stats) AddStuckIteration() {
+	atomic.AddUint64(&s.StuckIterations, 1)
+}
+
+// SetStuckUsers records how many users are stuck right now, keeping the peak
+func (s *Stats) SetStuckUsers(n int64) {
+	atomic.StoreInt64(&s.StuckUsers, n)
+	for {
+		peak := atomic.LoadInt64(&s.StuckPeak)
+		if n <= peak || atomic.CompareAndSwapInt64(&s.StuckPeak, peak, n) {
+			return
+		}
+	}
+}
+
+// RotateInterval closes the current interval histogram, appends it as a
+// heatmap column and returns it
+func (s *Stats) RotateInterval() *SafeHistogram {
+	prev := s.interval.Swap(NewSafeHistogram())
+
+	bounds := make([]int64, len(HeatmapBoundsMs))
+	for i, b := range HeatmapBoundsMs {
+		bounds[i] = b * 1000
+	}
+	col := prev.Buckets(bounds)
+
+	s.rotateTransfer()
+	s.addToWindow(prev, s.rotateInflight())
+
+	s.muHeatmap.Lock()
+	s.Heatmap = append(s.Heatmap, col)
+	if len(s.Heatmap) > heatmapMaxCols {
+		s.Heatmap = s.Heatmap[len(s.Heatmap)-heatmapMaxCols:]
+	}
+	s.muHeatmap.Unlock()
+	return prev
+}
+
+// GetHeatmap returns up to the last n heatmap columns, oldest first
+func (s *Stats) GetHeatmap(n int) [][]int64 {
+	s.muHeatmap.Lock()
+	defer s.muHeatmap.Unlock()
+	start := 0
+	if len(s.Heatmap) > n {
+		start = len(s.Heatmap) - n
+	}
+	copy := make([][]int64, len(s.Heatmap)-start)
+	for i, col := range s.Heatmap[start:] {
+		copy[i] = append([]int64(nil), col...)
+	}
+	return copy
+}
+
 func (s *Stats) QueueWaitAvgMs() float64 {
 	reqs := atomic.LoadUint64(&s.Requests)
 	if reqs == 0 {
@@ -0,0 +1,34 @@
+package stats
+
+import "sync/atomic"
+
+// AddTransfer counts the bytes of one request: sent (request head and body)
+// and received (response head and body)
+func (s *Stats) AddTransfer(sent, recv uint64) {
+	atomic.AddUint64(&s.SentBytes, sent)
+	atomic.AddUint64(&s.RecvBytes, recv)
+}
+
+// GetTransferRates returns up to the last n per-second byte counts sent and
+// received, oldest first
+func (s *Stats) GetTransferRates(n int) (sent, recv []uint64) {
+	s.muTransfer.Lock()
+	defer s.muTransfer.Unlock()
+	start := max(0, len(s.sentRate)-n)
+	return append([]uint64(nil), s.sentRate[start:]...), append([]uint64(nil), s.recvRate[start:]...)
+}
+
+// rotateTransfer appends the bytes sent and received since the last call to
+// the per-second history
+func (s *Stats) rotateTransfer() {
+	sent, recv := atomic.LoadUint64(&s.SentBytes), atomic.LoadUint64(&s.RecvBytes)
+	s.muTransfer.Lock()
+	defer s.muTransfer.Unlock()
+	s.sentRate = append(s.sentRate, sent-s.lastTransfer[0])
+	s.recvRate = append(s.recvRate, recv-s.lastTransfer[1])
+	s.lastTransfer = [2]uint64{sent, recv}
+	if len(s.sentRate) > heatmapMaxCols {
+		s.sentRate = s.sentRate[len(s.sentRate)-heatmapMaxCols:]
+		s.recvRate = s.recvRate[len(s.recvRate)-heatmapMaxCols:]
+	}
+}
@@ -1,46 +1,41 @@
 package app
 
 import (
-	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"steadyq/internal/monitor"
+	"steadyq/internal/plan"
 	"steadyq/internal/runner"
 	"steadyq/internal/tui/styles"
 	"steadyq/internal/tui/views"
 )
 
-type ClearStatusMsg struct{}
-
-func clearStatusCmd() tea.Cmd {
-	return tea.Tick(3*time.Second, func(_ time.Time) tea.Msg {
-		return ClearStatusMsg{}
-	})
-}
-
 // View Enum
 type ViewID int
 
 const (
 	ViewRunner ViewID = iota
 	ViewDashboard
+	ViewHeatmap
 )
 
-type StatsMsg runner.StatsSnapshot
+// Launches above these targets ask for confirmation first
+const (
+	confirmRPSThreshold   = 1000
+	confirmUsersThreshold = 500
+)
 
 type Model struct {
-	Runner  *runner.Runner
-	Updates runner.StatsUpdateChan
-
-	// Core State
-	RunActive bool
-	Draining  bool
-	RunCtx    context.Context // To cancel run
-	RunCancel context.CancelFunc
+	// Core State: one session per concurrent run, Active is the focused one
+	Sessions []*RunSession
+	Active   int
 
 	// Layout
 	Width  int
@@ -49,65 +44,172 @@ type Model struct {
 	CurrentView ViewID
 	MenuItems   []string
 
-	RunnerView views.RunnerView
-	DashView   views.DashboardView
+	RunnerView  views.RunnerView
+	HeatmapView views.HeatmapView
+	PlanDialog  views.PlanDialog
+
+	// Launch awaiting confirmation (target above safety threshold)
+	PendingRun *runner.Config
+
+	// Config just loaded (plan, run directory or bundle), awaiting the choice
+	// between replaying it now and editing it first
+	PendingLoad *loadedConfig
 
-	// Feedback
-	StatusMsg string
+	// Keymap and glossary overlay (F1, or ? outside the Runner form)
+	ShowHelp bool
+	Help     viewport.Model
+
+	// Export location: directory and file name templates (see ReportPath)
+	OutDir         string
+	ReportTemplate string
+	BundleTemplate string
+
+	// Feedback: notifications, oldest first (see notify)
+	Toasts    []Toast
+	nextToast int
+
+	// First-run onboarding: the demo offer, then the dashboard tour (tip
+	// number, 0 when not touring)
+	Welcome bool
+	Tour    int
 }
 
 func NewModel(r *runner.Runner, updates runner.StatsUpdateChan) Model {
 	return Model{
-		Runner:      r,
-		Updates:     updates,
+		Sessions:    []*RunSession{newSession(1, r, updates, 0, 0)},
 		CurrentView: ViewRunner,
-		MenuItems:   []string{"[1] New Run", "[2] Dashboard"},
+		MenuItems:   []string{"[1] New Run", "[2] Dashboard", "[3] Heatmap"},
 		RunnerView:  views.NewRunnerView(r.Cfg),
-		DashView:    views.NewDashboardView(r.Cfg, 0, 0),
+		HeatmapView: views.NewHeatmapView(0, 0),
+		PlanDialog:  views.NewPlanDialog(),
+
+		ReportTemplate: DefaultReportTemplate,
+		BundleTemplate: DefaultBundleTemplate,
 	}
 }
 
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.RunnerView.Init(),
-		waitForUpdate(m.Updates),
+		waitForUpdate(1, m.Sessions[0].Updates),
 	)
 }
 
-func waitForUpdate(sub runner.StatsUpdateChan) tea.Cmd {
-	return func() tea.Msg {
-		return StatsMsg(<-sub)
-	}
+// session returns the focused run
+func (m Model) session() *RunSession {
+	return m.Sessions[m.Active]
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
-	case ClearStatusMsg:
-		m.StatusMsg = ""
+	case DismissToastMsg:
+		m.dismissToast(msg.ID)
+		return m, nil
+
+	case PreflightMsg:
+		if msg.Err != nil {
+			return m, m.notify(ToastError, "Not started: %v", msg.Err)
+		}
+		toast := m.notify(ToastInfo, "Preflight OK (status %d, %s)", msg.Result.Status, msg.Result.ServiceTime.Round(time.Millisecond))
+		return m, tea.Batch(toast, m.startRun(msg.Cfg))
+
+	case views.PlanSaveMsg:
+		path, err := plan.Save(msg.Name, m.RunnerView.GetConfig())
+		if err != nil {
+			slog.Error("plan not saved", "name", msg.Name, "err", err)
+			return m, m.notify(ToastError, "Save Plan Failed: %v", err)
+		}
+		return m, m.notify(ToastInfo, "Plan saved to %s", path)
+
+	case views.PlanLoadMsg:
+		cfg, source, err := LoadConfig(msg.Path)
+		if err != nil {
+			slog.Error("config not loaded", "path", msg.Path, "err", err)
+			return m, m.notify(ToastError, "Load Failed: %v", err)
+		}
+		m.PendingLoad = &loadedConfig{Cfg: cfg, Source: source}
 		return m, nil
 
 	case tea.KeyMsg:
+		// 0. MODAL DIALOGS capture all keys except quit
+		if m.Welcome && msg.String() != "ctrl+c" {
+			return m.updateWelcome(msg)
+		}
+		if m.PendingLoad != nil && msg.String() != "ctrl+c" {
+			return m.updateLoadChoice(msg)
+		}
+		if m.PendingRun != nil && msg.String() != "ctrl+c" {
+			if msg.String() == "y" || msg.String() == "Y" {
+				cmds = append(cmds, m.launch(*m.PendingRun))
+			} else {
+				cmds = append(cmds, m.notify(ToastInfo, "Launch cancelled."))
+			}
+			m.PendingRun = nil
+			return m, tea.Batch(cmds...)
+		}
+		if m.ShowHelp && msg.String() != "ctrl+c" {
+			switch msg.String() {
+			case "esc", "?", "f1", "q":
+				m.ShowHelp = false
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.Help, cmd = m.Help.Update(msg)
+			return m, cmd
+		}
+		if m.PlanDialog.Active && msg.String() != "ctrl+c" {
+			var cmd tea.Cmd
+			m.PlanDialog, cmd = m.PlanDialog.Update(msg)
+			return m, cmd
+		}
+
 		// 1. GLOBAL NAVIGATION & CONTROL (Prioritized)
 		switch msg.String() {
 		case "ctrl+c", "ctrl+q": // Removed "q" to allow typing
 			return m, tea.Quit
 
+		case "ctrl+x": // Dismiss notifications and the tour
+			m.dismissToast(0)
+			m.Tour = 0
+			return m, nil
+
+		case "enter": // Next tour tip; the Runner form uses Enter itself
+			if m.Tour > 0 && m.CurrentView != ViewRunner {
+				m.nextTip()
+				return m, nil
+			}
+
+		case "f1":
+			m.openHelp()
+			return m, nil
+
+		case "?": // Typed as text in the Runner form
+			if m.CurrentView != ViewRunner {
+				m.openHelp()
+				return m, nil
+			}
+
+		case "ctrl+t": // Theme
+			styles.Apply(styles.NextTheme())
+			m.RunnerView = m.RunnerView.RefreshStyles()
+			return m, m.notify(ToastInfo, "Theme: %s", styles.Current)
+
 		case "ctrl+d": // Dashboard
 			m.CurrentView = ViewDashboard
 			return m, nil
 
 		case "ctrl+right":
 			m.CurrentView++
-			if m.CurrentView > ViewDashboard {
+			if m.CurrentView > ViewHeatmap {
 				m.CurrentView = ViewRunner
 			}
 			return m, nil
 		case "ctrl+left":
 			m.CurrentView--
 			if m.CurrentView < ViewRunner {
-				m.CurrentView = ViewDashboard
+				m.CurrentView = ViewHeatmap
 			}
 			return m, nil
 		// Removed 1, 2, 3 to allow numeric input
@@ -115,35 +217,85 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// 2. ACTIONS
 		case "ctrl+r": // Run
 			if m.CurrentView == ViewRunner {
-				cfg := m.RunnerView.GetConfig()
-				m.startRun(cfg)
+				errs := m.RunnerView.Validate()
+				m.RunnerView.Errors = errs
+				if len(errs) > 0 {
+					return m, m.notify(ToastWarn, "Cannot start: %d field(s) need attention.", len(errs))
+				}
+
+				return m, m.requestLaunch(m.RunnerView.GetConfig())
 			}
 			return m, nil
 
-		case "ctrl+s": // Stop
-			if m.RunActive && m.RunCancel != nil {
-				m.RunCancel()
-				m.RunActive = false
+		case "ctrl+w": // Save Plan
+			if m.CurrentView == ViewRunner {
+				var cmd tea.Cmd
+				m.PlanDialog, cmd = m.PlanDialog.OpenSave()
+				return m, cmd
 			}
 			return m, nil
 
+		case "ctrl+o": // Load Plan
+			if m.CurrentView == ViewRunner {
+				m.PlanDialog = m.PlanDialog.OpenLoad()
+			}
+			return m, nil
+
+		case "ctrl+s": // Stop
+			m.session().stop()
+			return m, nil
+
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9": // Switch run (Dashboard/Heatmap only, digits are input elsewhere)
+			if m.CurrentView != ViewRunner {
+				idx := int(msg.String()[0] - '1')
+				if idx < len(m.Sessions) {
+					m.Active = idx
+				}
+				return m, nil
+			}
+
+		case "ctrl+e": // Export Bundle
+			if m.CurrentView != ViewRunner {
+				r := m.session().Runner
+				if r.ResultCount() == 0 {
+					return m, m.notify(ToastInfo, "No results to export yet.")
+				}
+				base, err := ReportPath(m.OutDir, m.BundleTemplate, r.Cfg, time.Now())
+				if err != nil {
+					return m, m.notify(ToastError, "Bundle Failed: %v", err)
+				}
+				name := base + ".zip"
+				if err := ExportBundle(r, name); err != nil {
+					slog.Error("bundle export failed", "run_id", r.Meta.RunID, "file", name, "err", err)
+					return m, m.notify(ToastError, "Bundle Failed: %v", err)
+				}
+				return m, m.notify(ToastInfo, "Bundle saved to %s", name)
+			}
+
 		case "ctrl+p": // Export
 			if m.CurrentView == ViewDashboard {
-				// Export Current Run
-				if len(m.Runner.Results) > 0 {
-					ts := time.Now().Format("20060102-150405")
-					base := fmt.Sprintf("steadyq_report_%s", ts)
-					if err := ExportCSV(m.Runner.Results, base+".csv"); err == nil {
-						ExportJSON(m.Runner.Results, base+".json")
-						m.StatusMsg = fmt.Sprintf("Exported to %s.{csv,json}", base)
-						cmds = append(cmds, clearStatusCmd())
+				// Export Focused Run
+				r := m.session().Runner
+				if results := r.SnapshotResults(); len(results) > 0 {
+					base, err := ReportPath(m.OutDir, m.ReportTemplate, r.Cfg, time.Now())
+					if err == nil {
+						err = ExportCSV(results, base+".csv")
+					}
+					if err == nil {
+						err = ExportJSON(results, base+".json")
+					}
+					if err == nil {
+						if err := ExportTargetCSV(TargetSamples(r), results, base+"_target.csv"); err == nil {
+							cmds = append(cmds, m.notify(ToastInfo, "Exported to %s.{csv,json} and %s_target.csv", base, base))
+						} else {
+							cmds = append(cmds, m.notify(ToastInfo, "Exported to %s.{csv,json}", base))
+						}
 					} else {
-						m.StatusMsg = fmt.Sprintf("Export Failed: %v", err)
-						cmds = append(cmds, clearStatusCmd())
+						slog.Error("report export failed", "run_id", r.Meta.RunID, "err", err)
+						cmds = append(cmds, m.notify(ToastError, "Export Failed: %v", err))
 					}
 				} else {
-					m.StatusMsg = "No results to export yet."
-					cmds = append(cmds, clearStatusCmd())
+					cmds = append(cmds, m.notify(ToastInfo, "No results to export yet."))
 				}
 				return m, tea.Batch(cmds...)
 			}
@@ -153,49 +305,78 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Key wasn't global, pass to active view
 		// ... (Logic continues below in default case)
 
+	case tea.MouseMsg:
+		if m.ShowHelp {
+			var cmd tea.Cmd
+			m.Help, cmd = m.Help.Update(msg)
+			return m, cmd
+		}
+		if m.Welcome || m.PendingRun != nil || m.PendingLoad != nil || m.PlanDialog.Active {
+			return m, nil
+		}
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			if m.handleClick(msg.X, msg.Y) {
+				return m, nil
+			}
+		}
+		// Wheel events fall through to the active view's viewport
+
 	case tea.WindowSizeMsg:
 		m.Width = msg.Width
 		m.Height = msg.Height
-		contentHeight := m.Height - 7 // Increased footer space (3 rows now)
+		contentHeight := m.Height - 6 // Nav bar (2 rows), panel border and the one-row footer
+		if m.ShowHelp {
+			m.openHelp()
+		}
 
 		m.RunnerView.Width = m.Width
 		m.RunnerView.Height = contentHeight
 
-		m.DashView.Width = m.Width
-		m.DashView.Height = contentHeight
+		m.HeatmapView.Width = m.Width
+		m.HeatmapView.Height = contentHeight
 
-		updatedDash, _ := m.DashView.Update(msg)
-		m.DashView = updatedDash
+		for _, sess := range m.Sessions {
+			sess.DashView.Width = m.Width
+			sess.DashView.Height = contentHeight
+			sess.DashView, _ = sess.DashView.Update(msg)
+		}
 
 		updatedRunner, _ := m.RunnerView.Update(msg)
 		m.RunnerView = updatedRunner
 
 	case StatsMsg:
-		snap := runner.StatsSnapshot(msg)
-		updatedDash, c := m.DashView.Update(snap)
-		m.DashView = updatedDash
+		sess := m.Sessions[msg.SessionID-1]
+		snap := msg.Snap
+		updatedDash, c := sess.DashView.Update(snap)
+		sess.DashView = updatedDash
 		cmds = append(cmds, c)
 
 		// Check for Completion (Time based)
-		elapsed := time.Since(m.DashView.StartTime)
-		if m.RunActive && !m.Draining && elapsed >= m.DashView.Duration {
+		elapsed := time.Since(sess.DashView.StartTime)
+		if sess.RunActive && !sess.Draining && snap.State == runner.StateRunning && elapsed >= sess.DashView.Duration {
 			// Phase 1: Stop Generation (Drain)
-			m.Draining = true
-			if m.RunCancel != nil {
-				m.RunCancel()
-			}
-			m.StatusMsg = "Stopping load... waiting for inflight requests to finish."
+			sess.Draining = true
+			sess.Runner.Stop()
+			cmds = append(cmds, m.notify(ToastInfo, "Run %d: stopping load... waiting for inflight requests to finish.", sess.ID))
+		}
+
+		// An abort condition stopped the run early (the runner already cancelled it)
+		if sess.RunActive && !sess.Draining && snap.AbortReason != "" {
+			sess.Draining = true
+			cmds = append(cmds, m.notify(ToastError, "Run %d aborted: %s", sess.ID, snap.AbortReason))
 		}
 
-		if m.Draining && snap.Inflight == 0 {
+		if sess.RunActive && snap.State == runner.StateDone {
 			// Phase 2: Fully Stopped
-			m.RunActive = false
-			m.Draining = false
-			m.StatusMsg = "Test Completed."
-			cmds = append(cmds, clearStatusCmd())
+			sess.RunActive = false
+			sess.Draining = false
+			if snap.AbortReason == "" {
+				cmds = append(cmds, m.notify(ToastInfo, "Run %d: Test Completed.", sess.ID))
+			}
 		}
 
-		cmds = append(cmds, waitForUpdate(m.Updates))
+		cmds = append(cmds, waitForUpdate(sess.ID, sess.Updates))
+		return m, tea.Batch(cmds...)
 	}
 
 	// DEFAULT: Forward all other messages (KeyMsg that fell through, FrameMsg, BlinkMsg, etc.)
@@ -205,34 +386,179 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case ViewRunner:
 		m.RunnerView, defaultCmd = m.RunnerView.Update(msg)
 	case ViewDashboard:
-		m.DashView, defaultCmd = m.DashView.Update(msg)
+		sess := m.session()
+		sess.DashView, defaultCmd = sess.DashView.Update(msg)
 	}
 	cmds = append(cmds, defaultCmd)
 
 	return m, tea.Batch(cmds...)
 }
 
-func (m *Model) startRun(cfg runner.Config) {
-	// Cancel existing if any
-	if m.RunActive && m.RunCancel != nil {
-		m.RunCancel()
+// PreflightMsg carries the outcome of the probe sent before a run
+type PreflightMsg struct {
+	Cfg    runner.Config
+	Result runner.ExperimentResult
+	Err    error
+}
+
+// requestLaunch launches cfg, or asks first when it is above the safety thresholds
+func (m *Model) requestLaunch(cfg runner.Config) tea.Cmd {
+	if (cfg.Mode == "users" && cfg.NumUsers > confirmUsersThreshold) ||
+		(cfg.Mode != "users" && cfg.TargetRPS > confirmRPSThreshold) {
+		m.PendingRun = &cfg
+		return nil
 	}
+	return m.launch(cfg)
+}
 
-	m.Runner.Cfg = cfg
-	m.Runner.Stats.Reset()
+// launch starts cfg, first sending the preflight probe off the UI loop if one is configured
+func (m *Model) launch(cfg runner.Config) tea.Cmd {
+	if !cfg.WantsPreflight() {
+		return m.startRun(cfg)
+	}
+	toast := m.notify(ToastInfo, "Preflight: probing target...")
+	return tea.Batch(toast, func() tea.Msg {
+		res, err := runner.Preflight(cfg)
+		return PreflightMsg{Cfg: cfg, Result: res, Err: err}
+	})
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	m.RunCtx = ctx
-	m.RunCancel = cancel
-	m.RunActive = true
-	m.Draining = false
+// startRun launches cfg in an idle session, leaving other runs untouched.
+// Never-started sessions are reused first, then a new one is added,
+// then the oldest finished one is recycled.
+func (m *Model) startRun(cfg runner.Config) tea.Cmd {
+	var cmd tea.Cmd
+	idx := -1
+	for i, s := range m.Sessions {
+		if !s.Started {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 && len(m.Sessions) < maxSessions {
+		updates := make(runner.StatsUpdateChan, 100)
+		s := newSession(len(m.Sessions)+1, runner.NewRunner(cfg, updates), updates, m.Width, m.Height-5)
+		m.Sessions = append(m.Sessions, s)
+		idx = len(m.Sessions) - 1
+		cmd = waitForUpdate(s.ID, s.Updates)
+	}
+	if idx == -1 {
+		for i, s := range m.Sessions {
+			if !s.RunActive {
+				idx = i
+				break
+			}
+		}
+	}
+	if idx == -1 {
+		return m.notify(ToastWarn, "All %d run slots are busy. Stop one with Ctrl+S first.", maxSessions)
+	}
 
 	// totalDur calculated in NewDashboardView
-	m.DashView = views.NewDashboardView(cfg, m.Width, m.Height-6)
-
+	if err := m.Sessions[idx].start(cfg, m.Width, m.Height-5); err != nil {
+		return tea.Batch(cmd, m.notify(ToastWarn, "Run %d is still finishing: %v", m.Sessions[idx].ID, err))
+	}
+	m.Active = idx
 	m.CurrentView = ViewDashboard
+	if missing := runner.FastHTTPUnsupported(cfg); cfg.HTTPEngine == runner.EngineFastHTTP && len(missing) > 0 {
+		cmd = tea.Batch(cmd, m.notify(ToastWarn, "fasthttp ignores %s", strings.Join(missing, ", ")))
+	}
+	for _, warning := range runner.HostAdvisories(cfg, monitor.ReadHostLimits()) {
+		slog.Warn("host advisory", "warning", warning)
+		cmd = tea.Batch(cmd, m.notify(ToastWarn, "%s", warning))
+	}
+	return cmd
+}
+
+// navTabs renders the view menu, one string per tab
+func (m Model) navTabs() []string {
+	var tabs []string
+	for i, item := range m.MenuItems {
+		if ViewID(i) == m.CurrentView {
+			tabs = append(tabs, styles.TabActive.Render(item))
+		} else {
+			tabs = append(tabs, styles.TabBase.Render(item))
+		}
+	}
+	return tabs
+}
+
+// sessionTabs renders one tab per concurrent run
+func (m Model) sessionTabs() []string {
+	var tabs []string
+	for i, s := range m.Sessions {
+		icon := "○ "
+		if s.RunActive {
+			icon = "● "
+		}
+		style := styles.TabBase
+		if i == m.Active {
+			style = styles.TabActive
+		}
+		tabs = append(tabs, style.Render(icon+s.Label()))
+	}
+	return tabs
+}
+
+// sessionStrip lists concurrent runs above the dashboard
+func (m Model) sessionStrip() string {
+	return lipgloss.JoinHorizontal(lipgloss.Top, m.sessionTabs()...)
+}
 
-	go m.Runner.Run(ctx)
+// tabAt maps a column to the index of the rendered tab under it, or -1
+func tabAt(x int, tabs []string) int {
+	for i, t := range tabs {
+		w := lipgloss.Width(t)
+		if x >= 0 && x < w {
+			return i
+		}
+		x -= w
+	}
+	return -1
+}
+
+// handleClick switches views or runs when a tab is clicked
+func (m *Model) handleClick(x, y int) bool {
+	// Nav bar: FooterBase pads 1 column
+	navHeight := lipgloss.Height(lipgloss.JoinHorizontal(lipgloss.Top, m.navTabs()...))
+	if y < navHeight {
+		if idx := tabAt(x-1, m.navTabs()); idx >= 0 {
+			m.CurrentView = ViewID(idx)
+			return true
+		}
+		return false
+	}
+
+	// Session strip: first rows inside the Panel (border + padding)
+	if m.CurrentView != ViewRunner && len(m.Sessions) > 1 {
+		tabs := m.sessionTabs()
+		top := navHeight + 2
+		if y >= top && y < top+lipgloss.Height(tabs[0]) {
+			if idx := tabAt(x-3, tabs); idx >= 0 {
+				m.Active = idx
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// confirmView asks before launching a run above the safety thresholds
+func (m Model) confirmView() string {
+	cfg := m.PendingRun
+	target := fmt.Sprintf("%d RPS (threshold %d)", cfg.TargetRPS, confirmRPSThreshold)
+	if cfg.Mode == "users" {
+		target = fmt.Sprintf("%d users (threshold %d)", cfg.NumUsers, confirmUsersThreshold)
+	}
+	body := lipgloss.JoinVertical(lipgloss.Left,
+		styles.Warn.Bold(true).Render("⚠ High load requested"),
+		"",
+		styles.Text.Render("Target: "+target),
+		styles.Text.Render("URL:    "+cfg.URL),
+		"",
+		styles.Subtle.Render("[Y] Launch  [any other key] Cancel"),
+	)
+	return styles.Box.BorderForeground(styles.ColorWarning).Padding(1, 2).Render(body)
 }
 
 func (m Model) View() string {
@@ -240,61 +566,74 @@ func (m Model) View() string {
 		return "Loading..."
 	}
 
-	nav := strings.Builder{}
-	for i, item := range m.MenuItems {
-		if ViewID(i) == m.CurrentView {
-			nav.WriteString(styles.TabActive.Render(item))
-		} else {
-			nav.WriteString(styles.TabBase.Render(item))
-		}
-	}
-	navBar := styles.FooterBase.Width(m.Width).Render(nav.String())
+	navBar := styles.FooterBase.Width(m.Width).Render(lipgloss.JoinHorizontal(lipgloss.Top, m.navTabs()...))
 
 	contentStr := ""
 	switch m.CurrentView {
 	case ViewRunner:
 		contentStr = m.RunnerView.View()
 	case ViewDashboard:
-		contentStr = m.DashView.View()
+		contentStr = m.session().DashView.View()
+	case ViewHeatmap:
+		contentStr = m.HeatmapView.View(m.session().DashView.Stats)
 	}
-
-	// Adjust height for larger footer
-	content := styles.Panel.Width(m.Width - 2).Height(m.Height - 6).Render(contentStr)
-
-	// Help Grid
-	// Row 1: Navigation
-	keys1 := []string{
-		styles.RenderKey("Ctrl+<->", "View"),
-		styles.RenderKey("Tab", "Field"),
-		styles.RenderKey("Enter", "Edit"),
+	if m.CurrentView != ViewRunner && len(m.Sessions) > 1 {
+		contentStr = lipgloss.JoinVertical(lipgloss.Left, m.sessionStrip(), contentStr)
 	}
 
-	// Row 2: Actions
-	keys2 := []string{
-		styles.RenderKey("Ctrl+R", "Run"),
-		styles.RenderKey("Ctrl+S", "Stop"),
-		styles.RenderKey("Ctrl+P", "Export"),
-		styles.RenderKey("Ctrl+Q", "Quit"),
+	if m.PlanDialog.Active {
+		contentStr = lipgloss.Place(m.Width-6, m.Height-7, lipgloss.Center, lipgloss.Center, m.PlanDialog.View())
 	}
-
-	// Row 3: Shortcuts
-	keys3 := []string{
-		styles.RenderKey("Ctrl+D", "Dash"),
+	if m.PendingLoad != nil {
+		contentStr = lipgloss.Place(m.Width-6, m.Height-7, lipgloss.Center, lipgloss.Center, m.loadChoiceView())
+	}
+	if m.PendingRun != nil {
+		contentStr = lipgloss.Place(m.Width-6, m.Height-7, lipgloss.Center, lipgloss.Center, m.confirmView())
+	}
+	if m.Welcome {
+		contentStr = lipgloss.Place(m.Width-6, m.Height-7, lipgloss.Center, lipgloss.Center, m.welcomeView())
+	}
+	if m.ShowHelp {
+		contentStr = m.helpView()
 	}
 
-	helpRow1 := styles.FooterBase.Width(m.Width).Render(strings.Join(keys1, "   "))
-	helpRow2 := styles.FooterBase.Width(m.Width).Render(strings.Join(keys2, "   "))
-	helpRow3 := styles.FooterBase.Width(m.Width).Render(strings.Join(keys3, "   "))
-
-	footer := lipgloss.JoinVertical(lipgloss.Left, helpRow1, helpRow2, helpRow3)
-
-	// Status Overlay? Or just append to footer?
-	// Let's replace footer keybindings with status if exists, or append above footer.
-	if m.StatusMsg != "" {
-		status := styles.Box.BorderForeground(styles.ColorHighlight).Render(m.StatusMsg)
-		// Float it at the bottom above footer
-		return lipgloss.JoinVertical(lipgloss.Left, navBar, content, status, footer)
+	// The tour tip and toasts take their rows from the content, not from the screen
+	var below []string
+	if tip := m.tourView(); tip != "" {
+		below = append(below, tip)
+	}
+	if toasts := m.toastsView(); toasts != "" {
+		below = append(below, toasts)
+	}
+	belowRows := 0
+	for _, b := range below {
+		belowRows += lipgloss.Height(b)
+	}
+	if belowRows > 0 {
+		contentStr = clipLines(contentStr, m.Height-7-belowRows)
+	}
+	content := styles.Panel.Width(m.Width - 2).Height(m.Height - 5 - belowRows).Render(contentStr)
+
+	// Footer: the essentials, everything else is in the help overlay
+	keys := []string{styles.RenderKey("Ctrl+<->", "View")}
+	if m.CurrentView == ViewRunner {
+		keys = append(keys, styles.RenderKey("Ctrl+R", "Run"), styles.RenderKey("Ctrl+W", "Save"), styles.RenderKey("Ctrl+O", "Load"))
+	} else {
+		keys = append(keys, styles.RenderKey("Ctrl+S", "Stop"), styles.RenderKey("Ctrl+P", "Export"))
+		if len(m.Sessions) > 1 {
+			keys = append(keys, styles.RenderKey("1-9", "Switch Run"))
+		}
+	}
+	if m.Tour > 0 && m.CurrentView != ViewRunner {
+		keys = append(keys, styles.RenderKey("Enter", "Next Tip"))
+	}
+	if len(m.Toasts) > 0 || m.Tour > 0 {
+		keys = append(keys, styles.RenderKey("Ctrl+X", "Dismiss"))
 	}
+	keys = append(keys, styles.RenderKey("F1", "Help"), styles.RenderKey("Ctrl+Q", "Quit"))
+	footer := styles.FooterBase.Width(m.Width).Render(strings.Join(keys, "   "))
 
-	return lipgloss.JoinVertical(lipgloss.Left, navBar, content, footer)
+	// The tour tip and notifications stack above the footer
+	parts := append([]string{navBar, content}, below...)
+	return lipgloss.JoinVertical(lipgloss.Left, append(parts, footer)...)
 }
@@ -0,0 +1,294 @@
+package app
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"steadyq/internal/plan"
+	"steadyq/internal/report"
+	"steadyq/internal/runner"
+	"steadyq/internal/stats"
+)
+
+// TimelinePoint aggregates one second of results
+type TimelinePoint struct {
+	Second   int64   `json:"second"` // Unix seconds
+	Requests int     `json:"requests"`
+	Success  int     `json:"success"`
+	Fail     int     `json:"fail"`
+	Bytes    int64   `json:"bytes"`
+	P50      float64 `json:"p50_ms"`
+	P99      float64 `json:"p99_ms"`
+	Max      float64 `json:"max_ms"`
+}
+
+// ExportBundle writes every artifact of a run into one zip: raw CSV, raw JSON,
+// summary JSON, run metadata (host, version, git SHA), per-second timeline JSON, interval histogram snapshots and their percentiles CSV, HDR histograms (.hgrm), the plan used,
+// the generator's own health and, when the target was monitored, its CPU/memory timeline.
+func ExportBundle(r *runner.Runner, filename string) error {
+	results, cfg := r.SnapshotResults(), r.Cfg
+	if len(results) == 0 {
+		return fmt.Errorf("no results to bundle")
+	}
+
+	// Reuse the file exporters via a scratch dir, then zip the files
+	tmp, err := os.MkdirTemp("", "steadyq-bundle-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	base := strings.TrimSuffix(filepath.Base(filename), ".zip")
+	if err := ExportCSV(results, filepath.Join(tmp, "results.csv")); err != nil {
+		return err
+	}
+	if err := ExportJSON(results, filepath.Join(tmp, "results.json")); err != nil {
+		return err
+	}
+	summary := report.Summarize(results, 0)
+	summary.Metadata = &r.Meta
+	summary.Percentiles = report.ServicePercentiles(results, r.Cfg.GetPercentiles())
+	mirrored := r.MirrorResults()
+	summary.Mirror = report.CompareMirror(results, mirrored)
+	inflightAvg, inflightMax := r.Stats.InflightAvg()
+	summary.Concurrency = report.LittlesLaw(summary, results, inflightAvg, inflightMax)
+	if len(mirrored) > 0 {
+		if err := ExportCSV(mirrored, filepath.Join(tmp, "mirror.csv")); err != nil {
+			return err
+		}
+	}
+	if err := writeJSON(filepath.Join(tmp, "summary.json"), summary); err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(tmp, "metadata.json"), r.Meta); err != nil {
+		return err
+	}
+	if err := writeJSON(filepath.Join(tmp, "timeline.json"), Timeline(results)); err != nil {
+		return err
+	}
+	snaps := r.Stats.GetSnapshots()
+	if err := writeJSON(filepath.Join(tmp, "intervals.json"), snaps); err != nil {
+		return err
+	}
+	if len(snaps) > 0 {
+		if err := ExportPercentiles(snaps, r.Cfg.GetPercentiles(), filepath.Join(tmp, "percentiles.csv")); err != nil {
+			return err
+		}
+	}
+	if cfg.RequestIDHeader != "" {
+		if err := ExportFailures(results, filepath.Join(tmp, "failures.csv")); err != nil {
+			return err
+		}
+	}
+	if target := TargetSamples(r); len(target) > 0 {
+		if err := writeJSON(filepath.Join(tmp, "target.json"), TargetTimeline(target, results)); err != nil {
+			return err
+		}
+	}
+	if r.Self != nil {
+		if err := writeJSON(filepath.Join(tmp, "generator.json"), r.Self.Samples(0)); err != nil {
+			return err
+		}
+	}
+	p := plan.Plan{Name: base, SavedAt: time.Now(), Config: runner.RedactConfig(cfg)}
+	if err := plan.WriteFile(filepath.Join(tmp, "plan.json"), p); err != nil {
+		return err
+	}
+
+	service, total := stats.NewSafeHistogram(), stats.NewSafeHistogram()
+	for _, res := range results {
+		service.RecordValue(res.ServiceTime.Microseconds())
+		total.RecordValue(res.Latency.Microseconds())
+	}
+	if err := writeHistogram(filepath.Join(tmp, "service_time.hgrm"), service); err != nil {
+		return err
+	}
+	if err := writeHistogram(filepath.Join(tmp, "total_time.hgrm"), total); err != nil {
+		return err
+	}
+
+	return zipDir(tmp, base, filename)
+}
+
+// Timeline buckets results per second of their timestamp
+func Timeline(results []runner.ExperimentResult) []TimelinePoint {
+	type bucket struct {
+		point TimelinePoint
+		lats  []float64
+	}
+	buckets := make(map[int64]*bucket)
+	for _, r := range results {
+		sec := r.TimeStamp.Unix()
+		b, ok := buckets[sec]
+		if !ok {
+			b = &bucket{point: TimelinePoint{Second: sec}}
+			buckets[sec] = b
+		}
+		b.point.Requests++
+		if r.Success {
+			b.point.Success++
+		} else {
+			b.point.Fail++
+		}
+		b.point.Bytes += r.Bytes
+		b.lats = append(b.lats, float64(r.Latency.Microseconds())/1000.0)
+	}
+
+	points := make([]TimelinePoint, 0, len(buckets))
+	for _, b := range buckets {
+		sort.Float64s(b.lats)
+		n := len(b.lats)
+		b.point.P50 = b.lats[int(0.50*float64(n-1))]
+		b.point.P99 = b.lats[int(0.99*float64(n-1))]
+		b.point.Max = b.lats[n-1]
+		points = append(points, b.point)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Second < points[j].Second })
+	return points
+}
+
+// ExportIntervals writes the run's interval snapshots (see stats.IntervalSnapshot)
+func ExportIntervals(r *runner.Runner, filename string) error {
+	snaps := r.Stats.GetSnapshots()
+	if len(snaps) == 0 {
+		return fmt.Errorf("no interval snapshots")
+	}
+	return writeJSON(filename, snaps)
+}
+
+// ExportPercentiles writes one CSV row per interval snapshot with its rate,
+// error rate and service time at each of ps (runner.Config.GetPercentiles),
+// for plotting latency over time without recomputing percentiles from the
+// raw rows. Percentiles are read from the interval's histogram. The sampled
+// in-flight average and peak come with their Little's Law estimate (λW),
+// then the bytes sent and received.
+func ExportPercentiles(snaps []stats.IntervalSnapshot, ps []float64, filename string) error {
+	if len(snaps) == 0 {
+		return fmt.Errorf("no interval snapshots")
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	header := []string{"start", "elapsed_s", "requests", "rps", "error_pct"}
+	for _, p := range ps {
+		header = append(header, "p"+strconv.FormatFloat(p, 'g', -1, 64)+"_ms")
+	}
+	w.Write(append(header, "max_ms", "inflight_avg", "inflight_max", "littles_l", "sent_bytes", "recv_bytes"))
+	for _, iv := range snaps {
+		rps, errPct := 0.0, 0.0
+		if d := iv.End.Sub(iv.Start).Seconds(); d > 0 {
+			rps = float64(iv.Requests) / d
+		}
+		if iv.Requests > 0 {
+			errPct = float64(iv.Fail) / float64(iv.Requests) * 100
+		}
+		row := []string{
+			iv.Start.UTC().Format(time.RFC3339),
+			fmt.Sprintf("%.0f", iv.Start.Sub(snaps[0].Start).Seconds()),
+			strconv.FormatUint(iv.Requests, 10),
+			fmt.Sprintf("%.2f", rps),
+			fmt.Sprintf("%.2f", errPct),
+		}
+		h, err := stats.DecodeHistogram(iv.Histogram)
+		for _, p := range ps {
+			v := 0.0
+			if err == nil {
+				v = float64(h.ValueAtQuantile(p)) / 1000
+			}
+			row = append(row, fmt.Sprintf("%.2f", v))
+		}
+		littleL := 0.0 // λW: completions per second times mean service time
+		if err == nil {
+			littleL = rps * h.Mean() / 1e6
+		}
+		w.Write(append(row,
+			fmt.Sprintf("%.2f", iv.MaxMs),
+			fmt.Sprintf("%.2f", iv.InflightAvg),
+			strconv.FormatInt(iv.InflightMax, 10),
+			fmt.Sprintf("%.2f", littleL),
+			strconv.FormatUint(iv.SentBytes, 10),
+			strconv.FormatUint(iv.RecvBytes, 10),
+		))
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// LoadIntervals reads interval snapshots written by ExportIntervals or a bundle
+func LoadIntervals(filename string) ([]stats.IntervalSnapshot, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var snaps []stats.IntervalSnapshot
+	if err := json.Unmarshal(data, &snaps); err != nil {
+		return nil, err
+	}
+	return snaps, nil
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func writeHistogram(path string, h *stats.SafeHistogram) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return h.WritePercentiles(f)
+}
+
+// zipDir stores every file of dir under prefix/ in a new zip archive
+func zipDir(dir, prefix, filename string) error {
+	out, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     prefix + "/" + e.Name(),
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(w, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
@@ -4,34 +4,21 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"steadyq/internal/report"
 	"steadyq/internal/runner"
 )
 
-type SummaryReport struct {
-	TotalRequests uint64         `json:"total_requests"`
-	TotalSuccess  uint64         `json:"total_success"`
-	TotalFail     uint64         `json:"total_fail"`
-	TotalBytes    int64          `json:"total_bytes"`
-	P50           float64        `json:"p50_ms"`
-	P90           float64        `json:"p90_ms"`
-	P95           float64        `json:"p95_ms"`
-	P99           float64        `json:"p99_ms"`
-	Mean          float64        `json:"mean_ms"`
-	Max           float64        `json:"max_ms"`
-	Min           float64        `json:"min_ms"`
-	StatusCodes   map[int]int    `json:"status_codes"`
-	Errors        map[string]int `json:"errors"`
-	Duration      time.Duration  `json:"duration"`
-	AverageRPS    float64        `json:"avg_rps"`
-}
-
 // ExportCSV exports results to a JMeter-compatible CSV file.
 // Schema: timeStamp,elapsed,label,responseCode,responseMessage,threadName,dataType,success,failureMessage,bytes,sentBytes,grpThreads,allThreads,URL,Latency,IdleTime,Connect
+// plus a trailing tags column (k=v;k=v) when the run has tags.
 func ExportCSV(results []runner.ExperimentResult, filename string) error {
 	f, err := os.Create(filename)
 	if err != nil {
@@ -48,6 +35,10 @@ func ExportCSV(results []runner.ExperimentResult, filename string) error {
 		"threadName", "dataType", "success", "failureMessage", "bytes",
 		"sentBytes", "grpThreads", "allThreads", "URL", "Latency", "IdleTime", "Connect",
 	}
+	withTags := hasTags(results)
+	if withTags {
+		header = append(header, "tags")
+	}
 	if err := w.Write(header); err != nil {
 		return err
 	}
@@ -72,21 +63,24 @@ func ExportCSV(results []runner.ExperimentResult, filename string) error {
 		record := []string{
 			ts,
 			elapsed,
-			"SteadyQ Request", // Label
+			report.Label(res),
 			strconv.Itoa(res.Status),
 			httpStatusText(res.Status),
 			"User-" + res.UserID, // Thread Name
 			"text",               // DataType
 			successStr,
 			errMsg,
-			strconv.FormatInt(res.Bytes, 10),
-			"0", // Sent bytes (not tracked currently)
+			strconv.FormatInt(res.RecvHeaderBytes+res.Bytes, 10), // Like JMeter, heads included
+			strconv.FormatInt(res.SentHeaderBytes+res.SentBytes, 10),
 			"1", // grpThreads (mock)
 			"1", // allThreads (mock)
-			"",  // URL (not in Result struct, could be added later)
-			fmt.Sprintf("%d", res.Latency.Milliseconds()),   // Latency
-			fmt.Sprintf("%d", res.QueueWait.Milliseconds()), // IdleTime (QueueWait)
-			"0", // Connect time (part of ServiceTime, not separated)
+			res.URL,
+			fmt.Sprintf("%d", res.Latency.Milliseconds()),     // Latency
+			fmt.Sprintf("%d", res.QueueWait.Milliseconds()),   // IdleTime (QueueWait)
+			fmt.Sprintf("%d", res.ConnectTime.Milliseconds()), // Connect (socket modes, new HTTP connections)
+		}
+		if withTags {
+			record = append(record, FormatTags(res.Tags))
 		}
 
 		if err := w.Write(record); err != nil {
@@ -94,7 +88,8 @@ func ExportCSV(results []runner.ExperimentResult, filename string) error {
 		}
 	}
 
-	return nil
+	w.Flush()
+	return w.Error()
 }
 
 // ExportJSON exports results to a JSON file.
@@ -106,108 +101,158 @@ func ExportJSON(results []runner.ExperimentResult, filename string) error {
 	return os.WriteFile(filename, data, 0644)
 }
 
-func ExportSummary(results []runner.ExperimentResult, baseFilename string) error {
-	if len(results) == 0 {
+// ExportSummary writes the run summary (see report.Summarize) as JSON and CSV
+func ExportSummary(sum report.Summary, meta *runner.RunMetadata, baseFilename string) error {
+	if sum.TotalRequests == 0 {
 		return fmt.Errorf("no results to summarize")
 	}
-
-	report := CalculateSummary(results)
+	sum.Metadata = meta
 
 	// JSON Summary
-	jsonData, _ := json.MarshalIndent(report, "", "  ")
-	os.WriteFile(baseFilename+"_summary.json", jsonData, 0644)
+	jsonData, err := json.MarshalIndent(sum, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(baseFilename+"_summary.json", jsonData, 0644); err != nil {
+		return err
+	}
 
 	// CSV Summary (Simple key-value)
-	f, _ := os.Create(baseFilename + "_summary.csv")
+	f, err := os.Create(baseFilename + "_summary.csv")
+	if err != nil {
+		return err
+	}
 	defer f.Close()
 	w := csv.NewWriter(f)
 	defer w.Flush()
 
 	w.Write([]string{"Metric", "Value"})
-	w.Write([]string{"Total Requests", strconv.FormatUint(report.TotalRequests, 10)})
-	w.Write([]string{"Success", strconv.FormatUint(report.TotalSuccess, 10)})
-	w.Write([]string{"Fail", strconv.FormatUint(report.TotalFail, 10)})
-	w.Write([]string{"P50 ms", fmt.Sprintf("%.2f", report.P50)})
-	w.Write([]string{"P90 ms", fmt.Sprintf("%.2f", report.P90)})
-	w.Write([]string{"P95 ms", fmt.Sprintf("%.2f", report.P95)})
-	w.Write([]string{"P99 ms", fmt.Sprintf("%.2f", report.P99)})
-	w.Write([]string{"Mean ms", fmt.Sprintf("%.2f", report.Mean)})
-	w.Write([]string{"Max ms", fmt.Sprintf("%.2f", report.Max)})
-	w.Write([]string{"Min ms", fmt.Sprintf("%.2f", report.Min)})
-	w.Write([]string{"Avg RPS", fmt.Sprintf("%.2f", report.AverageRPS)})
-
-	return nil
-}
+	w.Write([]string{"Total Requests", strconv.FormatUint(sum.TotalRequests, 10)})
+	w.Write([]string{"Success", strconv.FormatUint(sum.TotalSuccess, 10)})
+	w.Write([]string{"Fail", strconv.FormatUint(sum.TotalFail, 10)})
+	w.Write([]string{"Error Rate %", fmt.Sprintf("%.2f", sum.ErrorRate)})
+	w.Write([]string{"P50 ms", fmt.Sprintf("%.2f", sum.P50)})
+	w.Write([]string{"P90 ms", fmt.Sprintf("%.2f", sum.P90)})
+	w.Write([]string{"P95 ms", fmt.Sprintf("%.2f", sum.P95)})
+	w.Write([]string{"P99 ms", fmt.Sprintf("%.2f", sum.P99)})
+	w.Write([]string{"Mean ms", fmt.Sprintf("%.2f", sum.Mean)})
+	w.Write([]string{"Max ms", fmt.Sprintf("%.2f", sum.Max)})
+	w.Write([]string{"Min ms", fmt.Sprintf("%.2f", sum.Min)})
+	w.Write([]string{"StdDev ms", fmt.Sprintf("%.2f", sum.StdDev)})
+	w.Write([]string{"Trimmed Mean ms", fmt.Sprintf("%.2f", sum.TrimmedMean)})
+	w.Write([]string{"Service P50 ms", fmt.Sprintf("%.2f", sum.Service.P50)})
+	w.Write([]string{"Service P99 ms", fmt.Sprintf("%.2f", sum.Service.P99)})
+	w.Write([]string{"Service Min ms", fmt.Sprintf("%.2f", sum.Service.Min)})
+	w.Write([]string{"Service Mean ms", fmt.Sprintf("%.2f", sum.Service.Mean)})
+	w.Write([]string{"Service Trimmed Mean ms", fmt.Sprintf("%.2f", sum.Service.TrimmedMean)})
+	w.Write([]string{"Service StdDev ms", fmt.Sprintf("%.2f", sum.Service.StdDev)})
+	for _, p := range sum.Percentiles {
+		if p.P != 50 && p.P != 99 {
+			w.Write([]string{fmt.Sprintf("Service P%g ms", p.P), fmt.Sprintf("%.2f", p.Ms)})
+		}
+	}
+	w.Write([]string{"Sent Bytes", strconv.FormatInt(sum.SentBytes, 10)})
+	w.Write([]string{"Received Bytes", strconv.FormatInt(sum.RecvBytes, 10)})
+	if c := sum.Concurrency; c != nil {
+		w.Write([]string{"Inflight Avg", fmt.Sprintf("%.2f", c.InflightAvg)})
+		w.Write([]string{"Inflight Max", strconv.FormatInt(c.InflightMax, 10)})
+		w.Write([]string{"Little's Law L", fmt.Sprintf("%.2f", c.LittleL)})
+	}
+	if sum.NewConns+sum.ReusedConns > 0 {
+		w.Write([]string{"Connection Reuse %", fmt.Sprintf("%.2f", sum.ReuseRate())})
+		w.Write([]string{"New Connections", strconv.FormatUint(sum.NewConns, 10)})
+		w.Write([]string{"Connect P99 ms", fmt.Sprintf("%.2f", sum.ConnectMs.P99)})
+	}
+	for _, version := range slices.Sorted(maps.Keys(sum.TLSVersions)) {
+		w.Write([]string{"TLS " + version, strconv.Itoa(sum.TLSVersions[version])})
+	}
+	if sum.TLSHandshakes > 0 {
+		w.Write([]string{"TLS Handshakes", strconv.FormatUint(sum.TLSHandshakes, 10)})
+		w.Write([]string{"TLS Resumed", strconv.FormatUint(sum.TLSResumed, 10)})
+	}
+	if sum.TLSFullMs != nil {
+		w.Write([]string{"TLS Full Handshake P99 ms", fmt.Sprintf("%.2f", sum.TLSFullMs.P99)})
+	}
+	if sum.TLSResumedMs != nil {
+		w.Write([]string{"TLS Resumed Handshake P99 ms", fmt.Sprintf("%.2f", sum.TLSResumedMs.P99)})
+	}
+	for _, addr := range slices.Sorted(maps.Keys(sum.ByAddr)) {
+		w.Write([]string{"Peer " + addr, strconv.FormatUint(sum.ByAddr[addr].Requests, 10)})
+	}
+	w.Write([]string{"Apdex", fmt.Sprintf("%.3f", sum.Apdex)})
+	w.Write([]string{"Avg RPS", fmt.Sprintf("%.2f", sum.AverageRPS)})
+	for _, code := range slices.Sorted(maps.Keys(sum.StatusCodes)) {
+		w.Write([]string{fmt.Sprintf("Status %d", code), strconv.Itoa(sum.StatusCodes[code])})
+	}
+	for _, warning := range sum.Warnings {
+		w.Write([]string{"Warning", warning})
+	}
+	if meta != nil {
+		w.Write([]string{"Started", meta.StartedAt.Format(time.RFC3339)})
+		w.Write([]string{"Host", meta.Hostname})
+		w.Write([]string{"SteadyQ Version", meta.Version})
+		w.Write([]string{"Git SHA", meta.GitSHA})
+	}
 
-func CalculateSummary(results []runner.ExperimentResult) SummaryReport {
-	var totalBytes int64
-	var totalSuccess uint64
-	var latencies []float64
-	statusCodes := make(map[int]int)
-	errors := make(map[string]int)
+	w.Flush()
+	return w.Error()
+}
 
-	minTime := results[0].TimeStamp
-	maxTime := results[0].TimeStamp
+// ExportFailures writes the failed requests with their request IDs
+// (Config.RequestIDHeader) and times, for finding them in the server's logs
+func ExportFailures(results []runner.ExperimentResult, filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	defer w.Flush()
 
-	for _, r := range results {
-		if r.TimeStamp.Before(minTime) {
-			minTime = r.TimeStamp
+	w.Write([]string{"time", "request_id", "status", "error", "latency_ms", "url", "remote_addr"})
+	for _, res := range results {
+		if res.Success {
+			continue
 		}
-		if r.TimeStamp.After(maxTime) {
-			maxTime = r.TimeStamp
+		errMsg := ""
+		if res.Err != nil {
+			errMsg = res.Err.Error()
 		}
+		w.Write([]string{
+			res.TimeStamp.UTC().Format(time.RFC3339Nano),
+			res.RequestID,
+			strconv.Itoa(res.Status),
+			errMsg,
+			fmt.Sprintf("%.2f", float64(res.Latency.Microseconds())/1000),
+			res.URL,
+			res.RemoteAddr,
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
 
-		if r.Success {
-			totalSuccess++
-		}
-		totalBytes += r.Bytes
-		lat := float64(r.Latency.Microseconds()) / 1000.0
-		latencies = append(latencies, lat)
-		statusCodes[r.Status]++
-		if r.Err != nil {
-			errors[r.Err.Error()]++
+func hasTags(results []runner.ExperimentResult) bool {
+	for _, res := range results {
+		if len(res.Tags) > 0 {
+			return true
 		}
 	}
+	return false
+}
 
-	sort.Float64s(latencies)
-	count := len(latencies)
-
-	getQuantile := func(q float64) float64 {
-		if count == 0 {
-			return 0
-		}
-		idx := int(q * float64(count-1))
-		return latencies[idx]
-	}
-
-	sum := 0.0
-	for _, l := range latencies {
-		sum += l
-	}
-
-	dur := maxTime.Sub(minTime)
-	avgRPS := 0.0
-	if dur.Seconds() > 0 {
-		avgRPS = float64(count) / dur.Seconds()
-	}
-
-	return SummaryReport{
-		TotalRequests: uint64(count),
-		TotalSuccess:  totalSuccess,
-		TotalFail:     uint64(count) - totalSuccess,
-		TotalBytes:    totalBytes,
-		P50:           getQuantile(0.50),
-		P90:           getQuantile(0.90),
-		P95:           getQuantile(0.95),
-		P99:           getQuantile(0.99),
-		Mean:          sum / float64(count),
-		Max:           latencies[count-1],
-		Min:           latencies[0],
-		StatusCodes:   statusCodes,
-		Errors:        errors,
-		Duration:      dur,
-		AverageRPS:    avgRPS,
+// FormatTags renders tags as sorted k=v pairs joined by ';'
+func FormatTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + tags[k]
 	}
+	return strings.Join(parts, ";")
 }
 
 func httpStatusText(code int) string {
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+
+	"steadyq/internal/tui/styles"
+)
+
+// helpSection is a titled list of key/description pairs
+type helpSection struct {
+	Title string
+	Rows  [][2]string
+}
+
+// keymap lists every binding, per view
+var keymap = []helpSection{
+	{"Global", [][2]string{
+		{"Ctrl+←/→", "Switch view (Runner, Dashboard, Heatmap)"},
+		{"Ctrl+D", "Go to the Dashboard"},
+		{"Ctrl+S", "Stop the focused run"},
+		{"Ctrl+P", "Export results (Dashboard)"},
+		{"Ctrl+E", "Export a zip bundle of the focused run"},
+		{"Ctrl+T", "Cycle theme"},
+		{"Ctrl+X", "Dismiss notifications"},
+		{"F1 / ?", "This help (? outside the Runner form)"},
+		{"Ctrl+Q", "Quit"},
+	}},
+	{"Runner", [][2]string{
+		{"Tab / ↑↓", "Next / previous field"},
+		{"Enter", "Next field (new line in Headers/Body)"},
+		{"Space", "Toggle type, mode, pacing and on/off fields"},
+		{"Ctrl+R", "Run (asks above 1000 RPS / 500 users)"},
+		{"Ctrl+W", "Save the form as a plan"},
+		{"Ctrl+O", "Load a plan (p by path: run dir, bundle; d delete, a archive), then replay or edit"},
+	}},
+	{"Dashboard", [][2]string{
+		{"1-9", "Switch between concurrent runs"},
+		{"e", "Error drill-down (↑↓ select, Esc close)"},
+		{"p t n w l o c x b m g", "Collapse/expand panels"},
+		{"a", "Expand all panels"},
+		{"Wheel", "Scroll"},
+	}},
+}
+
+// glossary explains the metrics shown on the dashboard
+var glossary = [][2]string{
+	{"Open loop (RPS)", "Requests start on schedule whether or not earlier ones finished, like real independent traffic. A slow server builds up in-flight requests."},
+	{"Closed loop (Users)", "Each user sends, waits for the response, thinks, repeats. A slow server lowers the request rate instead (coordinated omission)."},
+	{"Service Time", "Request sent to response read: what the server and network took."},
+	{"Queue Wait", "Scheduled start to actual send: time lost in the generator. High values mean the client, not the server, is the bottleneck."},
+	{"Latency", "Queue Wait + Service Time, what a user would have experienced."},
+	{"P50 / P99", "Half / 99% of requests were faster than this."},
+	{"Inflight", "Requests sent and not yet answered."},
+	{"Little's Law", "L = λW: average in flight = completions per second × mean service time. Measured and derived L should agree once the load is steady."},
+	{"Iteration", "Users mode: one request plus its think time."},
+	{"Stuck Users", "Users mode: users waiting on a request longer than the stuck threshold (default 5s). A deadline per iteration (--iteration-timeout) frees them sooner."},
+	{"Not Sent (Gen)", "Open-loop requests skipped because the generator fell behind."},
+	{"Force-Cancelled", "In flight when the run was stopped or the graceful stop ran out; not counted as failures."},
+	{"Result Buffer", "The last --max-results results (default 1,000,000) are kept for the summary and exports; older ones are dropped and counted so memory stays flat."},
+}
+
+// helpContent renders the keymap and glossary for the help overlay, in two
+// columns when width allows
+func helpContent(width int) string {
+	keyWidth := 0
+	for _, sec := range keymap {
+		for _, row := range sec.Rows {
+			keyWidth = max(keyWidth, lipgloss.Width(row[0]))
+		}
+	}
+
+	var keys []string
+	for _, sec := range keymap {
+		keys = append(keys, styles.Active.Render(sec.Title))
+		for _, row := range sec.Rows {
+			keys = append(keys, styles.KeyKey.Width(keyWidth+2).Render(row[0])+styles.KeyDesc.Render(row[1]))
+		}
+		keys = append(keys, "")
+	}
+	keyCol := strings.Join(keys, "\n")
+
+	termWidth := width - lipgloss.Width(keyCol) - 4
+	twoCols := termWidth >= 30
+	if !twoCols {
+		termWidth = width
+	}
+	terms := []string{styles.Active.Render("Glossary")}
+	for _, g := range glossary {
+		terms = append(terms, styles.Text.Width(termWidth).Render(styles.Value.Render(g[0])+"  "+g[1]))
+	}
+	termCol := strings.Join(terms, "\n")
+
+	if twoCols {
+		return lipgloss.JoinHorizontal(lipgloss.Top, keyCol, "    ", termCol)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, keyCol, termCol)
+}
+
+// openHelp shows the help overlay sized to the content area
+func (m *Model) openHelp() {
+	m.ShowHelp = true
+	m.Help = viewport.New(m.Width-6, max(1, m.Height-8))
+	m.Help.SetContent(helpContent(m.Width - 6))
+}
+
+// helpView is the help overlay with its closing hint
+func (m Model) helpView() string {
+	hint := "Esc, ? or F1 to close"
+	if !m.Help.AtTop() || !m.Help.AtBottom() {
+		hint += " · ↑↓ PgUp/PgDn to scroll"
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, m.Help.View(), styles.Subtle.Render(hint))
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"steadyq/internal/runner"
+	"steadyq/internal/tui/styles"
+	"steadyq/internal/tui/views"
+)
+
+// loadedConfig is a config read from a plan, run directory or bundle (see
+// LoadConfig), before the user chose what to do with it
+type loadedConfig struct {
+	Cfg    runner.Config
+	Source string // "plan 'checkout'", "run 01J...", "bundle run.zip"
+}
+
+// updateLoadChoice handles the keys of the load choice: replay the config
+// as it is, or put it in the Runner form to edit first
+func (m Model) updateLoadChoice(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	loaded := *m.PendingLoad
+	m.PendingLoad = nil
+	switch msg.String() {
+	case "r", "R":
+		// The form shows what is running, marked with its source
+		m.fillRunner(loaded)
+		return m, tea.Batch(m.RunnerView.Init(), m.requestLaunch(loaded.Cfg))
+	case "e", "E", "enter":
+		m.fillRunner(loaded)
+		return m, tea.Batch(m.notify(ToastInfo, "Loaded %s: edit, then Ctrl+R to run", loaded.Source), m.RunnerView.Init())
+	}
+	return m, m.notify(ToastInfo, "Load cancelled.")
+}
+
+// fillRunner replaces the Runner form with loaded and shows it
+func (m *Model) fillRunner(loaded loadedConfig) {
+	m.RunnerView = views.NewRunnerView(loaded.Cfg)
+	m.RunnerView.Source = loaded.Source
+	m.RunnerView, _ = m.RunnerView.Update(tea.WindowSizeMsg{Width: m.Width, Height: m.Height - 6})
+	m.CurrentView = ViewRunner
+}
+
+// loadChoiceView asks whether to replay a loaded config now or edit it first
+func (m Model) loadChoiceView() string {
+	cfg := m.PendingLoad.Cfg
+	target := fmt.Sprintf("%d RPS", cfg.TargetRPS)
+	if cfg.Mode == "users" {
+		target = fmt.Sprintf("%d users", cfg.NumUsers)
+	}
+	body := lipgloss.JoinVertical(lipgloss.Left,
+		styles.Active.Render("↺ Loaded "+m.PendingLoad.Source),
+		"",
+		styles.Text.Render("URL:    "+cfg.URL),
+		styles.Text.Render(fmt.Sprintf("Load:   %s for %ds", target, cfg.RampUp+cfg.SteadyDur+cfg.RampDown)),
+		"",
+		styles.Subtle.Render("[R] Replay now  [E/Enter] Edit and run  [any other key] Cancel"),
+	)
+	return styles.Box.BorderForeground(styles.ColorPrimary).Padding(1, 2).Render(body)
+}
@@ -0,0 +1,188 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"steadyq/internal/report"
+	"steadyq/internal/stats"
+)
+
+// MergeReplays combines runs of independent generators that hit the same
+// target at the same time. Intervals starting in the same step (the longest
+// snapshot interval of the inputs) are added up and their histograms merged,
+// so the combined percentiles are exact rather than averaged. Status and
+// error counts are summed when every input is a bundle (with a summary).
+func MergeReplays(runs []Replay) (Replay, error) {
+	if len(runs) == 0 {
+		return Replay{}, fmt.Errorf("nothing to merge")
+	}
+
+	step := time.Second
+	for _, rp := range runs {
+		if len(rp.Intervals) > 0 {
+			iv := rp.Intervals[0]
+			step = max(step, iv.End.Sub(iv.Start).Round(time.Second))
+		}
+	}
+
+	type bucket struct {
+		snap stats.IntervalSnapshot
+		hist *stats.SafeHistogram
+	}
+	buckets := make(map[time.Time]*bucket)
+	for _, rp := range runs {
+		for _, iv := range rp.Intervals {
+			start := iv.Start.Truncate(step)
+			b, ok := buckets[start]
+			if !ok {
+				b = &bucket{
+					snap: stats.IntervalSnapshot{Start: start, End: start.Add(step)},
+					hist: stats.NewSafeHistogram(),
+				}
+				buckets[start] = b
+			}
+			b.snap.Requests += iv.Requests
+			b.snap.Success += iv.Success
+			b.snap.Fail += iv.Fail
+			b.snap.Bytes += iv.Bytes
+			b.snap.SentBytes += iv.SentBytes
+			b.snap.RecvBytes += iv.RecvBytes
+			// Generators' concurrency adds up; peaks need not coincide, so
+			// the summed peak is an upper bound
+			b.snap.InflightAvg += iv.InflightAvg * iv.End.Sub(iv.Start).Seconds() / step.Seconds()
+			b.snap.InflightMax += iv.InflightMax
+			h, err := stats.DecodeHistogram(iv.Histogram)
+			if err != nil {
+				return Replay{}, fmt.Errorf("interval at %s: %v", iv.Start.Format(time.RFC3339), err)
+			}
+			b.hist.Merge(h)
+		}
+	}
+
+	merged := Replay{Config: runs[0].Config}
+	for _, b := range buckets {
+		b.snap.P50Ms = float64(b.hist.ValueAtQuantile(50)) / 1000
+		b.snap.P90Ms = float64(b.hist.ValueAtQuantile(90)) / 1000
+		b.snap.P99Ms = float64(b.hist.ValueAtQuantile(99)) / 1000
+		b.snap.MaxMs = float64(b.hist.Max()) / 1000
+		b.snap.Histogram, _ = b.hist.Encode()
+		merged.Intervals = append(merged.Intervals, b.snap)
+	}
+	sort.Slice(merged.Intervals, func(i, j int) bool { return merged.Intervals[i].Start.Before(merged.Intervals[j].Start) })
+
+	sum := SummarizeIntervals(merged.Intervals, merged.Config.GetPercentiles())
+	for _, rp := range runs {
+		if rp.Summary == nil {
+			// A partial breakdown would pass for the whole run
+			clear(sum.StatusCodes)
+			clear(sum.Errors)
+			break
+		}
+		for code, n := range rp.Summary.StatusCodes {
+			sum.StatusCodes[code] += n
+		}
+		for err, n := range rp.Summary.Errors {
+			sum.Errors[err] += n
+		}
+	}
+	merged.Summary = &sum
+	return merged, checkReplay(merged)
+}
+
+// SummarizeIntervals derives a summary from interval snapshots alone: counts,
+// throughput and service-time percentiles, including each of ps. End-to-end
+// latencies and Apdex need the raw results and stay zero.
+func SummarizeIntervals(snaps []stats.IntervalSnapshot, ps []float64) report.Summary {
+	s := report.Summary{
+		StatusCodes: make(map[int]int),
+		Errors:      make(map[string]int),
+	}
+	if len(snaps) == 0 {
+		return s
+	}
+	total := stats.NewSafeHistogram()
+	first, last := snaps[0].Start, snaps[0].End
+	for _, iv := range snaps {
+		s.TotalRequests += iv.Requests
+		s.TotalSuccess += iv.Success
+		s.TotalFail += iv.Fail
+		s.TotalBytes += int64(iv.Bytes)
+		if iv.Start.Before(first) {
+			first = iv.Start
+		}
+		if iv.End.After(last) {
+			last = iv.End
+		}
+		if h, err := stats.DecodeHistogram(iv.Histogram); err == nil {
+			total.Merge(h)
+		}
+	}
+	if s.TotalRequests > 0 {
+		s.ErrorRate = float64(s.TotalFail) / float64(s.TotalRequests) * 100
+	}
+	s.Service = report.Latencies{
+		P50:         float64(total.ValueAtQuantile(50)) / 1000,
+		P90:         float64(total.ValueAtQuantile(90)) / 1000,
+		P95:         float64(total.ValueAtQuantile(95)) / 1000,
+		P99:         float64(total.ValueAtQuantile(99)) / 1000,
+		Mean:        total.Mean() / 1000,
+		Max:         float64(total.Max()) / 1000,
+		Min:         float64(total.Min()) / 1000,
+		StdDev:      total.StdDev() / 1000,
+		TrimmedMean: total.TrimmedMean() / 1000,
+	}
+	for _, p := range ps {
+		s.Percentiles = append(s.Percentiles, report.PercentileValue{P: p, Ms: float64(total.ValueAtQuantile(p)) / 1000})
+	}
+	s.Warnings = report.SampleWarnings(uint64(total.TotalCount()))
+	s.Duration = last.Sub(first)
+	if s.Duration > 0 {
+		s.AverageRPS = float64(s.TotalRequests) / s.Duration.Seconds()
+	}
+	return s
+}
+
+// ReplayBins returns the service time bins of a run's intervals (every
+// request, failures included) and its request and failure counts, for
+// report.CompareBins
+func ReplayBins(rp Replay, name string) (report.ComparedRun, []report.Bin) {
+	run := report.ComparedRun{Name: name}
+	total := stats.NewSafeHistogram()
+	for _, iv := range rp.Intervals {
+		run.Requests += iv.Requests
+		run.Fail += iv.Fail
+		if h, err := stats.DecodeHistogram(iv.Histogram); err == nil {
+			total.Merge(h)
+		}
+	}
+	values, counts := total.Counts()
+	bins := make([]report.Bin, len(values))
+	for i := range values {
+		bins[i] = report.Bin{Value: float64(values[i]) / 1000, Count: uint64(counts[i])}
+	}
+	return run, bins
+}
+
+// ExportMerged writes a merged run as <prefix>_intervals.json (replayable),
+// <prefix>_percentiles.csv, <prefix>_summary.{json,csv} and
+// <prefix>_service_time.hgrm
+func ExportMerged(rp Replay, prefix string) error {
+	if err := writeJSON(prefix+"_intervals.json", rp.Intervals); err != nil {
+		return err
+	}
+	if err := ExportPercentiles(rp.Intervals, rp.Config.GetPercentiles(), prefix+"_percentiles.csv"); err != nil {
+		return err
+	}
+	if err := ExportSummary(*rp.Summary, nil, prefix); err != nil {
+		return err
+	}
+	total := stats.NewSafeHistogram()
+	for _, iv := range rp.Intervals {
+		if h, err := stats.DecodeHistogram(iv.Histogram); err == nil {
+			total.Merge(h)
+		}
+	}
+	return writeHistogram(prefix+"_service_time.hgrm", total)
+}
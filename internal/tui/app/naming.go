@@ -0,0 +1,62 @@
+package app
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"steadyq/internal/runner"
+)
+
+// Default report name templates (before the output directory is applied)
+const (
+	DefaultReportTemplate = "steadyq_report_{{date}}"
+	DefaultBundleTemplate = "steadyq_bundle_{{date}}"
+)
+
+// ReportPath expands a filename template and places it in dir, creating dir if needed.
+// Placeholders: {{date}} (20060102-150405), {{name}} (run/plan name, default "steadyq"),
+// {{target}} (URL host and path, filesystem safe).
+func ReportPath(dir, tmpl string, cfg runner.Config, now time.Time) (string, error) {
+	name := cfg.Name
+	if name == "" {
+		name = "steadyq"
+	}
+	r := strings.NewReplacer(
+		"{{date}}", now.Format("20060102-150405"),
+		"{{name}}", safeName(name),
+		"{{target}}", safeName(targetName(cfg)),
+	)
+	base := r.Replace(tmpl)
+	if dir == "" {
+		return base, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, base), nil
+}
+
+func targetName(cfg runner.Config) string {
+	if cfg.URL == "" {
+		return "script"
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil || u.Host == "" {
+		return cfg.URL
+	}
+	return u.Host + strings.TrimSuffix(u.Path, "/")
+}
+
+// safeName keeps letters, digits, '-', '_' and '.', replacing the rest with '_'
+func safeName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,211 @@
+package app
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"steadyq/internal/plan"
+	"steadyq/internal/report"
+	"steadyq/internal/runner"
+	"steadyq/internal/stats"
+	"steadyq/internal/tui/views"
+)
+
+// Replay is a finished run loaded back from its interval snapshots
+type Replay struct {
+	Config    runner.Config
+	Intervals []stats.IntervalSnapshot
+	Summary   *report.Summary // Only present in bundles
+}
+
+// LoadReplay reads a bundle (.zip) or an _intervals.json file
+func LoadReplay(filename string) (Replay, error) {
+	var rp Replay
+	if !strings.HasSuffix(filename, ".zip") {
+		snaps, err := LoadIntervals(filename)
+		if err != nil {
+			return rp, err
+		}
+		rp.Intervals = snaps
+		return rp, checkReplay(rp)
+	}
+
+	zr, err := zip.OpenReader(filename)
+	if err != nil {
+		return rp, err
+	}
+	defer zr.Close()
+	for _, f := range zr.File {
+		var target any
+		switch path.Base(f.Name) {
+		case "intervals.json":
+			target = &rp.Intervals
+		case "summary.json":
+			rp.Summary = &report.Summary{}
+			target = rp.Summary
+		case "plan.json":
+			data, err := readZipFile(f)
+			if err != nil {
+				return rp, err
+			}
+			p, err := plan.Decode(data)
+			if err != nil {
+				return rp, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			rp.Config = p.Config
+			continue
+		default:
+			continue
+		}
+		if err := readZipJSON(f, target); err != nil {
+			return rp, err
+		}
+	}
+	return rp, checkReplay(rp)
+}
+
+// LoadConfig reads the config of a saved plan (name or path), a run
+// directory (its config.json) or a bundle (its plan.json), and describes
+// where it came from
+func LoadConfig(nameOrPath string) (runner.Config, string, error) {
+	if info, err := os.Stat(nameOrPath); err == nil && info.IsDir() {
+		p, err := plan.Load(filepath.Join(nameOrPath, "config.json"))
+		return p.Config, fmt.Sprintf("run %s", filepath.Base(nameOrPath)), err
+	}
+	if !strings.HasSuffix(nameOrPath, ".zip") {
+		p, err := plan.Load(nameOrPath)
+		return p.Config, fmt.Sprintf("plan '%s'", p.Name), err
+	}
+
+	zr, err := zip.OpenReader(nameOrPath)
+	if err != nil {
+		return runner.Config{}, "", err
+	}
+	defer zr.Close()
+	source := fmt.Sprintf("bundle %s", filepath.Base(nameOrPath))
+	for _, f := range zr.File {
+		if path.Base(f.Name) != "plan.json" {
+			continue
+		}
+		data, err := readZipFile(f)
+		if err != nil {
+			return runner.Config{}, source, err
+		}
+		p, err := plan.Decode(data)
+		if err != nil {
+			return runner.Config{}, source, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		return p.Config, source, nil
+	}
+	return runner.Config{}, source, fmt.Errorf("%s has no plan.json", nameOrPath)
+}
+
+func checkReplay(rp Replay) error {
+	if len(rp.Intervals) == 0 {
+		return fmt.Errorf("no interval snapshots to replay (bundles from older versions lack intervals.json)")
+	}
+	return nil
+}
+
+func readZipJSON(f *zip.File, v any) error {
+	data, err := readZipFile(f)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+	return nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// Stats rebuilds the final dashboard snapshot from the stored intervals
+func (rp Replay) Stats() runner.StatsSnapshot {
+	bounds := make([]int64, len(stats.HeatmapBoundsMs))
+	for i, b := range stats.HeatmapBoundsMs {
+		bounds[i] = b * 1000
+	}
+
+	var snap runner.StatsSnapshot
+	var inflight, secs float64
+	total := stats.NewSafeHistogram()
+	for _, iv := range rp.Intervals {
+		snap.Requests += iv.Requests
+		snap.Success += iv.Success
+		snap.Fail += iv.Fail
+		snap.Bytes += iv.Bytes
+		d := iv.End.Sub(iv.Start).Seconds()
+		inflight += iv.InflightAvg * d
+		secs += d
+		snap.InflightHistory = append(snap.InflightHistory, iv.InflightAvg)
+		snap.SentBytes += iv.SentBytes
+		snap.RecvBytes += iv.RecvBytes
+		snap.SentRate = append(snap.SentRate, uint64(float64(iv.SentBytes)/max(d, 1)))
+		snap.RecvRate = append(snap.RecvRate, uint64(float64(iv.RecvBytes)/max(d, 1)))
+		h, err := stats.DecodeHistogram(iv.Histogram)
+		if err != nil {
+			snap.Heatmap = append(snap.Heatmap, make([]int64, len(bounds)+1))
+			continue
+		}
+		total.Merge(h)
+		snap.Heatmap = append(snap.Heatmap, h.Buckets(bounds))
+	}
+
+	snap.P50ServiceMs = float64(total.ValueAtQuantile(50)) / 1000
+	snap.P90ServiceMs = float64(total.ValueAtQuantile(90)) / 1000
+	snap.P95ServiceMs = float64(total.ValueAtQuantile(95)) / 1000
+	snap.P99ServiceMs = float64(total.ValueAtQuantile(99)) / 1000
+	if secs > 0 {
+		snap.InflightAvg = inflight / secs
+	}
+	snap.InflightHistory = snap.InflightHistory[max(0, len(snap.InflightHistory)-60):]
+	snap.SentRate = snap.SentRate[max(0, len(snap.SentRate)-60):]
+	snap.RecvRate = snap.RecvRate[max(0, len(snap.RecvRate)-60):]
+	for _, p := range rp.Config.GetPercentiles() {
+		snap.ServicePercentiles = append(snap.ServicePercentiles, float64(total.ValueAtQuantile(p))/1000)
+	}
+	snap.MaxServiceMs = total.Max() / 1000
+	snap.MeanServiceMs = total.Mean() / 1000
+	snap.MinServiceMs = float64(total.Min()) / 1000
+	snap.StdDevServiceMs = total.StdDev() / 1000
+	snap.TrimmedMeanServiceMs = total.TrimmedMean() / 1000
+
+	if rp.Summary != nil {
+		snap.StatusCodes = rp.Summary.StatusCodes
+		snap.ErrorCounts = rp.Summary.Errors
+	}
+	return snap
+}
+
+// NewReplayModel opens the TUI on a read-only dashboard of a finished run.
+// Starting a new run from the Runner view replaces the replay.
+func NewReplayModel(rp Replay) Model {
+	updates := make(runner.StatsUpdateChan, 1)
+	m := NewModel(runner.NewRunner(rp.Config, updates), updates)
+
+	first, last := rp.Intervals[0], rp.Intervals[len(rp.Intervals)-1]
+	dash := views.NewDashboardView(rp.Config, 0, 0)
+	dash.Stats = rp.Stats()
+	dash.StartTime = first.Start
+	dash.EndTime = last.End
+	if dash.Duration <= 0 {
+		dash.Duration = last.End.Sub(first.Start)
+	}
+	m.Sessions[0].DashView = dash
+	m.CurrentView = ViewDashboard
+	return m
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"steadyq/internal/runner"
+	"steadyq/internal/tui/views"
+)
+
+// maxSessions matches the number keys (1-9) used to switch dashboards
+const maxSessions = 9
+
+// RunSession is one load test with its own Runner, stats stream and dashboard.
+// Several sessions can run concurrently against different targets.
+type RunSession struct {
+	ID      int
+	Runner  *runner.Runner
+	Updates runner.StatsUpdateChan
+
+	Started   bool
+	RunActive bool // Until the runner reports done
+	Draining  bool // Load stopped, in-flight requests finishing
+
+	DashView views.DashboardView
+}
+
+// StatsMsg carries a snapshot tagged with the session that produced it
+type StatsMsg struct {
+	SessionID int
+	Snap      runner.StatsSnapshot
+}
+
+func newSession(id int, r *runner.Runner, updates runner.StatsUpdateChan, width, height int) *RunSession {
+	return &RunSession{
+		ID:       id,
+		Runner:   r,
+		Updates:  updates,
+		DashView: views.NewDashboardView(r.Cfg, width, height),
+	}
+}
+
+func waitForUpdate(id int, sub runner.StatsUpdateChan) tea.Cmd {
+	return func() tea.Msg {
+		return StatsMsg{SessionID: id, Snap: <-sub}
+	}
+}
+
+func (s *RunSession) start(cfg runner.Config, width, height int) error {
+	if s.Runner.State() == runner.StateRunning || s.Runner.State() == runner.StateDraining {
+		return runner.ErrAlreadyRunning
+	}
+	s.Runner.Cfg = cfg
+	s.Runner.Stats.Reset()
+
+	s.Started = true
+	s.RunActive = true
+	s.Draining = false
+
+	// Keep the panel layout the user picked for this slot
+	collapsed := s.DashView.Collapsed
+	s.DashView = views.NewDashboardView(cfg, width, height)
+	if collapsed != nil {
+		s.DashView.Collapsed = collapsed
+	}
+
+	return s.Runner.Start(context.Background())
+}
+
+// stop ends load generation; the session stays active until the runner drains
+func (s *RunSession) stop() {
+	if s.RunActive {
+		s.Runner.Stop()
+		s.Draining = true
+	}
+}
+
+// Label is a short target name for the session strip
+func (s *RunSession) Label() string {
+	target := s.Runner.Cfg.URL
+	if u, err := url.Parse(target); err == nil && u.Host != "" {
+		target = u.Host + u.Path
+	}
+	if target == "" {
+		target = s.Runner.Cfg.Command
+	}
+	if len(target) > 28 {
+		target = target[:25] + "..."
+	}
+	return fmt.Sprintf("[%d] %s", s.ID, target)
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+
+	"steadyq/internal/monitor"
+	"steadyq/internal/runner"
+)
+
+// TargetPoint pairs one target monitor sample with client latency of the same second
+type TargetPoint struct {
+	Second     int64   `json:"second"` // Unix seconds
+	CPUPercent float64 `json:"cpu_percent"`
+	MemPercent float64 `json:"mem_percent"`
+	Requests   int     `json:"requests"`
+	P50        float64 `json:"p50_ms"`
+	P99        float64 `json:"p99_ms"`
+	Err        string  `json:"error,omitempty"`
+}
+
+// TargetTimeline correlates target CPU/memory with the client-side timeline
+func TargetTimeline(samples []monitor.Sample, results []runner.ExperimentResult) []TargetPoint {
+	bySecond := make(map[int64]TimelinePoint)
+	for _, p := range Timeline(results) {
+		bySecond[p.Second] = p
+	}
+
+	points := make([]TargetPoint, 0, len(samples))
+	for _, s := range samples {
+		sec := s.Time.Unix()
+		tp := bySecond[sec]
+		points = append(points, TargetPoint{
+			Second:     sec,
+			CPUPercent: s.CPUPercent,
+			MemPercent: s.MemPercent,
+			Requests:   tp.Requests,
+			P50:        tp.P50,
+			P99:        tp.P99,
+			Err:        s.Err,
+		})
+	}
+	return points
+}
+
+// TargetSamples returns the run's target monitor history, if any
+func TargetSamples(r *runner.Runner) []monitor.Sample {
+	if r.Monitor == nil {
+		return nil
+	}
+	return r.Monitor.Samples(0)
+}
+
+// ExportTargetCSV writes the target resource timeline next to client latency
+func ExportTargetCSV(samples []monitor.Sample, results []runner.ExperimentResult, filename string) error {
+	if len(samples) == 0 {
+		return fmt.Errorf("no target samples")
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	w.Write([]string{"timeStamp", "cpuPercent", "memPercent", "requests", "p50Ms", "p99Ms", "error"})
+	for _, p := range TargetTimeline(samples, results) {
+		w.Write([]string{
+			fmt.Sprintf("%d", p.Second*1000),
+			fmt.Sprintf("%.1f", p.CPUPercent),
+			fmt.Sprintf("%.1f", p.MemPercent),
+			fmt.Sprintf("%d", p.Requests),
+			fmt.Sprintf("%.2f", p.P50),
+			fmt.Sprintf("%.2f", p.P99),
+			p.Err,
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
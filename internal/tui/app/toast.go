@@ -0,0 +1,110 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"steadyq/internal/tui/styles"
+)
+
+// ToastLevel orders notifications by severity
+type ToastLevel int
+
+const (
+	ToastInfo ToastLevel = iota
+	ToastWarn
+	ToastError
+)
+
+// Toast is one notification shown above the footer
+type Toast struct {
+	ID    int
+	Level ToastLevel
+	Text  string
+	At    time.Time
+}
+
+// maxToasts is how many notifications are stacked; older ones are dropped
+const maxToasts = 4
+
+// toastTTL is how long a toast stays up; errors stay until dismissed (Ctrl+X)
+var toastTTL = map[ToastLevel]time.Duration{
+	ToastInfo: 4 * time.Second,
+	ToastWarn: 8 * time.Second,
+}
+
+// DismissToastMsg expires one toast
+type DismissToastMsg struct{ ID int }
+
+// notify queues a toast and returns the command that expires it
+func (m *Model) notify(level ToastLevel, format string, args ...any) tea.Cmd {
+	m.nextToast++
+	t := Toast{ID: m.nextToast, Level: level, Text: fmt.Sprintf(format, args...), At: time.Now()}
+	m.Toasts = append(m.Toasts, t)
+	if len(m.Toasts) > maxToasts {
+		m.Toasts = m.Toasts[len(m.Toasts)-maxToasts:]
+	}
+
+	ttl, ok := toastTTL[level]
+	if !ok {
+		return nil
+	}
+	return tea.Tick(ttl, func(_ time.Time) tea.Msg {
+		return DismissToastMsg{ID: t.ID}
+	})
+}
+
+// dismissToast removes a toast by ID, all of them for ID 0
+func (m *Model) dismissToast(id int) {
+	if id == 0 {
+		m.Toasts = nil
+		return
+	}
+	for i, t := range m.Toasts {
+		if t.ID == id {
+			m.Toasts = append(m.Toasts[:i:i], m.Toasts[i+1:]...)
+			return
+		}
+	}
+}
+
+// toastsView renders the toast stack, oldest first, "" when empty
+func (m Model) toastsView() string {
+	if len(m.Toasts) == 0 {
+		return ""
+	}
+	worst := ToastInfo
+	lines := make([]string, len(m.Toasts))
+	for i, t := range m.Toasts {
+		icon, style := "•", styles.Text
+		switch t.Level {
+		case ToastWarn:
+			icon, style = "⚠", styles.Warn
+		case ToastError:
+			icon, style = "✖", styles.Error
+		}
+		worst = max(worst, t.Level)
+		lines[i] = styles.Subtle.Render(t.At.Format("15:04:05")) + " " + style.Render(icon+" "+t.Text)
+	}
+
+	border := styles.ColorHighlight
+	switch worst {
+	case ToastWarn:
+		border = styles.ColorWarning
+	case ToastError:
+		border = styles.ColorError
+	}
+	return styles.Box.BorderForeground(border).Render(strings.Join(lines, "\n"))
+}
+
+// clipLines keeps the first n lines of s
+func clipLines(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	if n < 1 || len(lines) <= n {
+		return s
+	}
+	return strings.Join(lines[:n], "\n")
+}
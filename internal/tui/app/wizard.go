@@ -0,0 +1,110 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"steadyq/internal/dummy"
+	"steadyq/internal/plan"
+	"steadyq/internal/runner"
+	"steadyq/internal/tui/styles"
+	"steadyq/internal/tui/views"
+)
+
+// Demo run offered on first start: short enough to finish while reading the tour
+const (
+	demoEndpoint = "/spike"
+	demoRPS      = 20
+	demoDuration = 10
+)
+
+// tourTips walk through the dashboard while the demo run is going
+var tourTips = []string{
+	fmt.Sprintf("This is the live dashboard of the demo: %d RPS for %ds against %s on a built-in server.", demoRPS, demoDuration, demoEndpoint),
+	"Throughput shows requests, RPS and Inflight. Open loop keeps sending on schedule even when responses slow down.",
+	demoEndpoint + " answers most requests in 20ms but 5% take 2s: watch P50 stay low while P99 jumps.",
+	"Status codes and errors are broken down below; press e to drill into error signatures.",
+	"Ctrl+→ opens the latency heatmap, Ctrl+P exports the results, F1 lists every key and explains each metric.",
+	"Ctrl+← takes you back to the Runner: point SteadyQ at your own service and press Ctrl+R.",
+}
+
+// welcomeMarker records that the first-run offer was answered
+func welcomeMarker() string {
+	return filepath.Join(filepath.Dir(plan.Dir()), "welcomed")
+}
+
+// FirstRun reports whether the welcome offer has never been answered on this machine
+func FirstRun() bool {
+	_, err := os.Stat(welcomeMarker())
+	return os.IsNotExist(err)
+}
+
+// markWelcomed stops the offer from showing again; failures only mean it will
+func markWelcomed() {
+	path := welcomeMarker()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+		os.WriteFile(path, nil, 0644)
+	}
+}
+
+// updateWelcome handles the answer to the first-run offer
+func (m Model) updateWelcome(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.Welcome = false
+	markWelcomed()
+	if msg.String() != "y" && msg.String() != "Y" && msg.String() != "enter" {
+		return m, m.notify(ToastInfo, "Press F1 any time for keys and a metrics glossary.")
+	}
+
+	base, err := dummy.Listen("127.0.0.1:0")
+	if err != nil {
+		return m, m.notify(ToastError, "Demo server failed to start: %v", err)
+	}
+	cfg := runner.Config{
+		URL:       base + demoEndpoint,
+		Method:    "GET",
+		Mode:      "rps",
+		TargetRPS: demoRPS,
+		SteadyDur: demoDuration,
+		Name:      "demo",
+	}
+	m.RunnerView = views.NewRunnerView(cfg)
+	m.RunnerView.Source = "demo"
+	m.RunnerView, _ = m.RunnerView.Update(tea.WindowSizeMsg{Width: m.Width, Height: m.Height - 6})
+	m.Tour = 1
+	return m, tea.Batch(m.RunnerView.Init(), m.launch(cfg))
+}
+
+// nextTip advances the dashboard tour, ending it after the last tip
+func (m *Model) nextTip() {
+	m.Tour++
+	if m.Tour > len(tourTips) {
+		m.Tour = 0
+	}
+}
+
+// welcomeView is the first-run offer
+func (m Model) welcomeView() string {
+	body := lipgloss.JoinVertical(lipgloss.Left,
+		styles.Active.Render("👋 Welcome to SteadyQ"),
+		"",
+		styles.Text.Render("Start a built-in demo server and run a 10-second sample test"),
+		styles.Text.Render("against it? A short tour of the live dashboard follows."),
+		"",
+		styles.Subtle.Render("[Y/Enter] Start the demo  [any other key] Skip"),
+	)
+	return styles.Box.BorderForeground(styles.ColorHighlight).Padding(1, 2).Render(body)
+}
+
+// tourView is the current tour tip, "" when the tour is over
+func (m Model) tourView() string {
+	if m.Tour == 0 {
+		return ""
+	}
+	tip := styles.Active.Render(fmt.Sprintf("Tour %d/%d ", m.Tour, len(tourTips))) + styles.Text.Render(tourTips[m.Tour-1])
+	hint := styles.Subtle.Render("[Enter] next tip  [Ctrl+X] end tour")
+	return styles.Box.BorderForeground(styles.ColorPrimary).Width(m.Width - 4).Render(tip + "\n" + hint)
+}
@@ -4,29 +4,39 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-// --- Color Palette (High Contrast Adaptive) ---
+// --- Color Palette ---
+// Set from the active Theme, see theme.go
 var (
-	// Primary: Deep Purple in light mode, Neon Purple in dark mode
-	ColorPrimary = lipgloss.AdaptiveColor{Light: "#5A189A", Dark: "#9D4EDD"}
-
-	// Secondary: Dark Cyan in light mode, Bright Cyan in dark mode
-	ColorSecondary = lipgloss.AdaptiveColor{Light: "#023E8A", Dark: "#48CAE4"}
-
-	// Accent/Error: Dark Red in light mode, Bright Coral in dark mode
-	ColorAccent    = lipgloss.AdaptiveColor{Light: "#C9184A", Dark: "#FF4D6D"}
-	ColorError     = lipgloss.AdaptiveColor{Light: "#C9184A", Dark: "#FF4D6D"}
-	ColorWarning   = lipgloss.AdaptiveColor{Light: "#B36700", Dark: "#FFAF00"} // Dark Gold / Gold
-	ColorText      = lipgloss.AdaptiveColor{Light: "#111111", Dark: "#FFFFFF"} // Almost Black / White
-	ColorSubtle    = lipgloss.AdaptiveColor{Light: "#555555", Dark: "#888888"} // Dark Gray / Light Gray
-	ColorBorder    = lipgloss.AdaptiveColor{Light: "#888888", Dark: "#444444"} // Mid Gray / Dark Gray
-	ColorBg        = lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#121212"} // White / Almost Black
-	ColorHighlight = lipgloss.AdaptiveColor{Light: "#EEEEEE", Dark: "#333333"} // Very Light Gray / Dark Gray
-	ColorBanner    = lipgloss.AdaptiveColor{Light: "#444444", Dark: "#AAAAAA"} // Variation of Subtle
+	ColorPrimary   lipgloss.TerminalColor
+	ColorSecondary lipgloss.TerminalColor
+	ColorAccent    lipgloss.TerminalColor
+	ColorError     lipgloss.TerminalColor
+	ColorWarning   lipgloss.TerminalColor
+	ColorText      lipgloss.TerminalColor
+	ColorSubtle    lipgloss.TerminalColor
+	ColorBorder    lipgloss.TerminalColor
+	ColorBg        lipgloss.TerminalColor
+	ColorHighlight lipgloss.TerminalColor
+	ColorBanner    lipgloss.TerminalColor
 )
 
 // --- Base Styles ---
 
 var (
+	Panel, Title                   lipgloss.Style
+	Text, Subtle, Value, Active    lipgloss.Style
+	Error, Warn, Success           lipgloss.Style
+	KeyKey, KeyDesc                lipgloss.Style
+	InputActive, InputNormal, Box  lipgloss.Style
+	TabBase, TabActive, FooterBase lipgloss.Style
+)
+
+func init() {
+	Apply(DetectTheme())
+}
+
+// build recreates every style from the current palette
+func build() {
 	// Main Container Panel
 	Panel = lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -42,19 +52,19 @@ var (
 		BorderForeground(ColorSubtle)
 
 	// Text Styles
-	Text   = lipgloss.NewStyle().Foreground(ColorText)
+	Text = lipgloss.NewStyle().Foreground(ColorText)
 	Subtle = lipgloss.NewStyle().Foreground(ColorSubtle)
 
 	// Value metrics
-	Value  = lipgloss.NewStyle().Foreground(ColorSecondary).Bold(true)
+	Value = lipgloss.NewStyle().Foreground(ColorSecondary).Bold(true)
 	Active = lipgloss.NewStyle().Foreground(ColorPrimary).Bold(true)
 	// Alerts
-	Error   = lipgloss.NewStyle().Foreground(ColorError)
-	Warn    = lipgloss.NewStyle().Foreground(ColorWarning)
+	Error = lipgloss.NewStyle().Foreground(ColorError)
+	Warn = lipgloss.NewStyle().Foreground(ColorWarning)
 	Success = lipgloss.NewStyle().Foreground(ColorSecondary).Bold(true)
 
 	// Keys
-	KeyKey  = lipgloss.NewStyle().Foreground(ColorText).Bold(true)
+	KeyKey = lipgloss.NewStyle().Foreground(ColorText).Bold(true)
 	KeyDesc = lipgloss.NewStyle().Foreground(ColorSubtle)
 
 	// Inputs
@@ -74,16 +84,16 @@ var (
 		Padding(0, 2)
 
 	TabActive = lipgloss.NewStyle().
-			Foreground(ColorPrimary).
-			Bold(true).
-			Border(lipgloss.NormalBorder(), false, false, true, false).
-			BorderForeground(ColorPrimary).
-			Padding(0, 2)
+		Foreground(ColorPrimary).
+		Bold(true).
+		Border(lipgloss.NormalBorder(), false, false, true, false).
+		BorderForeground(ColorPrimary).
+		Padding(0, 2)
 
 	FooterBase = lipgloss.NewStyle().
-			Height(1).
-			Padding(0, 1)
-)
+		Height(1).
+		Padding(0, 1)
+}
 
 func RenderKey(key, desc string) string {
 	return lipgloss.JoinHorizontal(lipgloss.Center,
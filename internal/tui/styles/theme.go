@@ -0,0 +1,153 @@
+package styles
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Theme is a named color palette
+type Theme struct {
+	Name string
+
+	Primary, Secondary, Accent, Error, Warning  lipgloss.TerminalColor
+	Text, Subtle, Border, Bg, Highlight, Banner lipgloss.TerminalColor
+}
+
+// ThemeNames is the cycle order used by the runtime toggle
+var ThemeNames = []string{"auto", "dark", "light", "mono"}
+
+var themes = map[string]Theme{
+	// auto follows the terminal background
+	"auto": {
+		Name:      "auto",
+		Primary:   lipgloss.AdaptiveColor{Light: "#5A189A", Dark: "#9D4EDD"},
+		Secondary: lipgloss.AdaptiveColor{Light: "#023E8A", Dark: "#48CAE4"},
+		Accent:    lipgloss.AdaptiveColor{Light: "#C9184A", Dark: "#FF4D6D"},
+		Error:     lipgloss.AdaptiveColor{Light: "#C9184A", Dark: "#FF4D6D"},
+		Warning:   lipgloss.AdaptiveColor{Light: "#B36700", Dark: "#FFAF00"},
+		Text:      lipgloss.AdaptiveColor{Light: "#111111", Dark: "#FFFFFF"},
+		Subtle:    lipgloss.AdaptiveColor{Light: "#555555", Dark: "#888888"},
+		Border:    lipgloss.AdaptiveColor{Light: "#888888", Dark: "#444444"},
+		Bg:        lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#121212"},
+		Highlight: lipgloss.AdaptiveColor{Light: "#EEEEEE", Dark: "#333333"},
+		Banner:    lipgloss.AdaptiveColor{Light: "#444444", Dark: "#AAAAAA"},
+	},
+	"dark": {
+		Name:      "dark",
+		Primary:   lipgloss.Color("#9D4EDD"),
+		Secondary: lipgloss.Color("#48CAE4"),
+		Accent:    lipgloss.Color("#FF4D6D"),
+		Error:     lipgloss.Color("#FF4D6D"),
+		Warning:   lipgloss.Color("#FFAF00"),
+		Text:      lipgloss.Color("#FFFFFF"),
+		Subtle:    lipgloss.Color("#888888"),
+		Border:    lipgloss.Color("#444444"),
+		Bg:        lipgloss.Color("#121212"),
+		Highlight: lipgloss.Color("#333333"),
+		Banner:    lipgloss.Color("#AAAAAA"),
+	},
+	"light": {
+		Name:      "light",
+		Primary:   lipgloss.Color("#5A189A"),
+		Secondary: lipgloss.Color("#023E8A"),
+		Accent:    lipgloss.Color("#C9184A"),
+		Error:     lipgloss.Color("#C9184A"),
+		Warning:   lipgloss.Color("#B36700"),
+		Text:      lipgloss.Color("#111111"),
+		Subtle:    lipgloss.Color("#555555"),
+		Border:    lipgloss.Color("#888888"),
+		Bg:        lipgloss.Color("#FFFFFF"),
+		Highlight: lipgloss.Color("#EEEEEE"),
+		Banner:    lipgloss.Color("#444444"),
+	},
+	// mono relies on bold/borders only, for NO_COLOR and screenshots
+	"mono": {
+		Name:      "mono",
+		Primary:   lipgloss.NoColor{},
+		Secondary: lipgloss.NoColor{},
+		Accent:    lipgloss.NoColor{},
+		Error:     lipgloss.NoColor{},
+		Warning:   lipgloss.NoColor{},
+		Text:      lipgloss.NoColor{},
+		Subtle:    lipgloss.NoColor{},
+		Border:    lipgloss.NoColor{},
+		Bg:        lipgloss.NoColor{},
+		Highlight: lipgloss.NoColor{},
+		Banner:    lipgloss.NoColor{},
+	},
+}
+
+// basic is used on 16-color terminals, where downsampled hex colors collapse together
+var basic = Theme{
+	Name:      "basic",
+	Primary:   lipgloss.Color("5"),
+	Secondary: lipgloss.Color("6"),
+	Accent:    lipgloss.Color("1"),
+	Error:     lipgloss.Color("1"),
+	Warning:   lipgloss.Color("3"),
+	Text:      lipgloss.NoColor{},
+	Subtle:    lipgloss.Color("8"),
+	Border:    lipgloss.Color("8"),
+	Bg:        lipgloss.NoColor{},
+	Highlight: lipgloss.Color("8"),
+	Banner:    lipgloss.NoColor{},
+}
+
+// Current is the name of the applied theme
+var Current string
+
+// DetectTheme picks the startup theme: mono under NO_COLOR, else STEADYQ_THEME, else auto
+func DetectTheme() string {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return "mono"
+	}
+	if t := strings.ToLower(os.Getenv("STEADYQ_THEME")); t != "" {
+		return t
+	}
+	return "auto"
+}
+
+// Apply switches the palette and rebuilds all styles. Unknown names fall back to auto.
+func Apply(name string) {
+	t, ok := themes[name]
+	if !ok {
+		t = themes["auto"]
+	}
+	Current = t.Name
+
+	if t.Name == "mono" {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	} else {
+		lipgloss.SetColorProfile(termenv.EnvColorProfile())
+		if lipgloss.ColorProfile() == termenv.ANSI {
+			t = basic
+		}
+	}
+
+	ColorPrimary = t.Primary
+	ColorSecondary = t.Secondary
+	ColorAccent = t.Accent
+	ColorError = t.Error
+	ColorWarning = t.Warning
+	ColorText = t.Text
+	ColorSubtle = t.Subtle
+	ColorBorder = t.Border
+	ColorBg = t.Bg
+	ColorHighlight = t.Highlight
+	ColorBanner = t.Banner
+
+	build()
+}
+
+// NextTheme returns the theme after the current one in ThemeNames
+func NextTheme() string {
+	for i, n := range ThemeNames {
+		if n == Current {
+			return ThemeNames[(i+1)%len(ThemeNames)]
+		}
+	}
+	return ThemeNames[0]
+}
@@ -2,6 +2,7 @@ package views
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 	"time"
@@ -11,7 +12,9 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"steadyq/internal/report"
 	"steadyq/internal/runner"
+	"steadyq/internal/tui/components"
 	"steadyq/internal/tui/styles"
 )
 
@@ -25,10 +28,55 @@ type DashboardView struct {
 	Duration   time.Duration
 	LastUpdate time.Time
 
+	// Set when replaying a finished run: the clock stays frozen at EndTime
+	EndTime time.Time
+
+	// Error drill-down overlay
+	ErrorsOpen bool
+	ErrorSel   int
+
+	// Panels hidden by the user, the rest expand to fill the width
+	Collapsed map[Panel]bool
+
 	Width  int
 	Height int
 }
 
+// Panel identifies a collapsible dashboard section
+type Panel string
+
+const (
+	PanelProgress Panel = "progress"
+	PanelVolume   Panel = "volume"
+	PanelConc     Panel = "concurrency"
+	PanelNet      Panel = "network"
+	PanelLatency  Panel = "latency"
+	PanelOther    Panel = "other"
+	PanelCodes    Panel = "codes"
+	PanelErrors   Panel = "errors"
+	PanelSamples  Panel = "samples"
+	PanelTarget   Panel = "target"
+	PanelGen      Panel = "generator"
+)
+
+// panelKeys maps toggle keys to panels, in display order
+var panelKeys = []struct {
+	Key   string
+	Panel Panel
+}{
+	{"p", PanelProgress},
+	{"t", PanelVolume},
+	{"n", PanelConc},
+	{"w", PanelNet},
+	{"l", PanelLatency},
+	{"o", PanelOther},
+	{"c", PanelCodes},
+	{"x", PanelErrors},
+	{"b", PanelSamples},
+	{"m", PanelTarget},
+	{"g", PanelGen},
+}
+
 func NewDashboardView(cfg runner.Config, width, height int) DashboardView {
 	totalDur := time.Duration(cfg.RampUp+cfg.SteadyDur+cfg.RampDown) * time.Second
 
@@ -50,6 +98,7 @@ func NewDashboardView(cfg runner.Config, width, height int) DashboardView {
 		LastUpdate: time.Now(),
 		Width:      width,
 		Height:     height,
+		Collapsed:  make(map[Panel]bool),
 	}
 }
 
@@ -65,11 +114,7 @@ func (m DashboardView) Update(msg tea.Msg) (DashboardView, tea.Cmd) {
 	case runner.StatsSnapshot:
 		m.LastUpdate = time.Now()
 		m.Stats = msg
-
-		var elapsed time.Duration
-		if !m.StartTime.IsZero() {
-			elapsed = time.Since(m.StartTime)
-		}
+		elapsed := m.elapsed()
 
 		pct := 0.0
 		if m.Duration > 0 {
@@ -94,6 +139,41 @@ func (m DashboardView) Update(msg tea.Msg) (DashboardView, tea.Cmd) {
 			m.Progress = newModel
 		}
 		cmds = append(cmds, cmd)
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "e":
+			m.ErrorsOpen = !m.ErrorsOpen
+			m.ErrorSel = 0
+			m.Viewport.GotoTop()
+			return m, nil
+		case "esc":
+			m.ErrorsOpen = false
+			return m, nil
+		case "a":
+			m.Collapsed = make(map[Panel]bool)
+			return m, nil
+		case "up", "k":
+			if m.ErrorsOpen {
+				m.ErrorSel = max(0, m.ErrorSel-1)
+				return m, nil
+			}
+		case "down", "j":
+			if m.ErrorsOpen {
+				m.ErrorSel = min(len(m.Stats.Failures)-1, m.ErrorSel+1)
+				return m, nil
+			}
+		}
+		for _, pk := range panelKeys {
+			if msg.String() == pk.Key && !m.ErrorsOpen {
+				m.togglePanel(pk.Panel)
+				return m, nil
+			}
+		}
+
+	case tea.MouseMsg:
+		// Wheel scrolling needs the content on the stored viewport, not just the View copy
+		m.Viewport.SetContent(m.content())
 	}
 
 	m.Viewport, cmd = m.Viewport.Update(msg)
@@ -103,13 +183,20 @@ func (m DashboardView) Update(msg tea.Msg) (DashboardView, tea.Cmd) {
 }
 
 func (m DashboardView) View() string {
+	m.Viewport.SetContent(m.content())
+	return m.Viewport.View()
+}
+
+// content renders all panels; the viewport clips it to the window
+func (m DashboardView) content() string {
+	if m.ErrorsOpen {
+		return m.errorsContent()
+	}
+
 	s := strings.Builder{}
 
 	// --- Header ---
-	var elapsed time.Duration
-	if !m.StartTime.IsZero() {
-		elapsed = time.Since(m.StartTime)
-	}
+	elapsed := m.elapsed()
 	remaining := m.Duration - elapsed
 	if remaining < 0 {
 		remaining = 0
@@ -142,8 +229,15 @@ func (m DashboardView) View() string {
 		}
 	}
 
+	if !m.EndTime.IsZero() {
+		status = "REPLAY"
+		phase = "Recorded " + m.StartTime.Format("2006-01-02 15:04")
+	}
+
 	statusColor := styles.Active
-	if status == "DRAINING" {
+	if status == "REPLAY" {
+		statusColor = styles.Subtle
+	} else if status == "DRAINING" {
 		statusColor = styles.Warn
 	} else if status == "FINISHED" {
 		statusColor = styles.Subtle
@@ -158,9 +252,21 @@ func (m DashboardView) View() string {
 	s.WriteString(header)
 	s.WriteString("\n\n")
 
+	// --- Local resource exhaustion (not the server's fault) ---
+	if w := m.exhaustionWarning(); w != "" {
+		s.WriteString(w)
+		s.WriteString("\n\n")
+	}
+
 	// --- Progress ---
-	s.WriteString(m.Progress.View())
-	s.WriteString("\n\n")
+	if !m.Collapsed[PanelProgress] {
+		if m.EndTime.IsZero() {
+			s.WriteString(m.Progress.View())
+		} else {
+			s.WriteString(m.Progress.ViewAs(1))
+		}
+		s.WriteString("\n\n")
+	}
 
 	// --- Metrics Grid ---
 	// Row 1: Volume
@@ -169,7 +275,7 @@ func (m DashboardView) View() string {
 	if elapsed.Seconds() > 0 {
 		rps = float64(m.Stats.Requests) / elapsed.Seconds()
 	}
-	rpsVal := styles.Value.Render(fmt.Sprintf("%.1f", rps))
+	rpsVal := m.thresholdStyle("rps", styles.Value, elapsed).Render(fmt.Sprintf("%.1f", rps))
 	inflightVal := styles.Active.Render(fmt.Sprintf("%d", m.Stats.Inflight))
 
 	// Target display
@@ -179,50 +285,152 @@ func (m DashboardView) View() string {
 	}
 	targetVal := styles.Subtle.Render(targetStr)
 
-	row1 := lipgloss.JoinHorizontal(lipgloss.Top,
-		MakeCard("Requests", reqsVal),
-		MakeCard("Avg RPS", rpsVal),
-		MakeCard("Inflight", inflightVal),
-		MakeCard("Target", targetVal),
-	)
-	s.WriteString(row1)
-	s.WriteString("\n")
+	if !m.Collapsed[PanelVolume] {
+		row1 := m.cardRow(
+			card{"Requests", reqsVal},
+			card{"Avg RPS", rpsVal},
+			card{"Inflight", inflightVal},
+			card{"Target", targetVal},
+		)
+		s.WriteString(row1)
+		s.WriteString("\n")
+	}
 
-	// Row 2: Latency Percentiles
-	p50Val := styles.Text.Render(fmt.Sprintf("%.1f ms", m.Stats.P50ServiceMs))
-	p90Val := styles.Text.Render(fmt.Sprintf("%.1f ms", m.Stats.P90ServiceMs))
-	p95Val := styles.Warn.Render(fmt.Sprintf("%.1f ms", m.Stats.P95ServiceMs))
-	p99Val := styles.Error.Render(fmt.Sprintf("%.1f ms", m.Stats.P99ServiceMs))
-
-	row2 := lipgloss.JoinHorizontal(lipgloss.Top,
-		MakeCard("P50 Latency", p50Val),
-		MakeCard("P90 Latency", p90Val),
-		MakeCard("P95 Latency", p95Val),
-		MakeCard("P99 Latency", p99Val),
-	)
-	s.WriteString(row2)
-	s.WriteString("\n")
+	if m.Stats.Requests > 0 && !m.Collapsed[PanelConc] {
+		s.WriteString(m.concurrencyContent(rps))
+	}
+
+	if m.Stats.SentBytes+m.Stats.RecvBytes > 0 && !m.Collapsed[PanelNet] {
+		s.WriteString(m.networkContent())
+	}
+
+	// Users mode: whole user loops, the business-level throughput
+	if m.Config.Mode == "users" && !m.Collapsed[PanelVolume] {
+		itersPerSec := 0.0
+		if elapsed.Seconds() > 0 {
+			itersPerSec = float64(m.Stats.Iterations) / elapsed.Seconds()
+		}
+		cards := []card{
+			{"Iterations", styles.Value.Render(fmt.Sprintf("%d", m.Stats.Iterations))},
+			{"Iterations/s", styles.Value.Render(fmt.Sprintf("%.1f", itersPerSec))},
+			{"P50 Iteration", styles.Text.Render(fmt.Sprintf("%.1f ms", m.Stats.P50IterationMs))},
+			{"P99 Iteration", styles.Error.Render(fmt.Sprintf("%.1f ms", m.Stats.P99IterationMs))},
+		}
+		if m.Config.GetFanOut() > 1 {
+			cards = append(cards, card{fmt.Sprintf("P99 Group (x%d)", m.Config.GetFanOut()), styles.Warn.Render(fmt.Sprintf("%.1f ms", m.Stats.P99GroupMs))})
+		}
+		stuck := styles.Value
+		if m.Stats.StuckUsers > 0 {
+			stuck = styles.Warn
+		}
+		cards = append(cards, card{"Stuck Users", stuck.Render(fmt.Sprintf("%d (peak %d)", m.Stats.StuckUsers, m.Stats.StuckPeak))})
+		if m.Config.IterationTimeout > 0 {
+			cards = append(cards, card{"Iter. Deadline", styles.Warn.Render(fmt.Sprintf("%d cut", m.Stats.IterationDeadlines))})
+		}
+		rowIter := m.cardRow(cards...)
+		s.WriteString(rowIter)
+		s.WriteString("\n")
+	}
+
+	// Row 2: Latency Percentiles (Config.GetPercentiles), the highest in red
+	if !m.Collapsed[PanelLatency] {
+		ps := m.Config.GetPercentiles()
+		var cards2 []card
+		for i, p := range ps {
+			base := styles.Text
+			switch {
+			case i == len(ps)-1:
+				base = styles.Error
+			case i == len(ps)-2 && len(ps) > 2:
+				base = styles.Warn
+			}
+			v := 0.0
+			if i < len(m.Stats.ServicePercentiles) {
+				v = m.Stats.ServicePercentiles[i]
+			}
+			name := fmt.Sprintf("P%g", p)
+			val := m.thresholdStyle(strings.ToLower(name), base, elapsed).Render(fmt.Sprintf("%.1f ms", v))
+			cards2 = append(cards2, card{name + " Latency", val})
+		}
+		row2 := m.cardRow(cards2...)
+		s.WriteString(row2)
+		s.WriteString("\n")
+		if warnings := report.SampleWarnings(m.Stats.Requests); len(warnings) > 0 {
+			s.WriteString(styles.Warn.Render("⚠ " + warnings[0]))
+			s.WriteString("\n")
+		}
+	}
 
 	// Row 3: Others
-	meanVal := styles.Text.Render(fmt.Sprintf("%.1f ms", m.Stats.MeanServiceMs))
-	maxVal := styles.Text.Render(fmt.Sprintf("%d ms", m.Stats.MaxServiceMs))
+	meanVal := m.thresholdStyle("mean", styles.Text, elapsed).Render(fmt.Sprintf("%.1f ms", m.Stats.MeanServiceMs))
+	stddevVal := m.thresholdStyle("stddev", styles.Text, elapsed).Render(fmt.Sprintf("%.1f ms", m.Stats.StdDevServiceMs))
+	maxVal := m.thresholdStyle("max", styles.Text, elapsed).Render(fmt.Sprintf("%d ms", m.Stats.MaxServiceMs))
 
 	errColor := styles.Text
 	if m.Stats.Fail > 0 {
 		errColor = styles.Error
 	}
-	failVal := errColor.Render(fmt.Sprintf("%d", m.Stats.Fail))
+	failVal := m.thresholdStyle("error_rate", errColor, elapsed).Render(fmt.Sprintf("%d", m.Stats.Fail))
 
-	row3 := lipgloss.JoinHorizontal(lipgloss.Top,
-		MakeCard("Mean Latency", meanVal),
-		MakeCard("Max Latency", maxVal),
-		MakeCard("Errors", failVal),
-	)
-	s.WriteString(row3)
-	s.WriteString("\n\n")
+	cards3 := []card{
+		{"Mean Latency", meanVal},
+		{"Std Dev", stddevVal},
+		{"Max Latency", maxVal},
+		{"Errors [e]", failVal},
+	}
+	if m.Config.BreakerErrorRate > 0 {
+		breakerColor := styles.Value
+		switch m.Stats.BreakerState {
+		case runner.BreakerOpen:
+			breakerColor = styles.Error
+		case runner.BreakerHalfOpen:
+			breakerColor = styles.Warn
+		}
+		breakerVal := breakerColor.Render(fmt.Sprintf("%s (%d shed)", strings.ToUpper(m.Stats.BreakerState), m.Stats.ShortCircuited))
+		cards3 = append(cards3, card{"Breaker", breakerVal})
+	}
+	if m.Stats.SchedulerSkipped > 0 {
+		intended := m.Stats.Requests + m.Stats.SchedulerSkipped
+		notSent := fmt.Sprintf("%d (%.1f%%)", m.Stats.SchedulerSkipped, float64(m.Stats.SchedulerSkipped)/float64(intended)*100)
+		cards3 = append(cards3, card{"Not Sent (Gen)", styles.Error.Render(notSent)})
+	}
+	if m.Stats.AbortReason != "" {
+		cards3 = append(cards3, card{"Aborted", styles.Error.Render(m.Stats.AbortReason)})
+	}
+	if m.Stats.ForceCancelled > 0 {
+		cards3 = append(cards3, card{"Force-Cancelled", styles.Warn.Render(fmt.Sprintf("%d", m.Stats.ForceCancelled))})
+	}
+	if m.Stats.TLSVerifyFailed > 0 {
+		cards3 = append(cards3, card{"TLS Verify Fail", styles.Error.Render(fmt.Sprintf("%d", m.Stats.TLSVerifyFailed))})
+	}
+	if m.Config.HonorRetryAfter {
+		backoffStr := fmt.Sprintf("%d shed", m.Stats.RetryAfterShed)
+		if m.Config.Mode == "users" {
+			backoffStr = fmt.Sprintf("%.1fs paused", m.Stats.RetryAfterPauseSec)
+		}
+		cards3 = append(cards3, card{"Retry-After", styles.Warn.Render(backoffStr)})
+	}
+	if !m.Collapsed[PanelOther] {
+		row3 := m.cardRow(cards3...)
+		s.WriteString(row3)
+		s.WriteString("\n")
+	}
+	if line := m.thresholdLine(elapsed); line != "" {
+		s.WriteString(line)
+		s.WriteString("\n")
+	}
+	if len(m.Stats.Generator) > 0 && !m.Collapsed[PanelGen] {
+		s.WriteString(m.generatorContent())
+		s.WriteString("\n")
+	}
+	if m.Config.Monitor != "" && !m.Collapsed[PanelTarget] {
+		s.WriteString(m.targetContent())
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
 
 	// --- Response Codes ---
-	if len(m.Stats.StatusCodes) > 0 {
+	if len(m.Stats.StatusCodes) > 0 && !m.Collapsed[PanelCodes] {
 		s.WriteString(styles.Subtle.Render("Response Breakdown"))
 		s.WriteString("\n")
 
@@ -233,6 +441,9 @@ func (m DashboardView) View() string {
 		sort.Ints(codes)
 
 		barWidth := 30
+		if m.anyCollapsed() {
+			barWidth = max(barWidth, m.Width-40)
+		}
 		maxCount := 0
 		for _, c := range m.Stats.StatusCodes {
 			if c > maxCount {
@@ -268,7 +479,7 @@ func (m DashboardView) View() string {
 	}
 
 	// --- Error Detail Breakdown ---
-	if len(m.Stats.ErrorCounts) > 0 {
+	if len(m.Stats.ErrorCounts) > 0 && !m.Collapsed[PanelErrors] {
 		s.WriteString("\n")
 		s.WriteString(styles.Subtle.Render("Error Details"))
 		s.WriteString("\n")
@@ -292,7 +503,7 @@ func (m DashboardView) View() string {
 	}
 
 	// --- Response Samples ---
-	if len(m.Stats.ResponseSamples) > 0 {
+	if len(m.Stats.ResponseSamples) > 0 && !m.Collapsed[PanelSamples] {
 		s.WriteString("\n")
 		s.WriteString(styles.Subtle.Render("Sample Response Body (>=400)"))
 		s.WriteString("\n")
@@ -315,14 +526,338 @@ func (m DashboardView) View() string {
 		}
 	}
 
-	content := styles.Panel.Width(m.Width - 6).Render(s.String())
-	m.Viewport.SetContent(content)
+	// --- Panel toggles ---
+	s.WriteString("\n")
+	s.WriteString(m.panelHint())
+
+	return styles.Panel.Width(m.Width - 6).Render(s.String())
+}
 
-	return m.Viewport.View()
+// elapsed is the run time so far, frozen at EndTime for replays
+func (m DashboardView) elapsed() time.Duration {
+	if m.StartTime.IsZero() {
+		return 0
+	}
+	if !m.EndTime.IsZero() {
+		return m.EndTime.Sub(m.StartTime)
+	}
+	return time.Since(m.StartTime)
 }
 
 func MakeCard(title, value string) string {
-	return styles.Box.Width(18).Align(lipgloss.Center).Render(
+	return makeCardWidth(title, value, 18)
+}
+
+func makeCardWidth(title, value string, width int) string {
+	return styles.Box.Width(width).Align(lipgloss.Center).Render(
 		fmt.Sprintf("%s\n%s", styles.Subtle.Render(title), value),
 	)
 }
+
+// thresholdStyle colors a metric's value by its Config.Thresholds: red while
+// one fails, green while all pass, base when none targets it or no request
+// has completed yet
+func (m DashboardView) thresholdStyle(metric string, base lipgloss.Style, elapsed time.Duration) lipgloss.Style {
+	style := base
+	for _, t := range m.Config.GetThresholds() {
+		if t.Metric != metric {
+			continue
+		}
+		v, ok := t.Observe(m.Stats, elapsed)
+		if !ok {
+			return base
+		}
+		if !t.Pass(v) {
+			return styles.Error
+		}
+		style = styles.Success
+	}
+	return style
+}
+
+// thresholdLine lists every threshold with its live verdict, "" when none are set
+func (m DashboardView) thresholdLine(elapsed time.Duration) string {
+	ths := m.Config.GetThresholds()
+	if len(ths) == 0 {
+		return ""
+	}
+	parts := []string{styles.Subtle.Render("Thresholds")}
+	for _, t := range ths {
+		v, ok := t.Observe(m.Stats, elapsed)
+		switch {
+		case !ok:
+			parts = append(parts, styles.Subtle.Render("· "+t.String()))
+		case t.Pass(v):
+			parts = append(parts, styles.Success.Render(fmt.Sprintf("✓ %s (%.1f)", t.String(), v)))
+		default:
+			parts = append(parts, styles.Error.Render(fmt.Sprintf("✗ %s (%.1f)", t.String(), v)))
+		}
+	}
+	return strings.Join(parts, "  ")
+}
+
+type card struct {
+	title string
+	value string
+}
+
+// cardRow renders a row of cards, stretched to the full width once panels are hidden
+func (m DashboardView) cardRow(cards ...card) string {
+	width := 18
+	if m.anyCollapsed() && len(cards) > 0 {
+		// Box adds border (2) and margin (2); the outer panels take ~12 columns
+		width = max(width, (m.Width-12)/len(cards)-4)
+	}
+	rendered := make([]string, len(cards))
+	for i, c := range cards {
+		rendered[i] = makeCardWidth(c.title, c.value, width)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
+}
+
+func (m *DashboardView) togglePanel(p Panel) {
+	if m.Collapsed == nil {
+		m.Collapsed = make(map[Panel]bool)
+	}
+	m.Collapsed[p] = !m.Collapsed[p]
+}
+
+func (m DashboardView) anyCollapsed() bool {
+	for _, v := range m.Collapsed {
+		if v {
+			return true
+		}
+	}
+	return false
+}
+
+// targetContent shows the target's CPU/memory next to client latency
+func (m DashboardView) targetContent() string {
+	samples := m.Stats.Target
+	if len(samples) == 0 {
+		return m.cardRow(card{"Target CPU", styles.Subtle.Render("waiting...")}, card{"Target Mem", styles.Subtle.Render("waiting...")})
+	}
+	last := samples[len(samples)-1]
+	if last.Err != "" {
+		msg := last.Err
+		if len(msg) > 60 {
+			msg = msg[:57] + "..."
+		}
+		return styles.Subtle.Render("Target monitor: ") + styles.Error.Render(msg) + "\n"
+	}
+
+	usage := func(pct float64) string {
+		style := styles.Value
+		if pct >= 90 {
+			style = styles.Error
+		} else if pct >= 75 {
+			style = styles.Warn
+		}
+		return style.Render(fmt.Sprintf("%.1f%%", pct))
+	}
+	row := m.cardRow(
+		card{"Target CPU", usage(last.CPUPercent)},
+		card{"Target Mem", usage(last.MemPercent)},
+		card{"P99 Latency", styles.Error.Render(fmt.Sprintf("%.1f ms", m.Stats.P99ServiceMs))},
+	)
+
+	width := max(10, min(len(samples), m.Width-24))
+	cpu := components.NewSparkline(width, 1, "", styles.Value)
+	mem := components.NewSparkline(width, 1, "", styles.Subtle)
+	for _, smp := range samples {
+		cpu.Add(uint64(smp.CPUPercent))
+		mem.Add(uint64(smp.MemPercent))
+	}
+	// Fixed 0-100 scale so the graphs compare across time
+	cpu.Max, mem.Max = 100, 100
+	graphs := fmt.Sprintf("%s %s\n%s %s",
+		styles.Subtle.Render("cpu"), strings.TrimPrefix(cpu.View(), "\n"),
+		styles.Subtle.Render("mem"), strings.TrimPrefix(mem.View(), "\n"))
+	return row + "\n" + graphs + "\n"
+}
+
+// concurrencyContent sets the measured in-flight requests against Little's
+// Law: over a stable stretch the average in flight (L) equals the completion
+// rate (λ) times the mean service time (W)
+func (m DashboardView) concurrencyContent(rps float64) string {
+	littleL := rps * m.Stats.MeanServiceMs / 1000
+	row := m.cardRow(
+		card{"Inflight (avg)", styles.Active.Render(fmt.Sprintf("%.1f", m.Stats.InflightAvg))},
+		card{"Little's L = λW", styles.Value.Render(fmt.Sprintf("%.1f", littleL))},
+		card{"λ Completed", styles.Text.Render(fmt.Sprintf("%.1f req/s", rps))},
+		card{"W Mean Service", styles.Text.Render(fmt.Sprintf("%.1f ms", m.Stats.MeanServiceMs))},
+	)
+
+	note := "Open loop: λ is fixed, so slower responses (W) pile up in flight (L)"
+	if m.Config.Mode == "users" {
+		note = fmt.Sprintf("Closed loop: at most %d in flight, so slower responses (W) lower the rate (λ)", m.Config.NumUsers*m.Config.GetFanOut())
+	}
+	history := m.Stats.InflightHistory
+	if len(history) == 0 {
+		return row + "\n" + styles.Subtle.Render(note) + "\n"
+	}
+	line := components.NewSparkline(max(10, min(len(history), m.Width-24)), 1, "", styles.Active)
+	for _, v := range history {
+		line.Add(uint64(math.Round(v)))
+	}
+	return fmt.Sprintf("%s\n%s %s\n%s\n", row,
+		styles.Subtle.Render("inflight"), strings.TrimPrefix(line.View(), "\n"),
+		styles.Subtle.Render(note))
+}
+
+// networkContent shows the bytes on the wire (heads and bodies) with
+// per-second throughput sparklines
+func (m DashboardView) networkContent() string {
+	last := func(rates []uint64) float64 {
+		if len(rates) == 0 {
+			return 0
+		}
+		return float64(rates[len(rates)-1])
+	}
+	row := m.cardRow(
+		card{"Sent", styles.Value.Render(report.FormatBytes(float64(m.Stats.SentBytes)))},
+		card{"Send Rate", styles.Text.Render(report.FormatBytes(last(m.Stats.SentRate)) + "/s")},
+		card{"Received", styles.Value.Render(report.FormatBytes(float64(m.Stats.RecvBytes)))},
+		card{"Recv Rate", styles.Text.Render(report.FormatBytes(last(m.Stats.RecvRate)) + "/s")},
+	)
+	if len(m.Stats.RecvRate) == 0 {
+		return row + "\n"
+	}
+	width := max(10, min(len(m.Stats.RecvRate), m.Width-24))
+	sent := components.NewSparkline(width, 1, "", styles.Value)
+	recv := components.NewSparkline(width, 1, "", styles.Active)
+	for i := range m.Stats.RecvRate {
+		sent.Add(m.Stats.SentRate[i])
+		recv.Add(m.Stats.RecvRate[i])
+	}
+	return fmt.Sprintf("%s\n%s %s\n%s %s\n", row,
+		styles.Subtle.Render("sent"), strings.TrimPrefix(sent.View(), "\n"),
+		styles.Subtle.Render("recv"), strings.TrimPrefix(recv.View(), "\n"))
+}
+
+// exhaustionWarning explains requests lost to local port or descriptor exhaustion
+func (m DashboardView) exhaustionWarning() string {
+	var lines []string
+	if n := m.Stats.PortsExhausted; n > 0 {
+		lines = append(lines,
+			styles.Error.Render(fmt.Sprintf("⚠ %d requests failed locally: ephemeral ports exhausted (EADDRNOTAVAIL)", n)),
+			styles.Subtle.Render("  "+runner.ExhaustionHint(runner.ExhaustedPorts)))
+	}
+	if n := m.Stats.FDsExhausted; n > 0 {
+		lines = append(lines,
+			styles.Error.Render(fmt.Sprintf("⚠ %d requests failed locally: too many open files (EMFILE)", n)),
+			styles.Subtle.Render("  "+runner.ExhaustionHint(runner.ExhaustedFDs)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// generatorContent shows SteadyQ's own health, warning when the client is the bottleneck
+func (m DashboardView) generatorContent() string {
+	last := m.Stats.Generator[len(m.Stats.Generator)-1]
+
+	cpuStyle := styles.Value
+	if last.CPUPercent >= 85 {
+		cpuStyle = styles.Error
+	}
+	fds := "n/a"
+	if last.OpenFDs >= 0 {
+		fds = fmt.Sprintf("%d", last.OpenFDs)
+		if last.FDLimit > 0 {
+			fds = fmt.Sprintf("%d/%d", last.OpenFDs, last.FDLimit)
+		}
+	}
+	row := m.cardRow(
+		card{"Gen CPU", cpuStyle.Render(fmt.Sprintf("%.1f%%", last.CPUPercent))},
+		card{"Gen Memory", styles.Text.Render(fmt.Sprintf("%.0f MB", last.MemMB))},
+		card{"Goroutines", styles.Text.Render(fmt.Sprintf("%d", last.Goroutines))},
+		card{"GC Pause/s", styles.Text.Render(fmt.Sprintf("%.1f ms", last.GCPauseMs))},
+		card{"Open FDs", styles.Text.Render(fds)},
+	)
+
+	warnings := last.Warnings()
+	if m.Config.Mode != "users" && m.Stats.AvgQueueWaitMs >= 10 {
+		warnings = append(warnings, fmt.Sprintf("requests start %.0fms late on average", m.Stats.AvgQueueWaitMs))
+	}
+	if len(warnings) > 0 {
+		row += "\n" + styles.Warn.Render("⚠ Generator may be the bottleneck: "+strings.Join(warnings, ", "))
+	}
+	if n := m.Stats.ResultsDropped; n > 0 {
+		row += "\n" + styles.Warn.Render(fmt.Sprintf("⚠ Result buffer full: %d oldest results dropped, the exports keep the last %d (--max-results)", n, m.Stats.ResultsKept))
+	}
+	return row
+}
+
+// panelHint shows the toggle keys, hidden panels dimmed
+func (m DashboardView) panelHint() string {
+	var parts []string
+	for _, pk := range panelKeys {
+		label := fmt.Sprintf("[%s] %s", pk.Key, pk.Panel)
+		if m.Collapsed[pk.Panel] {
+			parts = append(parts, styles.Subtle.Strikethrough(true).Render(label))
+		} else {
+			parts = append(parts, styles.Subtle.Render(label))
+		}
+	}
+	if m.anyCollapsed() {
+		parts = append(parts, styles.Subtle.Render("[a] all"))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// errorsContent lists failure signatures with the latest response body of the selected one
+func (m DashboardView) errorsContent() string {
+	s := strings.Builder{}
+	s.WriteString(styles.Title.Render("Error Details"))
+	s.WriteString("\n")
+	s.WriteString(styles.Subtle.Render("↑/↓ select  •  e/esc close"))
+	s.WriteString("\n\n")
+
+	failures := m.Stats.Failures
+	if len(failures) == 0 {
+		s.WriteString(styles.Subtle.Render("No failures recorded."))
+		return styles.Panel.Width(m.Width - 6).Render(s.String())
+	}
+
+	sel := min(max(m.ErrorSel, 0), len(failures)-1)
+	for i, f := range failures {
+		code := fmt.Sprintf("%d", f.Status)
+		if f.Status == 0 {
+			code = "ERR"
+		}
+		msg := f.Message
+		if len(msg) > 70 {
+			msg = msg[:67] + "..."
+		}
+		line := fmt.Sprintf("%6d x  %3s  %s", f.Count, code, msg)
+		if i == sel {
+			s.WriteString(styles.Active.Render("> " + line))
+		} else {
+			s.WriteString(styles.Text.Render("  " + line))
+		}
+		s.WriteString("\n")
+	}
+
+	f := failures[sel]
+	s.WriteString("\n")
+	s.WriteString(styles.Subtle.Render(fmt.Sprintf("Most recent response body (last seen %s)", f.LastSeen.Format("15:04:05"))))
+	s.WriteString("\n")
+	body := strings.TrimSpace(f.LastBody)
+	if body == "" {
+		body = styles.Subtle.Render("(no body captured)")
+	} else {
+		// Keep it readable: cap lines and width
+		lines := strings.Split(strings.ReplaceAll(body, "\r", ""), "\n")
+		if len(lines) > 15 {
+			lines = append(lines[:15], "...")
+		}
+		for i, l := range lines {
+			if len(l) > m.Width-14 && m.Width > 20 {
+				lines[i] = l[:m.Width-17] + "..."
+			}
+		}
+		body = styles.Warn.Render(strings.Join(lines, "\n"))
+	}
+	s.WriteString(styles.Box.Render(body))
+
+	return styles.Panel.Width(m.Width - 6).Render(s.String())
+}
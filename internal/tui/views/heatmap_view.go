@@ -0,0 +1,125 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"steadyq/internal/runner"
+	"steadyq/internal/stats"
+	"steadyq/internal/tui/styles"
+)
+
+// HeatmapView draws service time over time: one column per second,
+// one row per latency bucket, shade = share of that second's requests.
+type HeatmapView struct {
+	Width  int
+	Height int
+}
+
+func NewHeatmapView(width, height int) HeatmapView {
+	return HeatmapView{Width: width, Height: height}
+}
+
+// Shades from empty to hottest, readable without color too
+var heatShades = []string{" ", "░", "▒", "▓", "█"}
+
+func heatStyles() []lipgloss.Style {
+	return []lipgloss.Style{
+		styles.Subtle,
+		styles.Subtle,
+		styles.Value,
+		styles.Warn,
+		styles.Error,
+	}
+}
+
+func (m HeatmapView) View(snap runner.StatsSnapshot) string {
+	s := strings.Builder{}
+	s.WriteString(styles.Title.Render("Service Time Heatmap"))
+	s.WriteString("\n\n")
+
+	labels := heatmapLabels()
+	labelWidth := 0
+	for _, l := range labels {
+		labelWidth = max(labelWidth, len(l))
+	}
+
+	// App panel border+padding and the label gutter
+	cols := m.Width - labelWidth - 10
+	if cols < 10 {
+		cols = 10
+	}
+	data := snap.Heatmap
+	if len(data) > cols {
+		data = data[len(data)-cols:]
+	}
+
+	if len(data) == 0 {
+		s.WriteString(styles.Subtle.Render("No data yet. Columns appear once per second while a run is active."))
+		return s.String()
+	}
+
+	// Normalize per column so mode shifts stand out regardless of throughput
+	shadeStyles := heatStyles()
+	rows := len(labels)
+	for row := rows - 1; row >= 0; row-- {
+		line := strings.Builder{}
+		line.WriteString(styles.Subtle.Render(fmt.Sprintf("%*s │", labelWidth, labels[row])))
+		for _, col := range data {
+			var total int64
+			for _, c := range col {
+				total += c
+			}
+			level := 0
+			if total > 0 && col[row] > 0 {
+				steps := len(heatShades) - 1
+				level = 1 + min(steps-1, int(float64(col[row])/float64(total)*float64(steps)))
+			}
+			line.WriteString(shadeStyles[level].Render(heatShades[level]))
+		}
+		s.WriteString(line.String())
+		s.WriteString("\n")
+	}
+
+	// X axis
+	s.WriteString(strings.Repeat(" ", labelWidth+1) + "└" + strings.Repeat("─", len(data)))
+	s.WriteString("\n")
+	axisLeft := fmt.Sprintf("-%ds", len(data))
+	gap := max(1, len(data)-len(axisLeft)-3)
+	s.WriteString(strings.Repeat(" ", labelWidth+2) + styles.Subtle.Render(axisLeft+strings.Repeat(" ", gap)+"now"))
+	s.WriteString("\n\n")
+
+	legend := []string{}
+	steps := len(heatShades) - 1
+	for i := 1; i <= steps; i++ {
+		label := fmt.Sprintf(" >=%d%%", (i-1)*100/steps)
+		if i == 1 {
+			label = " >0%"
+		}
+		legend = append(legend, shadeStyles[i].Render(heatShades[i])+styles.Subtle.Render(label))
+	}
+	s.WriteString(styles.Subtle.Render("Share of each second's requests: ") + strings.Join(legend, "  "))
+
+	return s.String()
+}
+
+// heatmapLabels names each bucket row, lowest latency first
+func heatmapLabels() []string {
+	b := stats.HeatmapBoundsMs
+	labels := make([]string, 0, len(b)+1)
+	labels = append(labels, "<"+fmtBoundMs(b[0]))
+	for i := 1; i < len(b); i++ {
+		labels = append(labels, fmtBoundMs(b[i-1])+"-"+fmtBoundMs(b[i]))
+	}
+	labels = append(labels, ">="+fmtBoundMs(b[len(b)-1]))
+	return labels
+}
+
+func fmtBoundMs(ms int64) string {
+	if ms >= 1000 {
+		return fmt.Sprintf("%ds", ms/1000)
+	}
+	return fmt.Sprintf("%dms", ms)
+}
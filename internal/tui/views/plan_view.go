@@ -0,0 +1,227 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"steadyq/internal/plan"
+	"steadyq/internal/tui/styles"
+)
+
+// Plan Dialog Modes
+const (
+	PlanDialogSave = "save"
+	PlanDialogLoad = "load"
+	PlanDialogPath = "path" // Load a plan file, run directory or bundle by path
+)
+
+// PlanSaveMsg is emitted when the user confirms a plan name
+type PlanSaveMsg struct{ Name string }
+
+// PlanLoadMsg is emitted when the user picks a plan, or enters the path of a
+// plan file, run directory or bundle
+type PlanLoadMsg struct{ Path string }
+
+// PlanDialog is a small overlay to name a plan (save) or pick one (load, or
+// by path)
+type PlanDialog struct {
+	Active bool
+	Mode   string
+
+	Input  textinput.Model
+	Plans  []plan.Info
+	Cursor int
+	Err    error
+	Notice string
+
+	// Plan a first d asked to delete; a second d (or y) on it deletes it,
+	// any other key cancels
+	confirmDelete string
+}
+
+func NewPlanDialog() PlanDialog {
+	ti := textinput.New()
+	ti.Placeholder = "checkout-smoke"
+	ti.Prompt = "Plan Name: "
+	ti.PromptStyle = styles.Active
+	ti.TextStyle = styles.Text
+	ti.Width = 30
+	return PlanDialog{Input: ti}
+}
+
+// OpenSave shows the name prompt
+func (d PlanDialog) OpenSave() (PlanDialog, tea.Cmd) {
+	d.Active = true
+	d.Mode = PlanDialogSave
+	d.Err = nil
+	d.Input.Prompt, d.Input.Placeholder = "Plan Name: ", "checkout-smoke"
+	d.Input.SetValue("")
+	return d, d.Input.Focus()
+}
+
+// openPath prompts for the path of a plan file, run directory or bundle
+func (d PlanDialog) openPath() (PlanDialog, tea.Cmd) {
+	d.Mode = PlanDialogPath
+	d.Err = nil
+	d.Input.Prompt, d.Input.Placeholder = "Path: ", "runs/<run ID>, bundle.zip or plan.yaml"
+	d.Input.SetValue("")
+	return d, d.Input.Focus()
+}
+
+// OpenLoad lists saved plans
+func (d PlanDialog) OpenLoad() PlanDialog {
+	d.Active = true
+	d.Mode = PlanDialogLoad
+	d.Cursor = 0
+	d.Notice = ""
+	d.confirmDelete = ""
+	d.Plans, d.Err = plan.List()
+	return d
+}
+
+func (d PlanDialog) Update(msg tea.Msg) (PlanDialog, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		var cmd tea.Cmd
+		if d.Mode == PlanDialogSave {
+			d.Input, cmd = d.Input.Update(msg)
+		}
+		return d, cmd
+	}
+
+	switch key.String() {
+	case "esc":
+		d.Active = false
+		d.Input.Blur()
+		return d, nil
+	case "enter":
+		d.Active = false
+		d.Input.Blur()
+		if d.Mode == PlanDialogSave {
+			name := strings.TrimSpace(d.Input.Value())
+			if name == "" {
+				return d, nil
+			}
+			return d, func() tea.Msg { return PlanSaveMsg{Name: name} }
+		}
+		if d.Mode == PlanDialogPath {
+			path := strings.TrimSpace(d.Input.Value())
+			if path == "" {
+				return d, nil
+			}
+			return d, func() tea.Msg { return PlanLoadMsg{Path: path} }
+		}
+		if len(d.Plans) == 0 {
+			return d, nil
+		}
+		path := d.Plans[d.Cursor].Path
+		return d, func() tea.Msg { return PlanLoadMsg{Path: path} }
+	}
+
+	if d.Mode == PlanDialogLoad {
+		pending := d.confirmDelete
+		d.confirmDelete = ""
+		if pending != "" {
+			d.Notice = ""
+		}
+		switch key.String() {
+		case "p":
+			return d.openPath()
+		case "up", "k":
+			if d.Cursor > 0 {
+				d.Cursor--
+			}
+		case "down", "j":
+			if d.Cursor < len(d.Plans)-1 {
+				d.Cursor++
+			}
+		case "d", "y", "a":
+			if len(d.Plans) == 0 {
+				return d, nil
+			}
+			p := d.Plans[d.Cursor]
+			if key.String() != "a" && pending != p.Path {
+				if key.String() == "d" {
+					d.confirmDelete = p.Path
+					d.Notice = fmt.Sprintf("Delete '%s' for good? [d/y] Delete  [any other key] Keep  ([a] archives instead)", p.Name)
+				}
+				return d, nil
+			}
+			if key.String() != "a" {
+				d.Err = plan.Delete(p.Path)
+				d.Notice = fmt.Sprintf("Deleted '%s'", p.Name)
+			} else {
+				var dst string
+				dst, d.Err = plan.Archive(p.Path)
+				d.Notice = fmt.Sprintf("Archived '%s' to %s", p.Name, dst)
+			}
+			if d.Err != nil {
+				d.Notice = ""
+				return d, nil
+			}
+			d.Plans, d.Err = plan.List()
+			d.Cursor = min(d.Cursor, max(0, len(d.Plans)-1))
+		}
+		return d, nil
+	}
+
+	var cmd tea.Cmd
+	d.Input, cmd = d.Input.Update(msg)
+	return d, cmd
+}
+
+func (d PlanDialog) View() string {
+	s := strings.Builder{}
+
+	if d.Mode == PlanDialogPath {
+		s.WriteString(styles.Active.Render("Load from Path"))
+		s.WriteString("\n\n")
+		s.WriteString(d.Input.View())
+		s.WriteString("\n\n")
+		s.WriteString(styles.Subtle.Render("A plan file, a run directory (its config.json) or a bundle (its plan.json)"))
+		s.WriteString("\n")
+		s.WriteString(styles.Subtle.Render("[Enter] Load  [Esc] Cancel"))
+		return styles.Box.BorderForeground(styles.ColorPrimary).Padding(1, 2).Render(s.String())
+	}
+
+	if d.Mode == PlanDialogSave {
+		s.WriteString(styles.Active.Render("Save Plan"))
+		s.WriteString("\n\n")
+		s.WriteString(d.Input.View())
+		s.WriteString("\n\n")
+		s.WriteString(styles.Subtle.Render("Saved to " + plan.Dir()))
+		s.WriteString("\n")
+		s.WriteString(styles.Subtle.Render("[Enter] Save  [Esc] Cancel"))
+		return styles.Box.BorderForeground(styles.ColorPrimary).Padding(1, 2).Render(s.String())
+	}
+
+	s.WriteString(styles.Active.Render("Load Plan"))
+	s.WriteString("\n\n")
+	if d.Err != nil {
+		s.WriteString(styles.Error.Render(fmt.Sprintf("Plan error: %v", d.Err)))
+		s.WriteString("\n")
+	} else if len(d.Plans) == 0 {
+		s.WriteString(styles.Subtle.Render("No saved plans in " + plan.Dir()))
+		s.WriteString("\n")
+	}
+	for i, p := range d.Plans {
+		line := fmt.Sprintf("%-30s %s", p.Name, p.ModTime.Format("2006-01-02 15:04"))
+		if i == d.Cursor {
+			s.WriteString(styles.Active.Render("> " + line))
+		} else {
+			s.WriteString(styles.Text.Render("  " + line))
+		}
+		s.WriteString("\n")
+	}
+	if d.Notice != "" {
+		s.WriteString("\n")
+		s.WriteString(styles.Warn.Render(d.Notice))
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+	s.WriteString(styles.Subtle.Render("[↑/↓] Select  [Enter] Load  [p] Path  [d][d] Delete  [a] Archive  [Esc] Cancel"))
+	return styles.Box.BorderForeground(styles.ColorPrimary).Padding(1, 2).Render(s.String())
+}
@@ -0,0 +1,169 @@
+package views
+
+import (
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"steadyq/internal/runner"
+)
+
+// Validate checks the visible fields and returns an error message per offending field.
+// An empty map means the config is safe to launch.
+func (m RunnerView) Validate() map[int]string {
+	errs := make(map[int]string)
+	reqType := m.Inputs[FieldReqType].Value()
+	loadMode := m.Inputs[FieldLoadMode].Value()
+
+	switch {
+	case reqType == "http":
+		if msg := validateHTTPURL(m.Inputs[FieldURL].Value()); msg != "" {
+			errs[FieldURL] = msg
+		}
+		if strings.TrimSpace(m.Inputs[FieldMethod].Value()) == "" {
+			errs[FieldMethod] = "method is required"
+		}
+		if msg := validateHeaders(m.Headers.Value()); msg != "" {
+			errs[FieldHeaders] = msg
+		}
+	case reqType == "script":
+		if strings.TrimSpace(m.Inputs[FieldCommand].Value()) == "" {
+			errs[FieldCommand] = "command is required"
+		}
+	case isSocket(reqType):
+		addr := m.Inputs[FieldURL].Value()
+		if idx := strings.Index(addr, "://"); idx != -1 {
+			addr = addr[idx+3:]
+		}
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			errs[FieldURL] = "expected host:port"
+		}
+	default:
+		if strings.TrimSpace(m.Inputs[FieldURL].Value()) == "" {
+			errs[FieldURL] = "URL is required"
+		}
+	}
+
+	label := "RPS"
+	if loadMode == "users" {
+		label = "users"
+	}
+	if n, err := strconv.Atoi(m.Inputs[FieldRPS].Value()); err != nil || n <= 0 {
+		errs[FieldRPS] = label + " must be a number > 0"
+	}
+	if n, err := strconv.Atoi(m.Inputs[FieldDuration].Value()); err != nil || n <= 0 {
+		errs[FieldDuration] = "duration must be a number > 0"
+	}
+	for _, f := range []int{FieldRampUp, FieldRampDown, FieldThinkTime} {
+		if msg := validateOptionalInt(m.Inputs[f].Value()); msg != "" {
+			errs[f] = msg
+		}
+	}
+
+	// Advanced
+	for _, f := range []int{FieldConnectTimeout, FieldTLSTimeout, FieldHeaderTimeout, FieldRequestTimeout, FieldGracefulStop, FieldAbortP99} {
+		if v := strings.TrimSpace(m.Inputs[f].Value()); v != "" {
+			if d, err := time.ParseDuration(v); err != nil || d < 0 {
+				errs[f] = "expected a duration like 5s or 500ms"
+			}
+		}
+	}
+	if msg := validateOptionalInt(m.Inputs[FieldMaxConns].Value()); msg != "" {
+		errs[FieldMaxConns] = msg
+	}
+	if v := strings.TrimSpace(m.Inputs[FieldBreakerRate].Value()); v != "" {
+		if r, err := strconv.ParseFloat(v, 64); err != nil || r < 0 || r > 1 {
+			errs[FieldBreakerRate] = "expected a ratio between 0 and 1"
+		}
+	}
+	if v := strings.TrimSpace(m.Inputs[FieldAbortErrorRate].Value()); v != "" {
+		if r, err := strconv.ParseFloat(v, 64); err != nil || r < 0 || r > 100 {
+			errs[FieldAbortErrorRate] = "expected a percent between 0 and 100"
+		}
+	}
+	if _, err := runner.ParseThresholds(splitList(m.Inputs[FieldThresholds].Value())); err != nil {
+		errs[FieldThresholds] = "expected targets like p99<500ms, error_rate<1%"
+	}
+	if !slices.Contains(runner.PacingStrategies, m.Inputs[FieldPacing].Value()) {
+		errs[FieldPacing] = "press Space to pick a strategy"
+	}
+	if _, err := runner.ParseBandwidth(m.Inputs[FieldBandwidth].Value()); err != nil {
+		errs[FieldBandwidth] = "expected a rate like 50Mbit or 2MB/s"
+	}
+	if _, err := runner.ParseNetworkProfile(m.Inputs[FieldNetwork].Value()); err != nil {
+		errs[FieldNetwork] = "expected 3g, adsl, ... or latency=,jitter=,down=,up="
+	}
+	if v := strings.TrimSpace(m.Inputs[FieldBodyDir].Value()); v != "" {
+		if _, err := runner.CheckBodyDir(v, m.BodyWeights); err != nil {
+			errs[FieldBodyDir] = err.Error()
+		}
+	}
+	if v := strings.TrimSpace(m.Inputs[FieldCAFile].Value()); v != "" && m.Inputs[FieldInsecure].Value() != "on" {
+		if _, err := runner.LoadCAFile(v); err != nil {
+			errs[FieldCAFile] = err.Error()
+		}
+	}
+	if _, err := runner.ParseSuccessCodes(m.Inputs[FieldSuccessCodes].Value()); err != nil {
+		errs[FieldSuccessCodes] = "expected codes like 200,404 or 200-299 or 2xx"
+	}
+	if v := strings.TrimSpace(m.Inputs[FieldMonitor].Value()); v != "" {
+		if !strings.HasPrefix(v, "http://") && !strings.HasPrefix(v, "https://") && !strings.HasPrefix(v, "ssh://") {
+			errs[FieldMonitor] = "expected http(s)://host:9100/metrics or ssh://user@host"
+		}
+	}
+
+	return errs
+}
+
+func validateHTTPURL(raw string) string {
+	if strings.TrimSpace(raw) == "" {
+		return "URL is required"
+	}
+	if strings.Contains(raw, "{{") {
+		// Templated URLs are only known at request time
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "invalid URL"
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "URL must start with http:// or https://"
+	}
+	if u.Host == "" {
+		return "URL is missing a host"
+	}
+	return ""
+}
+
+func validateHeaders(raw string) string {
+	for i, l := range strings.Split(raw, "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(l, ":")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" || strings.ContainsAny(k, " \t") {
+			return "line " + strconv.Itoa(i+1) + ": expected \"Key: Value\""
+		}
+		if err := runner.CheckSecretRefs(v); err != nil {
+			return "line " + strconv.Itoa(i+1) + ": " + err.Error()
+		}
+	}
+	return ""
+}
+
+func validateOptionalInt(v string) string {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return ""
+	}
+	if n, err := strconv.Atoi(v); err != nil || n < 0 {
+		return "must be a number >= 0"
+	}
+	return ""
+}